@@ -9,6 +9,11 @@ import (
 	"Gin/internal/database"
 	"Gin/internal/handlers"
 	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
+	"Gin/internal/repository/memory"
+	"Gin/internal/repository/postgres"
 	"Gin/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -21,7 +26,7 @@ func init() {
 	}
 }
 
-func setupRouter(db *database.DB) *gin.Engine {
+func setupRouter(store repository.Store) *gin.Engine {
 	// Crear router de Gin
 	r := gin.Default()
 
@@ -33,17 +38,34 @@ func setupRouter(db *database.DB) *gin.Engine {
 	r.Static("/static", "./static")
 
 	// Inicializar handlers
-	pageHandler := handlers.NewPageHandler(db)
-	roadmapHandler := handlers.NewRoadmapHandler()
-	nodeHandler := handlers.NewNodeHandler(db.GetDB())
-	connectionHandler := handlers.NewConnectionHandler(db.GetDB())
-	resourceHandler := handlers.NewResourceHandler(db.GetDB())
+	hub := realtime.NewHub()
+	pageHandler := handlers.NewPageHandler(store)
+	roadmapHandler := handlers.NewRoadmapHandler(store, hub)
+	nodeHandler := handlers.NewNodeHandler(store, hub)
+	connectionHandler := handlers.NewConnectionHandler(store, hub)
+	resourceHandler := handlers.NewResourceHandler(store)
+	proposalHandler := handlers.NewProposalHandler(store, hub)
+	liveHandler := handlers.NewLiveHandler(store, hub)
+	collaboratorHandler := handlers.NewCollaboratorHandler(store, hub)
+	organizationHandler := handlers.NewOrganizationHandler(store, hub)
+	assignmentHandler := handlers.NewAssignmentHandler(store)
 
 	// Inicializar servicios
 	jwtService := services.NewJWTService()
 	googleAuthService := services.NewGoogleAuthService()
 	authMiddleware := middleware.NewAuthMiddleware(jwtService)
-	ownerMiddleware := middleware.RequireRoadmapOwner(db.GetDB())
+	ownerMiddleware := middleware.RequireRoadmapOwner(store)
+
+	// Permisos sobre un roadmap según el rol del usuario
+	canView := middleware.RequireRoadmapRole(store, models.RoleViewer)
+	canComment := middleware.RequireRoadmapRole(store, models.RoleCommenter)
+	canEdit := middleware.RequireRoadmapRole(store, models.RoleEditor)
+	canAdmin := middleware.RequireRoadmapRole(store, models.RoleAdmin)
+
+	// Permisos sobre una organización según el rol del miembro
+	orgMember := middleware.RequireOrganizationRole(store, models.OrgRoleMember)
+	orgAdmin := middleware.RequireOrganizationRole(store, models.OrgRoleAdmin)
+	orgOwner := middleware.RequireOrganizationRole(store, models.OrgRoleOwner)
 
 	// Rutas de páginas
 	r.GET("/", pageHandler.Home)
@@ -52,7 +74,7 @@ func setupRouter(db *database.DB) *gin.Engine {
 	r.GET("/explore", pageHandler.Explore)
 
 	// Rutas de autenticación
-	authHandler := handlers.NewAuthHandler(db, jwtService, googleAuthService)
+	authHandler := handlers.NewAuthHandler(store, jwtService, googleAuthService)
 	auth := r.Group("/auth")
 	{
 		auth.POST("/register", authHandler.Register)
@@ -67,24 +89,28 @@ func setupRouter(db *database.DB) *gin.Engine {
 	{
 		roadmaps.GET("/", roadmapHandler.ListRoadmaps)
 		roadmaps.GET("/explore", roadmapHandler.ListRoadmaps)
-		roadmaps.POST("/create", roadmapHandler.CreateRoadmap)
-		
+		roadmaps.POST("/create", authMiddleware.RequireAuth(), roadmapHandler.CreateRoadmap)
+		roadmaps.POST("/import", authMiddleware.RequireAuth(), roadmapHandler.ImportRoadmap)
+
 		roadmap := roadmaps.Group("/:id")
 		{
-			roadmap.GET("", roadmapHandler.ViewRoadmap)
-			roadmap.PUT("", roadmapHandler.UpdateRoadmap)
-			roadmap.DELETE("", roadmapHandler.DeleteRoadmap)
-			roadmap.POST("/fork", roadmapHandler.ForkRoadmap)
+			roadmap.GET("", authMiddleware.OptionalAuth(), roadmapHandler.ViewRoadmap)
+			roadmap.GET("/plan", authMiddleware.OptionalAuth(), roadmapHandler.ViewStudyPlan)
+			roadmap.GET("/image.svg", authMiddleware.OptionalAuth(), roadmapHandler.RoadmapImage)
+			roadmap.PUT("", authMiddleware.RequireAuth(), canEdit, roadmapHandler.UpdateRoadmap)
+			roadmap.DELETE("", authMiddleware.RequireAuth(), ownerMiddleware, roadmapHandler.DeleteRoadmap)
+			roadmap.POST("/fork", authMiddleware.RequireAuth(), roadmapHandler.ForkRoadmap)
+			roadmap.GET("/forks", authMiddleware.OptionalAuth(), roadmapHandler.ListForks)
 			roadmap.GET("/reviews", roadmapHandler.GetRoadmapReviews)
-			roadmap.POST("/reviews", roadmapHandler.AddReview)
-			roadmap.GET("/editor", authMiddleware.RequireAuth(), ownerMiddleware, pageHandler.RoadmapEditor)
-			
+			roadmap.POST("/reviews", authMiddleware.RequireAuth(), canComment, roadmapHandler.AddReview)
+			roadmap.GET("/editor", authMiddleware.RequireAuth(), canEdit, pageHandler.RoadmapEditor)
+
 			// Rutas de nodos
 			nodes := roadmap.Group("/nodes")
 			{
 				nodes.GET("/node/:node_id/resources", roadmapHandler.GetNodeResources)
-				nodes.POST("/node/:node_id/complete", roadmapHandler.CompleteNode)
-				nodes.POST("/node/:node_id/progress", roadmapHandler.UpdateProgress)
+				nodes.POST("/node/:node_id/complete", authMiddleware.RequireAuth(), roadmapHandler.CompleteNode)
+				nodes.POST("/node/:node_id/progress", authMiddleware.RequireAuth(), roadmapHandler.UpdateProgress)
 			}
 		}
 	}
@@ -95,44 +121,167 @@ func setupRouter(db *database.DB) *gin.Engine {
 		// Rutas públicas
 		api.GET("/health", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{
-				"status": "ok",
+				"status":  "ok",
 				"message": "El servidor está funcionando correctamente",
 			})
 		})
 
-		// Rutas del editor de roadmaps (protegidas)
-		apiRoadmaps := api.Group("/roadmaps/:id", authMiddleware.RequireAuth(), ownerMiddleware)
+		// Consultas de solo lectura que también sirven a los roadmaps públicos;
+		// cada handler comprueba que el usuario, si lo hay, pueda verlo
+		publicRoadmaps := api.Group("/roadmaps/:id", authMiddleware.OptionalAuth())
+		{
+			publicRoadmaps.GET("/layout", roadmapHandler.GetLayout)
+			publicRoadmaps.GET("/export", roadmapHandler.ExportRoadmap)
+		}
+
+		// Rutas del editor de roadmaps (protegidas según el rol del usuario)
+		apiRoadmaps := api.Group("/roadmaps/:id", authMiddleware.RequireAuth())
 		{
+			// Estado completo del editor
+			apiRoadmaps.GET("", canView, roadmapHandler.GetRoadmapGraph)
+			apiRoadmaps.PUT("", canEdit, roadmapHandler.SaveRoadmapGraph)
+			apiRoadmaps.GET("/validate", canView, roadmapHandler.ValidateRoadmap)
+			apiRoadmaps.GET("/lint", canView, roadmapHandler.LintRoadmap)
+			apiRoadmaps.GET("/text", canView, roadmapHandler.GetRoadmapText)
+			apiRoadmaps.PUT("/text", canEdit, roadmapHandler.ApplyRoadmapText)
+
+			// Progreso, plan de estudio y caminos del usuario autenticado; basta con
+			// poder ver el roadmap
+			apiRoadmaps.GET("/progress", roadmapHandler.GetProgress)
+			apiRoadmaps.GET("/progress/unlocked", roadmapHandler.GetUnlockedNodes)
+			apiRoadmaps.GET("/study-plan", roadmapHandler.GetStudyPlan)
+			apiRoadmaps.GET("/path/:node_id", roadmapHandler.GetLearningPath)
+
+			// Edición colaborativa en tiempo real
+			apiRoadmaps.GET("/live", canView, liveHandler.Stream)
+			apiRoadmaps.GET("/live/presence", canView, liveHandler.ListPresence)
+			apiRoadmaps.POST("/live/presence", canView, liveHandler.UpdatePresence)
+
+			// Colaboradores; cada colaborador puede eliminarse a sí mismo
+			apiRoadmaps.GET("/collaborators", canView, collaboratorHandler.ListCollaborators)
+			apiRoadmaps.POST("/collaborators", canAdmin, collaboratorHandler.InviteCollaborator)
+			apiRoadmaps.PUT("/collaborators/:collaborator_id", canAdmin, collaboratorHandler.UpdateCollaborator)
+			apiRoadmaps.DELETE("/collaborators/:collaborator_id", canView, collaboratorHandler.RemoveCollaborator)
+
+			// Asignaciones del roadmap a grupos de usuarios
+			apiRoadmaps.GET("/assignments", canAdmin, assignmentHandler.ListAssignments)
+			apiRoadmaps.POST("/assignments", canAdmin, assignmentHandler.CreateAssignment)
+			apiRoadmaps.GET("/assignments/:assignment_id", canAdmin, assignmentHandler.GetAssignment)
+			apiRoadmaps.PUT("/assignments/:assignment_id", canAdmin, assignmentHandler.UpdateAssignment)
+			apiRoadmaps.DELETE("/assignments/:assignment_id", canAdmin, assignmentHandler.DeleteAssignment)
+			apiRoadmaps.GET("/assignments/:assignment_id/export", canAdmin, assignmentHandler.ExportAssignment)
+			apiRoadmaps.POST("/assignments/:assignment_id/assignees", canAdmin, assignmentHandler.AddAssignees)
+			apiRoadmaps.DELETE("/assignments/:assignment_id/assignees/:user_id", canAdmin, assignmentHandler.RemoveAssignee)
+
+			// Organización a la que pertenece el roadmap
+			apiRoadmaps.PUT("/organization", ownerMiddleware, organizationHandler.SetRoadmapOrganization)
+			apiRoadmaps.DELETE("/organization", ownerMiddleware, organizationHandler.RemoveRoadmapOrganization)
+
+			// Sincronización de un fork con su original
+			apiRoadmaps.GET("/sync", canEdit, roadmapHandler.PreviewForkSync)
+			apiRoadmaps.POST("/sync", canEdit, roadmapHandler.SyncFork)
+
+			// Historial de revisiones
+			apiRoadmaps.GET("/revisions", canView, roadmapHandler.ListRevisions)
+			apiRoadmaps.GET("/revisions/diff", canView, roadmapHandler.DiffRevisions)
+			apiRoadmaps.GET("/revisions/:number", canView, roadmapHandler.GetRevision)
+			apiRoadmaps.POST("/revisions/:number/restore", canEdit, roadmapHandler.RestoreRevision)
+
+			// Propuestas recibidas
+			apiRoadmaps.GET("/proposals", canComment, proposalHandler.ListRoadmapProposals)
+
 			// Rutas de nodos
-			apiRoadmaps.POST("/nodes", nodeHandler.CreateNode)
-			apiRoadmaps.PUT("/nodes/:node_id", nodeHandler.UpdateNode)
-			apiRoadmaps.DELETE("/nodes/:node_id", nodeHandler.DeleteNode)
-			apiRoadmaps.PUT("/nodes/positions", nodeHandler.UpdateNodePositions)
+			apiRoadmaps.POST("/nodes", canEdit, nodeHandler.CreateNode)
+			apiRoadmaps.GET("/nodes/:node_id", canView, nodeHandler.GetNode)
+			apiRoadmaps.PUT("/nodes/:node_id", canEdit, nodeHandler.UpdateNode)
+			apiRoadmaps.DELETE("/nodes/:node_id", canEdit, nodeHandler.DeleteNode)
+			apiRoadmaps.PUT("/nodes/positions", canEdit, nodeHandler.UpdateNodePositions)
 
 			// Rutas de conexiones
-			apiRoadmaps.POST("/connections", connectionHandler.CreateConnection)
-			apiRoadmaps.DELETE("/connections/:conn_id", connectionHandler.DeleteConnection)
+			apiRoadmaps.POST("/connections", canEdit, connectionHandler.CreateConnection)
+			apiRoadmaps.DELETE("/connections/:conn_id", canEdit, connectionHandler.DeleteConnection)
 
 			// Rutas de recursos
-			apiRoadmaps.POST("/nodes/:node_id/resources", resourceHandler.AddNodeResource)
+			apiRoadmaps.POST("/nodes/:node_id/resources", canEdit, resourceHandler.AddNodeResource)
+		}
+
+		// Propuestas de cambios de usuarios que no pueden editar el roadmap
+		api.POST("/roadmaps/:id/proposals", authMiddleware.RequireAuth(), proposalHandler.CreateProposal)
+		proposals := api.Group("/proposals", authMiddleware.RequireAuth())
+		{
+			proposals.GET("", proposalHandler.ListMyProposals)
+			proposals.GET("/:proposal_id", proposalHandler.GetProposal)
+			proposals.POST("/:proposal_id/accept", proposalHandler.AcceptProposal)
+			proposals.POST("/:proposal_id/reject", proposalHandler.RejectProposal)
+		}
+
+		// Invitaciones a colaborar recibidas por el usuario
+		invitations := api.Group("/invitations", authMiddleware.RequireAuth())
+		{
+			invitations.GET("", collaboratorHandler.ListInvitations)
+			invitations.POST("/:invitation_id/accept", collaboratorHandler.AcceptInvitation)
+			invitations.POST("/:invitation_id/decline", collaboratorHandler.DeclineInvitation)
+		}
+
+		// Asignaciones recibidas por el usuario
+		api.GET("/assignments", authMiddleware.RequireAuth(), assignmentHandler.ListMyAssignments)
+
+		// Organizaciones del usuario; cada miembro puede abandonarlas
+		organizations := api.Group("/organizations", authMiddleware.RequireAuth())
+		{
+			organizations.GET("", organizationHandler.ListOrganizations)
+			organizations.POST("", organizationHandler.CreateOrganization)
+
+			organization := organizations.Group("/:org_id")
+			{
+				organization.GET("", orgMember, organizationHandler.GetOrganization)
+				organization.PUT("", orgAdmin, organizationHandler.UpdateOrganization)
+				organization.DELETE("", orgOwner, organizationHandler.DeleteOrganization)
+
+				organization.GET("/members", orgMember, organizationHandler.ListMembers)
+				organization.POST("/members", orgAdmin, organizationHandler.AddMember)
+				organization.PUT("/members/:user_id", orgAdmin, organizationHandler.UpdateMember)
+				organization.DELETE("/members/:user_id", orgMember, organizationHandler.RemoveMember)
+
+				organization.GET("/roadmaps", orgMember, organizationHandler.ListRoadmaps)
+				organization.GET("/dashboard", orgAdmin, organizationHandler.Dashboard)
+			}
 		}
 	}
 
 	return r
 }
 
+// newStore crea el almacenamiento indicado por STORAGE_DRIVER: "postgres"
+// (por defecto) o "memory", que no persiste nada entre reinicios
+func newStore() (repository.Store, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "postgres":
+		db, err := database.Connect()
+		if err != nil {
+			return nil, err
+		}
+		return postgres.NewStore(db), nil
+	case "memory":
+		log.Printf("Usando almacenamiento en memoria: los datos se perderán al reiniciar")
+		return memory.NewStore(), nil
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER desconocido: %s", driver)
+	}
+}
+
 func main() {
 	// Configurar modo de Gin basado en variable de entorno
 	if os.Getenv("ENV") != "development" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Inicializar base de datos
-	db, err := database.Connect()
+	// Inicializar almacenamiento
+	store, err := newStore()
 	if err != nil {
-		log.Fatalf("Error al conectar con la base de datos: %v", err)
+		log.Fatalf("Error al inicializar el almacenamiento: %v", err)
 	}
-	defer db.Close()
+	defer store.Close()
 
 	// Obtener puerto del servidor
 	port := os.Getenv("PORT")
@@ -141,13 +290,13 @@ func main() {
 	}
 
 	// Inicializar router
-	router := setupRouter(db)
+	router := setupRouter(store)
 
 	// Iniciar servidor
 	serverAddr := fmt.Sprintf(":%s", port)
 	log.Printf("Servidor iniciando en http://localhost%s", serverAddr)
-	
+
 	if err := router.Run(serverAddr); err != nil {
 		log.Fatalf("Error al iniciar el servidor: %v", err)
 	}
-}
\ No newline at end of file
+}
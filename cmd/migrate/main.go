@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"Gin/internal/database"
+	"github.com/joho/godotenv"
+)
+
+const usage = `Uso: migrate <comando> [argumento]
+
+Comandos:
+  up [n]              aplica las migraciones pendientes (o solo las n siguientes)
+  down [n]            revierte las últimas n migraciones aplicadas (1 por defecto)
+  status              muestra el estado de cada migración
+  baseline <versión>  marca como aplicadas, sin ejecutarlas, las migraciones
+                      hasta <versión> inclusive`
+
+func init() {
+	// Cargar variables de entorno
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: No se pudo cargar el archivo .env: %v", err)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+	arg := 0
+	if len(os.Args) > 2 {
+		n, err := strconv.Atoi(os.Args[2])
+		if err != nil || n < 0 {
+			log.Fatalf("Argumento inválido: %s", os.Args[2])
+		}
+		arg = n
+	}
+
+	db, err := database.Open()
+	if err != nil {
+		log.Fatalf("Error al conectar con la base de datos: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db.GetDB())
+	if err != nil {
+		log.Fatalf("Error al cargar migraciones: %v", err)
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(arg)
+		printMigrations("Aplicada", applied)
+		if err != nil {
+			log.Fatalf("Error al aplicar migraciones: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("No hay migraciones pendientes")
+		}
+
+	case "down":
+		reverted, err := migrator.Down(arg)
+		printMigrations("Revertida", reverted)
+		if err != nil {
+			log.Fatalf("Error al revertir migraciones: %v", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No hay migraciones aplicadas")
+		}
+
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("Error al obtener el estado: %v", err)
+		}
+		for _, s := range status {
+			state := "pendiente"
+			if s.Applied() {
+				state = "aplicada " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-32s %s\n", s.Version, s.Name, state)
+		}
+
+	case "baseline":
+		if len(os.Args) < 3 {
+			log.Fatal("baseline requiere una versión")
+		}
+		marked, err := migrator.Baseline(arg)
+		if err != nil {
+			log.Fatalf("Error al marcar migraciones: %v", err)
+		}
+		printMigrations("Marcada", marked)
+
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func printMigrations(verb string, migrations []database.Migration) {
+	for _, m := range migrations {
+		fmt.Printf("%s: %04d_%s\n", verb, m.Version, m.Name)
+	}
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"log"
 	"os"
 
 	_ "github.com/lib/pq"
@@ -12,7 +13,38 @@ type DB struct {
 	*sql.DB
 }
 
+// Connect abre la conexión y aplica las migraciones pendientes, salvo que
+// DB_AUTO_MIGRATE sea "false"
 func Connect() (*DB, error) {
+	db, err := Open()
+	if err != nil {
+		return nil, err
+	}
+
+	if os.Getenv("DB_AUTO_MIGRATE") == "false" {
+		return db, nil
+	}
+
+	migrator, err := NewMigrator(db.DB)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al cargar migraciones: %v", err)
+	}
+
+	applied, err := migrator.Up(0)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al aplicar migraciones: %v", err)
+	}
+	for _, m := range applied {
+		log.Printf("Migración aplicada: %04d_%s", m.Version, m.Name)
+	}
+
+	return db, nil
+}
+
+// Open abre la conexión con la base de datos sin tocar el esquema
+func Open() (*DB, error) {
 	// Obtener variables de entorno
 	host := os.Getenv("DB_HOST")
 	port := os.Getenv("DB_PORT")
@@ -34,6 +66,7 @@ func Connect() (*DB, error) {
 
 	// Verificar conexión
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("error al verificar conexión: %v", err)
 	}
 
@@ -70,4 +103,4 @@ func (db *DB) Transaction(fn func(*sql.Tx) error) error {
 	}
 
 	return tx.Commit()
-}
\ No newline at end of file
+}
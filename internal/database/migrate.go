@@ -0,0 +1,314 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID identifica el advisory lock que serializa las migraciones
+// cuando varias instancias arrancan a la vez
+const migrationLockID = 727274
+
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	ErrIrreversibleMigration = errors.New("la migración no es reversible")
+	ErrUnknownMigration      = errors.New("versión de migración desconocida")
+)
+
+// Migration es un cambio versionado del esquema
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus describe si una migración está aplicada
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Applied indica si la migración ya se ejecutó
+func (s MigrationStatus) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+// Migrator aplica y revierte las migraciones embebidas en el binario
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator crea un Migrator con las migraciones embebidas
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// loadMigrations lee los ficheros NNNN_nombre.up.sql / NNNN_nombre.down.sql
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, file := range files {
+		match := migrationFileRegex.FindStringSubmatch(path.Base(file))
+		if match == nil {
+			return nil, fmt.Errorf("nombre de migración inválido: %s", file)
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("la versión %d tiene dos nombres: %s y %s", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("la migración %d no tiene fichero up", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// ensureTable crea la tabla de control si no existe
+func (m *Migrator) ensureTable() error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+// applied devuelve las versiones aplicadas y su fecha
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// Status devuelve todas las migraciones conocidas con su estado
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		status[i].Migration = migration
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status[i].AppliedAt = &appliedAt
+		}
+	}
+	return status, nil
+}
+
+// Up aplica hasta steps migraciones pendientes en orden (todas si steps <= 0)
+func (m *Migrator) Up(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if steps > 0 && len(done) == steps {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		ran, err := m.run(migration, true)
+		if err != nil {
+			return done, fmt.Errorf("migración %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			done = append(done, migration)
+		}
+	}
+
+	return done, nil
+}
+
+// Down revierte las últimas steps migraciones aplicadas (1 si steps <= 0)
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return done, fmt.Errorf("migración %04d_%s: %w", migration.Version, migration.Name, ErrIrreversibleMigration)
+		}
+
+		ran, err := m.run(migration, false)
+		if err != nil {
+			return done, fmt.Errorf("migración %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			done = append(done, migration)
+		}
+	}
+
+	return done, nil
+}
+
+// run ejecuta una migración y actualiza schema_migrations en la misma
+// transacción. Devuelve false si otra instancia ya la había ejecutado.
+func (m *Migrator) run(migration Migration, up bool) (bool, error) {
+	ran := false
+	err := (&DB{m.db}).Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+			return err
+		}
+
+		var exists bool
+		err := tx.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)`,
+			migration.Version,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists == up {
+			return nil
+		}
+
+		if up {
+			if _, err := tx.Exec(migration.Up); err != nil {
+				return err
+			}
+			_, err = tx.Exec(
+				`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+				migration.Version, migration.Name,
+			)
+		} else {
+			if _, err := tx.Exec(migration.Down); err != nil {
+				return err
+			}
+			_, err = tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+		}
+		ran = err == nil
+		return err
+	})
+
+	return ran, err
+}
+
+// Baseline marca como aplicadas, sin ejecutarlas, todas las migraciones hasta
+// version inclusive. Sirve para adoptar bases de datos cuyo esquema ya está al
+// día con esas migraciones.
+func (m *Migrator) Baseline(version int) ([]Migration, error) {
+	known := false
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownMigration, version)
+	}
+
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	var marked []Migration
+	err := (&DB{m.db}).Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			result, err := tx.Exec(`
+				INSERT INTO schema_migrations (version, name) VALUES ($1, $2)
+				ON CONFLICT (version) DO NOTHING`,
+				migration.Version, migration.Name,
+			)
+			if err != nil {
+				return err
+			}
+			if n, _ := result.RowsAffected(); n > 0 {
+				marked = append(marked, migration)
+			}
+		}
+		return nil
+	})
+
+	return marked, err
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS user_progress;
+DROP TABLE IF EXISTS node_resources;
+DROP TABLE IF EXISTS node_connections;
+DROP TABLE IF EXISTS roadmap_nodes;
+DROP TABLE IF EXISTS roadmaps;
+DROP TABLE IF EXISTS users;
@@ -1,14 +1,20 @@
--- Crear extensión para UUID si no existe
+-- Esquema base de Cartesia. Las tablas se crean con IF NOT EXISTS para poder
+-- adoptar bases de datos creadas a mano con los antiguos schema.sql; las
+-- diferencias de columnas se resuelven en 0002_reconcile_legacy_schema.
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
 -- Tabla de usuarios
 CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    username VARCHAR(50) NOT NULL UNIQUE,
+    username VARCHAR(30) NOT NULL UNIQUE,
     email VARCHAR(255) NOT NULL UNIQUE,
-    password_hash VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255),
+    google_id VARCHAR(255) UNIQUE,
+    avatar_url TEXT,
+    bio TEXT,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_auth_method CHECK (password_hash IS NOT NULL OR google_id IS NOT NULL)
 );
 
 -- Tabla de roadmaps
@@ -16,8 +22,10 @@ CREATE TABLE IF NOT EXISTS roadmaps (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     title VARCHAR(255) NOT NULL,
     description TEXT,
+    category VARCHAR(50),
     author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    is_public BOOLEAN DEFAULT true,
+    is_public BOOLEAN NOT NULL DEFAULT false,
+    forked_from UUID REFERENCES roadmaps(id) ON DELETE SET NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -28,9 +36,12 @@ CREATE TABLE IF NOT EXISTS roadmap_nodes (
     roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
     title VARCHAR(255) NOT NULL,
     description TEXT,
+    type VARCHAR(50) NOT NULL,
     position_x FLOAT NOT NULL,
     position_y FLOAT NOT NULL,
-    node_type VARCHAR(50) NOT NULL,
+    color VARCHAR(7),
+    status VARCHAR(20) NOT NULL DEFAULT 'not_started',
+    order_index INTEGER,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -41,9 +52,11 @@ CREATE TABLE IF NOT EXISTS node_connections (
     roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
     source_node_id UUID NOT NULL REFERENCES roadmap_nodes(id) ON DELETE CASCADE,
     target_node_id UUID NOT NULL REFERENCES roadmap_nodes(id) ON DELETE CASCADE,
-    connection_type VARCHAR(50) NOT NULL,
+    label VARCHAR(255),
+    type VARCHAR(50) NOT NULL DEFAULT 'default',
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(source_node_id, target_node_id)
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(roadmap_id, source_node_id, target_node_id)
 );
 
 -- Tabla de recursos asociados a nodos
@@ -52,9 +65,10 @@ CREATE TABLE IF NOT EXISTS node_resources (
     node_id UUID NOT NULL REFERENCES roadmap_nodes(id) ON DELETE CASCADE,
     title VARCHAR(255) NOT NULL,
     url TEXT NOT NULL,
-    resource_type VARCHAR(50) NOT NULL,
     description TEXT,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    type VARCHAR(50) NOT NULL DEFAULT 'link',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
 -- Tabla de progreso de usuarios en roadmaps
@@ -62,17 +76,10 @@ CREATE TABLE IF NOT EXISTS user_progress (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     node_id UUID NOT NULL REFERENCES roadmap_nodes(id) ON DELETE CASCADE,
-    status VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'not_started',
     completed_at TIMESTAMP WITH TIME ZONE,
+    notes TEXT,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     UNIQUE(user_id, node_id)
 );
-
--- Índices para mejorar el rendimiento
-CREATE INDEX IF NOT EXISTS idx_roadmaps_author ON roadmaps(author_id);
-CREATE INDEX IF NOT EXISTS idx_nodes_roadmap ON roadmap_nodes(roadmap_id);
-CREATE INDEX IF NOT EXISTS idx_connections_roadmap ON node_connections(roadmap_id);
-CREATE INDEX IF NOT EXISTS idx_resources_node ON node_resources(node_id);
-CREATE INDEX IF NOT EXISTS idx_progress_user ON user_progress(user_id);
-CREATE INDEX IF NOT EXISTS idx_progress_node ON user_progress(node_id);
\ No newline at end of file
@@ -0,0 +1,108 @@
+-- Unifica las bases de datos creadas con los dos antiguos schema.sql:
+--   * /schema.sql usaba roadmaps.user_id y contadores desnormalizados.
+--   * internal/database/schema.sql usaba node_type, connection_type y
+--     resource_type, y exigía password_hash incluso a usuarios de Google.
+-- En una base de datos nueva todas las sentencias son no-ops.
+-- Esta migración no es reversible.
+
+-- Usuarios
+ALTER TABLE users ADD COLUMN IF NOT EXISTS google_id VARCHAR(255) UNIQUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bio TEXT;
+ALTER TABLE users ALTER COLUMN password_hash DROP NOT NULL;
+ALTER TABLE users DROP COLUMN IF EXISTS last_login_at;
+ALTER TABLE users DROP COLUMN IF EXISTS is_active;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS check_auth_method;
+ALTER TABLE users ADD CONSTRAINT check_auth_method
+    CHECK (password_hash IS NOT NULL OR google_id IS NOT NULL);
+
+-- Roadmaps
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'roadmaps' AND column_name = 'user_id') THEN
+        ALTER TABLE roadmaps RENAME COLUMN user_id TO author_id;
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'roadmaps' AND column_name = 'deleted_at') THEN
+        DELETE FROM roadmaps WHERE deleted_at IS NOT NULL;
+    END IF;
+END $$;
+
+ALTER TABLE roadmaps ADD COLUMN IF NOT EXISTS category VARCHAR(50);
+ALTER TABLE roadmaps ADD COLUMN IF NOT EXISTS forked_from UUID REFERENCES roadmaps(id) ON DELETE SET NULL;
+ALTER TABLE roadmaps DROP COLUMN IF EXISTS likes_count;
+ALTER TABLE roadmaps DROP COLUMN IF EXISTS views_count;
+ALTER TABLE roadmaps DROP COLUMN IF EXISTS published_at;
+ALTER TABLE roadmaps DROP COLUMN IF EXISTS deleted_at;
+UPDATE roadmaps SET is_public = false WHERE is_public IS NULL;
+ALTER TABLE roadmaps ALTER COLUMN is_public SET DEFAULT false;
+ALTER TABLE roadmaps ALTER COLUMN is_public SET NOT NULL;
+DROP INDEX IF EXISTS idx_roadmaps_user_id;
+
+-- Nodos
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'roadmap_nodes' AND column_name = 'node_type') THEN
+        ALTER TABLE roadmap_nodes RENAME COLUMN node_type TO type;
+    END IF;
+END $$;
+
+ALTER TABLE roadmap_nodes ADD COLUMN IF NOT EXISTS color VARCHAR(7);
+ALTER TABLE roadmap_nodes ADD COLUMN IF NOT EXISTS status VARCHAR(20);
+ALTER TABLE roadmap_nodes ADD COLUMN IF NOT EXISTS order_index INTEGER;
+UPDATE roadmap_nodes SET status = 'not_started' WHERE status IS NULL OR status = 'pending';
+ALTER TABLE roadmap_nodes ALTER COLUMN status SET DEFAULT 'not_started';
+ALTER TABLE roadmap_nodes ALTER COLUMN status SET NOT NULL;
+
+-- Conexiones
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'node_connections' AND column_name = 'connection_type') THEN
+        ALTER TABLE node_connections RENAME COLUMN connection_type TO type;
+    END IF;
+END $$;
+
+ALTER TABLE node_connections ADD COLUMN IF NOT EXISTS label VARCHAR(255);
+ALTER TABLE node_connections ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+UPDATE node_connections SET type = 'default' WHERE type IS NULL;
+ALTER TABLE node_connections ALTER COLUMN type SET DEFAULT 'default';
+ALTER TABLE node_connections ALTER COLUMN type SET NOT NULL;
+
+-- Recursos
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'node_resources' AND column_name = 'resource_type') THEN
+        ALTER TABLE node_resources RENAME COLUMN resource_type TO type;
+    END IF;
+END $$;
+
+ALTER TABLE node_resources ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+UPDATE node_resources SET type = 'link' WHERE type IS NULL;
+ALTER TABLE node_resources ALTER COLUMN type SET DEFAULT 'link';
+ALTER TABLE node_resources ALTER COLUMN type SET NOT NULL;
+
+-- Progreso
+ALTER TABLE user_progress ADD COLUMN IF NOT EXISTS notes TEXT;
+ALTER TABLE user_progress ALTER COLUMN status SET DEFAULT 'not_started';
+
+-- Índices con los nombres definitivos
+DROP INDEX IF EXISTS idx_roadmaps_author;
+DROP INDEX IF EXISTS idx_nodes_roadmap;
+DROP INDEX IF EXISTS idx_connections_roadmap;
+DROP INDEX IF EXISTS idx_resources_node;
+DROP INDEX IF EXISTS idx_progress_user;
+DROP INDEX IF EXISTS idx_progress_node;
+
+CREATE INDEX IF NOT EXISTS idx_users_google_id ON users(google_id);
+CREATE INDEX IF NOT EXISTS idx_roadmaps_author_id ON roadmaps(author_id);
+CREATE INDEX IF NOT EXISTS idx_roadmaps_category ON roadmaps(category);
+CREATE INDEX IF NOT EXISTS idx_roadmaps_forked_from ON roadmaps(forked_from);
+CREATE INDEX IF NOT EXISTS idx_roadmap_nodes_roadmap_id ON roadmap_nodes(roadmap_id);
+CREATE INDEX IF NOT EXISTS idx_node_connections_roadmap_id ON node_connections(roadmap_id);
+CREATE INDEX IF NOT EXISTS idx_node_resources_node_id ON node_resources(node_id);
+CREATE INDEX IF NOT EXISTS idx_user_progress_user_id ON user_progress(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_progress_node_id ON user_progress(node_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS roadmap_comments;
+DROP TABLE IF EXISTS roadmap_likes;
+DROP TABLE IF EXISTS roadmap_views;
+DROP TABLE IF EXISTS reviews;
@@ -0,0 +1,55 @@
+-- Tabla de reseñas de roadmaps
+CREATE TABLE IF NOT EXISTS reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(roadmap_id, user_id)
+);
+
+-- Tabla de visitas a roadmaps
+CREATE TABLE IF NOT EXISTS roadmap_views (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    viewed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabla de likes de roadmaps
+CREATE TABLE IF NOT EXISTS roadmap_likes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, roadmap_id)
+);
+
+-- Tabla de comentarios en roadmaps
+CREATE TABLE IF NOT EXISTS roadmap_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    parent_id UUID REFERENCES roadmap_comments(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- /schema.sql usaba borrado lógico en los comentarios
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'roadmap_comments' AND column_name = 'deleted_at') THEN
+        DELETE FROM roadmap_comments WHERE deleted_at IS NOT NULL;
+        ALTER TABLE roadmap_comments DROP COLUMN deleted_at;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_reviews_roadmap_id ON reviews(roadmap_id);
+CREATE INDEX IF NOT EXISTS idx_roadmap_views_roadmap_id ON roadmap_views(roadmap_id);
+CREATE INDEX IF NOT EXISTS idx_roadmap_likes_roadmap_id ON roadmap_likes(roadmap_id);
+CREATE INDEX IF NOT EXISTS idx_roadmap_comments_roadmap_id ON roadmap_comments(roadmap_id);
+CREATE INDEX IF NOT EXISTS idx_roadmap_comments_parent_id ON roadmap_comments(parent_id);
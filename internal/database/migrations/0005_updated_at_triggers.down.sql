@@ -0,0 +1,13 @@
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'users', 'roadmaps', 'roadmap_nodes', 'node_connections',
+        'node_resources', 'user_progress', 'reviews', 'roadmap_comments'
+    ] LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS update_%s_updated_at ON %I', t, t);
+    END LOOP;
+END $$;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
@@ -0,0 +1,23 @@
+-- Mantener updated_at al día en cada UPDATE
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'users', 'roadmaps', 'roadmap_nodes', 'node_connections',
+        'node_resources', 'user_progress', 'reviews', 'roadmap_comments'
+    ] LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS update_%s_updated_at ON %I', t, t);
+        EXECUTE format(
+            'CREATE TRIGGER update_%s_updated_at BEFORE UPDATE ON %I
+             FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()', t, t);
+    END LOOP;
+END $$;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_roadmaps_forked_from;
+
+ALTER TABLE node_resources DROP COLUMN IF EXISTS origin_id;
+ALTER TABLE node_connections DROP COLUMN IF EXISTS origin_id;
+ALTER TABLE roadmap_nodes DROP COLUMN IF EXISTS origin_id;
+ALTER TABLE roadmaps DROP COLUMN IF EXISTS forked_at;
@@ -0,0 +1,11 @@
+-- Linaje de los forks: cuándo se bifurcó cada roadmap y de qué elemento del
+-- roadmap original procede cada nodo, conexión y recurso copiado. origin_id no
+-- tiene clave foránea porque el original puede eliminarse después del fork.
+ALTER TABLE roadmaps ADD COLUMN IF NOT EXISTS forked_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE roadmap_nodes ADD COLUMN IF NOT EXISTS origin_id UUID;
+ALTER TABLE node_connections ADD COLUMN IF NOT EXISTS origin_id UUID;
+ALTER TABLE node_resources ADD COLUMN IF NOT EXISTS origin_id UUID;
+
+UPDATE roadmaps SET forked_at = created_at WHERE forked_from IS NOT NULL AND forked_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_roadmaps_forked_from ON roadmaps(forked_from);
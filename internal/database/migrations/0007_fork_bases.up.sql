@@ -0,0 +1,7 @@
+-- Contenido del roadmap original con el que cada fork se sincronizó por última
+-- vez. Es la base común de la fusión a tres bandas con el original.
+CREATE TABLE IF NOT EXISTS roadmap_fork_bases (
+    roadmap_id UUID PRIMARY KEY REFERENCES roadmaps(id) ON DELETE CASCADE,
+    snapshot JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
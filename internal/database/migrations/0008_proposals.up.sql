@@ -0,0 +1,25 @@
+-- Propuestas de cambios de usuarios que no son autores del roadmap. Las
+-- operaciones y los contenidos base y propuesto se guardan como JSON.
+CREATE TABLE IF NOT EXISTS roadmap_proposals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'accepted', 'rejected')),
+    operations JSONB NOT NULL,
+    base JSONB NOT NULL,
+    proposed JSONB NOT NULL,
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_comment TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_roadmap_proposals_roadmap ON roadmap_proposals(roadmap_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_roadmap_proposals_author ON roadmap_proposals(author_id, created_at DESC);
+
+DROP TRIGGER IF EXISTS update_roadmap_proposals_updated_at ON roadmap_proposals;
+CREATE TRIGGER update_roadmap_proposals_updated_at BEFORE UPDATE ON roadmap_proposals
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,13 @@
+-- Historial de revisiones: el contenido completo de cada roadmap tras cada
+-- modificación, numerado de forma consecutiva por roadmap
+CREATE TABLE IF NOT EXISTS roadmap_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(50) NOT NULL,
+    restored_from INTEGER,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(roadmap_id, number)
+);
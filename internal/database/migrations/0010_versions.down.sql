@@ -0,0 +1,2 @@
+ALTER TABLE roadmap_nodes DROP COLUMN IF EXISTS version;
+ALTER TABLE roadmaps DROP COLUMN IF EXISTS version;
@@ -0,0 +1,3 @@
+-- Versiones para el control de concurrencia optimista del editor
+ALTER TABLE roadmaps ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE roadmap_nodes ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS roadmap_collaborators;
@@ -0,0 +1,20 @@
+-- Colaboradores de los roadmaps. El autor no figura aquí: siempre es el
+-- propietario.
+CREATE TABLE IF NOT EXISTS roadmap_collaborators (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('viewer', 'commenter', 'editor', 'admin')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted')),
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(roadmap_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_roadmap_collaborators_user ON roadmap_collaborators(user_id, status);
+
+DROP TRIGGER IF EXISTS update_roadmap_collaborators_updated_at ON roadmap_collaborators;
+CREATE TRIGGER update_roadmap_collaborators_updated_at BEFORE UPDATE ON roadmap_collaborators
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_roadmaps_organization;
+ALTER TABLE roadmaps DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
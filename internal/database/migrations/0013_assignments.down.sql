@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS assignment_assignees;
+DROP TABLE IF EXISTS assignment_nodes;
+DROP TABLE IF EXISTS assignments;
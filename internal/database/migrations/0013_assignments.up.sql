@@ -0,0 +1,34 @@
+-- Asignaciones de roadmaps a grupos de usuarios con fecha límite. Si
+-- all_nodes es falso, la asignación abarca solo los nodos de assignment_nodes.
+CREATE TABLE IF NOT EXISTS assignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    all_nodes BOOLEAN NOT NULL DEFAULT true,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignments_roadmap ON assignments(roadmap_id);
+
+CREATE TABLE IF NOT EXISTS assignment_nodes (
+    assignment_id UUID NOT NULL REFERENCES assignments(id) ON DELETE CASCADE,
+    node_id UUID NOT NULL REFERENCES roadmap_nodes(id) ON DELETE CASCADE,
+    PRIMARY KEY (assignment_id, node_id)
+);
+
+CREATE TABLE IF NOT EXISTS assignment_assignees (
+    assignment_id UUID NOT NULL REFERENCES assignments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (assignment_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_assignees_user ON assignment_assignees(user_id);
+
+DROP TRIGGER IF EXISTS update_assignments_updated_at ON assignments;
+CREATE TRIGGER update_assignments_updated_at BEFORE UPDATE ON assignments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1 @@
+ALTER TABLE roadmap_nodes DROP COLUMN IF EXISTS estimated_hours;
@@ -0,0 +1,4 @@
+-- Estimación opcional del esfuerzo de cada nodo, en horas. Se usa para
+-- calcular el camino más corto hasta un nodo.
+ALTER TABLE roadmap_nodes ADD COLUMN IF NOT EXISTS estimated_hours DOUBLE PRECISION
+    CHECK (estimated_hours IS NULL OR estimated_hours > 0);
@@ -0,0 +1 @@
+ALTER TABLE roadmap_revisions DROP COLUMN IF EXISTS details;
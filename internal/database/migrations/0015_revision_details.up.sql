@@ -0,0 +1,4 @@
+-- Datos generales del roadmap (título, descripción, categoría y visibilidad)
+-- en cada revisión, para que sus cambios también queden en el historial.
+-- Las revisiones anteriores quedan sin ellos.
+ALTER TABLE roadmap_revisions ADD COLUMN IF NOT EXISTS details JSONB;
@@ -0,0 +1,161 @@
+package dsl
+
+import (
+	"strings"
+
+	"Gin/internal/layout"
+	"Gin/internal/models"
+)
+
+// Apply devuelve el contenido de current modificado para que coincida con el
+// texto. Los nodos cuyo título ya existe conservan su ID, posición y estado;
+// las conexiones entre los mismos nodos y los recursos con la misma url en el
+// mismo nodo también conservan su ID. Los nodos nuevos se colocan por capas
+// debajo del contenido existente. Los nodos sin color en el texto toman
+// DefaultColor, salvo los existentes que no tenían otro, que lo conservan tal
+// cual para que aplicar el texto de Print no cambie nada.
+func (r *Roadmap) Apply(current models.Snapshot) models.Snapshot {
+	existing := make(map[string]models.Node, len(current.Nodes))
+	for _, node := range current.Nodes {
+		key := titleKey(node.Title)
+		if _, ok := existing[key]; !ok {
+			existing[key] = node
+		}
+	}
+	ids := make(map[string]models.ID, len(r.Nodes))
+	for _, node := range r.Nodes {
+		if old, ok := existing[titleKey(node.Title)]; ok {
+			ids[titleKey(node.Title)] = old.ID
+		}
+	}
+
+	desired, ids := r.snapshot(ids)
+	kept := make(map[models.ID]models.Node, len(current.Nodes))
+	for _, node := range current.Nodes {
+		if ids[titleKey(node.Title)] == node.ID {
+			kept[node.ID] = node
+		}
+	}
+
+	bottom, hasKept := 0.0, false
+	for i := range desired.Nodes {
+		node := &desired.Nodes[i]
+		old, ok := kept[node.ID]
+		if node.Color == "" {
+			node.Color = DefaultColor
+			if ok && (old.Color == "" || strings.EqualFold(old.Color, DefaultColor)) {
+				node.Color = old.Color
+			}
+		}
+		if !ok {
+			continue
+		}
+		node.Position, node.Status, node.Version = old.Position, old.Status, old.Version
+		bottom, hasKept = max(bottom, old.Position.Y), true
+	}
+	placeNew(&desired, kept, bottom, hasKept)
+
+	connections := make(map[[2]models.ID]models.Connection, len(current.Connections))
+	for _, conn := range current.Connections {
+		connections[[2]models.ID{conn.FromNodeID, conn.ToNodeID}] = conn
+	}
+	for i := range desired.Connections {
+		conn := &desired.Connections[i]
+		if old, ok := connections[[2]models.ID{conn.FromNodeID, conn.ToNodeID}]; ok {
+			conn.ID = old.ID
+		}
+	}
+
+	resources := make(map[[2]string][]models.Resource, len(current.Resources))
+	for _, resource := range current.Resources {
+		key := [2]string{resource.NodeID.String(), resource.URL}
+		resources[key] = append(resources[key], resource)
+	}
+	for i := range desired.Resources {
+		resource := &desired.Resources[i]
+		key := [2]string{resource.NodeID.String(), resource.URL}
+		if olds := resources[key]; len(olds) > 0 {
+			resource.ID, resource.Description = olds[0].ID, olds[0].Description
+			resources[key] = olds[1:]
+		}
+	}
+	return desired
+}
+
+// placeNew coloca los nodos que no estaban en el roadmap. Sin nodos
+// conservados se usa la colocación por capas tal cual; con ellos, los nodos
+// nuevos se desplazan para empezar debajo del nodo más bajo.
+func placeNew(snapshot *models.Snapshot, kept map[models.ID]models.Node, bottom float64, hasKept bool) {
+	positions := layout.Layout(snapshot.Nodes, snapshot.Connections, layout.DefaultOptions).Positions
+
+	top, hasNew := 0.0, false
+	for _, node := range snapshot.Nodes {
+		if _, ok := kept[node.ID]; !ok {
+			if y := positions[node.ID].Y; !hasNew || y < top {
+				top, hasNew = y, true
+			}
+		}
+	}
+
+	shift := 0.0
+	if hasKept {
+		shift = bottom + models.NodeHeight + layout.DefaultOptions.LayerSpacing - top
+	}
+	for i := range snapshot.Nodes {
+		node := &snapshot.Nodes[i]
+		if _, ok := kept[node.ID]; !ok {
+			node.Position = positions[node.ID]
+			node.Position.Y += shift
+		}
+	}
+}
+
+// snapshot construye el contenido del texto. Los nodos usan el ID indicado
+// para su título en ids o uno nuevo; devuelve los IDs de todos los títulos.
+func (r *Roadmap) snapshot(ids map[string]models.ID) (models.Snapshot, map[string]models.ID) {
+	all := make(map[string]models.ID, len(r.Nodes))
+	snapshot := models.Snapshot{
+		Nodes:       make([]models.Node, 0, len(r.Nodes)),
+		Connections: make([]models.Connection, 0, len(r.Connections)),
+		Resources:   make([]models.Resource, 0, len(r.Resources)),
+	}
+
+	for _, n := range r.Nodes {
+		id, ok := ids[titleKey(n.Title)]
+		if !ok {
+			id = models.NewID()
+		}
+		all[titleKey(n.Title)] = id
+		snapshot.Nodes = append(snapshot.Nodes, models.Node{
+			ID:          id,
+			Title:       n.Title,
+			Description: n.Description,
+			Type:        n.Type,
+			Status:      "not_started",
+			Color:       n.Color,
+			OrderIndex:  n.OrderIndex,
+			Estimate:    n.Estimate,
+		})
+	}
+
+	for _, c := range r.Connections {
+		snapshot.Connections = append(snapshot.Connections, models.Connection{
+			ID:             models.NewID(),
+			FromNodeID:     all[titleKey(c.From)],
+			ToNodeID:       all[titleKey(c.To)],
+			Label:          c.Label,
+			ConnectionType: c.Type,
+		})
+	}
+
+	for _, res := range r.Resources {
+		snapshot.Resources = append(snapshot.Resources, models.Resource{
+			ID:     models.NewID(),
+			NodeID: all[titleKey(res.Node)],
+			Title:  res.Title,
+			Type:   res.Type,
+			URL:    res.URL,
+		})
+	}
+	return snapshot, all
+}
@@ -0,0 +1,89 @@
+// Package dsl implementa un lenguaje de texto para escribir roadmaps sin
+// arrastrar nodos en el editor. Cada línea es un nodo o pertenece al nodo
+// anterior con menos sangría:
+//
+//	// Los comentarios empiezan por dos barras
+//	Fundamentos de Go [milestone color=#10B981 hours=4 order=1]
+//	  > La descripción del nodo, una o varias líneas
+//	  @video https://go.dev/tour Tour de Go
+//	  Sintaxis
+//	    Variables
+//	  ->[strong "antes de nada"] Concurrencia
+//	Concurrencia [challenge]
+//	  ->[dashed] "Patrones [avanzado]"
+//	"Patrones [avanzado]"
+//
+// Un nodo sangrado bajo otro queda conectado desde él con una conexión
+// normal. Las líneas que empiezan por -> añaden una conexión desde el nodo
+// al que pertenecen hasta el nodo con el título indicado; el modificador
+// entre corchetes lleva el tipo de conexión (default, strong, weak o
+// dashed) y una etiqueta opcional entre comillas. Las líneas @tipo url
+// título añaden un recurso y las que empiezan por > forman la descripción.
+//
+// Los atributos opcionales del nodo van entre corchetes al final: el tipo
+// (topic, resource, challenge o milestone, topic si no se indica), color,
+// hours con las horas estimadas y order con su orden en el plan de estudio.
+// Los títulos se escriben entre comillas cuando contienen corchetes o
+// comillas o empiezan como otro tipo de línea.
+package dsl
+
+import (
+	"strings"
+
+	"Gin/internal/models"
+)
+
+// DefaultColor es el color de los nodos que no indican ninguno, el mismo que
+// usa el editor para los nodos nuevos
+const DefaultColor = "#4F46E5"
+
+// Roadmap es el contenido de un texto ya analizado. Los nodos se identifican
+// por su título.
+type Roadmap struct {
+	Nodes       []Node
+	Connections []Connection
+	Resources   []Resource
+}
+
+// Node es un nodo del texto. Color está vacío si el texto no indica ninguno.
+type Node struct {
+	Line        int
+	Title       string
+	Description string
+	Type        models.NodeType
+	Color       string
+	Estimate    *float64
+	OrderIndex  *int
+}
+
+// Connection une dos nodos por su título. Nested indica que viene de la
+// sangría y no de una línea ->.
+type Connection struct {
+	Line   int
+	From   string
+	To     string
+	Type   models.ConnectionType
+	Label  string
+	Nested bool
+}
+
+// Resource es un recurso del nodo con el título indicado
+type Resource struct {
+	Line  int
+	Node  string
+	Type  string
+	URL   string
+	Title string
+}
+
+// Error es un problema del texto en la línea indicada
+type Error struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// titleKey es la forma en que se comparan los títulos: sin distinguir
+// mayúsculas ni espacios repetidos
+func titleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
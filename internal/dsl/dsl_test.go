@@ -0,0 +1,221 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+
+	"Gin/internal/merge"
+	"Gin/internal/models"
+)
+
+// example es el texto de la documentación del paquete
+const example = `// Los comentarios empiezan por dos barras
+Fundamentos de Go [milestone color=#10B981 hours=4 order=1]
+  > La descripción del nodo, una o varias líneas
+  @video https://go.dev/tour Tour de Go
+  Sintaxis
+    Variables
+  ->[strong "antes de nada"] Concurrencia
+Concurrencia [challenge]
+  ->[dashed] "Patrones [avanzado]"
+"Patrones [avanzado]"
+`
+
+func TestParse(t *testing.T) {
+	roadmap, problems := Parse(example)
+	if problems != nil {
+		t.Fatalf("Parse: %v", problems)
+	}
+
+	var titles []string
+	for _, node := range roadmap.Nodes {
+		titles = append(titles, node.Title)
+	}
+	if got := strings.Join(titles, "|"); got != "Fundamentos de Go|Sintaxis|Variables|Concurrencia|Patrones [avanzado]" {
+		t.Fatalf("nodos = %s", got)
+	}
+
+	first := roadmap.Nodes[0]
+	if first.Type != models.NodeTypeMilestone || first.Color != "#10B981" || *first.Estimate != 4 || *first.OrderIndex != 1 {
+		t.Errorf("atributos = %+v", first)
+	}
+	if first.Description != "La descripción del nodo, una o varias líneas" {
+		t.Errorf("descripción = %q", first.Description)
+	}
+	if node := roadmap.Nodes[1]; node.Type != models.NodeTypeTopic || node.Color != "" {
+		t.Errorf("nodo sin atributos = %+v", node)
+	}
+
+	want := []Connection{
+		{Line: 5, From: "Fundamentos de Go", To: "Sintaxis", Type: models.ConnectionTypeDefault, Nested: true},
+		{Line: 6, From: "Sintaxis", To: "Variables", Type: models.ConnectionTypeDefault, Nested: true},
+		{Line: 7, From: "Fundamentos de Go", To: "Concurrencia", Type: models.ConnectionTypeStrong, Label: "antes de nada"},
+		{Line: 9, From: "Concurrencia", To: "Patrones [avanzado]", Type: models.ConnectionTypeDashed},
+	}
+	if len(roadmap.Connections) != len(want) {
+		t.Fatalf("conexiones = %+v", roadmap.Connections)
+	}
+	for i, conn := range roadmap.Connections {
+		if conn != want[i] {
+			t.Errorf("conexión %d = %+v, se esperaba %+v", i, conn, want[i])
+		}
+	}
+
+	if len(roadmap.Resources) != 1 || roadmap.Resources[0] != (Resource{Line: 4, Node: "Fundamentos de Go", Type: "video", URL: "https://go.dev/tour", Title: "Tour de Go"}) {
+		t.Errorf("recursos = %+v", roadmap.Resources)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		line    int
+		message string
+	}{
+		{"tabulador", "A\n\tB", 2, "tabuladores"},
+		{"línea suelta", "-> B", 1, "sangrada bajo un nodo"},
+		{"comillas", `"A`, 1, "cerrar las comillas"},
+		{"título repetido", "A\na", 2, "Título repetido"},
+		{"tipo de nodo", "A [otro]", 1, "Tipo de nodo inválido"},
+		{"horas", "A [hours=0]", 1, "mayor que cero"},
+		{"atributo", "A [peso=1]", 1, "Atributo desconocido"},
+		{"destino", "A\n  -> B", 2, "Nodo desconocido"},
+		{"consigo mismo", "A\n  -> A", 2, "consigo mismo"},
+		{"conexión repetida", "A\n  B\n  -> B", 3, "Conexión repetida"},
+		{"tipo de conexión", "A\n  ->[otro] B\nB", 2, "Tipo de conexión inválido"},
+		{"recurso sin url", "A\n  @video", 2, "Falta la url"},
+		{"ciclo", "A\n  ->[strong] B\nB\n  ->[strong] A", 2, "ciclo de requisitos"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roadmap, problems := Parse(tt.text)
+			if roadmap != nil || len(problems) != 1 {
+				t.Fatalf("Parse = %v, %v", roadmap, problems)
+			}
+			if problems[0].Line != tt.line || !strings.Contains(problems[0].Message, tt.message) {
+				t.Errorf("problema = %+v, se esperaba en la línea %d con %q", problems[0], tt.line, tt.message)
+			}
+		})
+	}
+}
+
+// content devuelve un roadmap con los casos que Print tiene que conservar
+func content() models.Snapshot {
+	hours, order := 1.5, 2
+	node := func(title string, typ models.NodeType, color string, x, y float64) models.Node {
+		return models.Node{ID: models.NewID(), Title: title, Type: typ, Color: color, Status: "not_started",
+			Position: models.Position{X: x, Y: y}, Version: 1}
+	}
+	nodes := []models.Node{
+		node("Fundamentos", models.NodeTypeMilestone, "#10B981", 0, 0),
+		node("Sintaxis", models.NodeTypeTopic, "", 0, 100),
+		node("Tipos", models.NodeTypeTopic, DefaultColor, 250, 100),
+		node("Punteros", models.NodeTypeResource, "#4f46e5", 500, 100),
+		node("Concurrencia [avanzado]", models.NodeTypeChallenge, "#EF4444", 0, 200),
+		node("-> Proyecto", models.NodeTypeTopic, "", 250, 300),
+	}
+	nodes[0].Description = "Primera línea\n\nTercera línea"
+	nodes[1].Estimate = &hours
+	nodes[2].OrderIndex = &order
+	nodes[2].Status = "completed"
+
+	conn := func(from, to int, typ models.ConnectionType, label string) models.Connection {
+		return models.Connection{ID: models.NewID(), FromNodeID: nodes[from].ID, ToNodeID: nodes[to].ID, ConnectionType: typ, Label: label}
+	}
+	return models.Snapshot{
+		Nodes: nodes,
+		Connections: []models.Connection{
+			conn(0, 1, models.ConnectionTypeDefault, ""),
+			conn(0, 2, models.ConnectionTypeDefault, ""),
+			conn(1, 3, models.ConnectionTypeDefault, ""),
+			conn(2, 3, models.ConnectionTypeDefault, ""),
+			conn(0, 4, models.ConnectionTypeStrong, `requisito "duro"`),
+			conn(4, 5, models.ConnectionTypeDashed, ""),
+			conn(3, 5, models.ConnectionTypeWeak, ""),
+		},
+		Resources: []models.Resource{
+			{ID: models.NewID(), NodeID: nodes[0].ID, Title: "Tour de Go", Type: "video", URL: "https://go.dev/tour", Description: "Solo en el editor"},
+			{ID: models.NewID(), NodeID: nodes[0].ID, Title: "https://go.dev/doc", Type: "link", URL: "https://go.dev/doc"},
+		},
+	}
+}
+
+func TestPrintParseApplyRoundTrip(t *testing.T) {
+	current := content()
+	text := Print(current)
+	parsed, problems := Parse(text)
+	if problems != nil {
+		t.Fatalf("Parse: %v\n%s", problems, text)
+	}
+
+	result := merge.Merge(current, parsed.Apply(current), current, nil)
+	if len(result.Changes) > 0 || len(result.Conflicts) > 0 {
+		t.Errorf("aplicar el texto impreso produce cambios: %+v\n%s", result.Changes, text)
+	}
+	if again := Print(parsed.Apply(current)); again != text {
+		t.Errorf("el texto cambia al volver a imprimirlo:\n%s\n---\n%s", text, again)
+	}
+}
+
+func TestApplyColors(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string // color del nodo existente; vacío si el nodo es nuevo
+		text    string
+		want    string
+	}{
+		{"nuevo sin color", "", "A", DefaultColor},
+		{"nuevo con color", "", "A [color=#EF4444]", "#EF4444"},
+		{"existente sin color", "-", "A", ""},
+		{"existente por defecto", DefaultColor, "A", DefaultColor},
+		{"existente por defecto en minúsculas", "#4f46e5", "A", "#4f46e5"},
+		{"se quita el color", "#EF4444", "A", DefaultColor},
+		{"se cambia el color", "#EF4444", "A [color=#10B981]", "#10B981"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var current models.Snapshot
+			if tt.current != "" {
+				color := strings.TrimPrefix(tt.current, "-")
+				current.Nodes = []models.Node{{ID: models.NewID(), Title: "A", Type: models.NodeTypeTopic, Color: color}}
+			}
+			parsed, problems := Parse(tt.text)
+			if problems != nil {
+				t.Fatalf("Parse: %v", problems)
+			}
+			if got := parsed.Apply(current).Nodes[0].Color; got != tt.want {
+				t.Errorf("color = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsExistingNodes(t *testing.T) {
+	current := content()
+	parsed, problems := Parse("fundamentos [milestone]\n  Nuevo\n  ->[strong] Sintaxis\nSintaxis")
+	if problems != nil {
+		t.Fatalf("Parse: %v", problems)
+	}
+	applied := parsed.Apply(current)
+
+	if len(applied.Nodes) != 3 {
+		t.Fatalf("nodos = %+v", applied.Nodes)
+	}
+	fundamentos, nuevo, sintaxis := applied.Nodes[0], applied.Nodes[1], applied.Nodes[2]
+	if fundamentos.ID != current.Nodes[0].ID || fundamentos.Position != current.Nodes[0].Position || fundamentos.Title != "fundamentos" {
+		t.Errorf("el nodo existente no conserva su ID y posición: %+v", fundamentos)
+	}
+	if sintaxis.ID != current.Nodes[1].ID || sintaxis.Estimate != nil {
+		t.Errorf("Sintaxis = %+v", sintaxis)
+	}
+	if nuevo.Position.Y <= sintaxis.Position.Y {
+		t.Errorf("el nodo nuevo no queda debajo del contenido: %+v", nuevo.Position)
+	}
+	if applied.Connections[1].ID != current.Connections[0].ID {
+		t.Errorf("la conexión entre los mismos nodos no conserva su ID")
+	}
+	if len(applied.Resources) != 0 {
+		t.Errorf("recursos = %+v", applied.Resources)
+	}
+}
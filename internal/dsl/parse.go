@@ -0,0 +1,340 @@
+package dsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"Gin/internal/graph"
+	"Gin/internal/models"
+)
+
+// block es un nodo abierto junto con la sangría de su línea
+type block struct {
+	indent int
+	node   int
+}
+
+// parser acumula el contenido y los problemas del texto
+type parser struct {
+	roadmap Roadmap
+	errors  []Error
+	byKey   map[string]int
+	lines   map[int][]string // líneas de descripción de cada nodo
+}
+
+func (p *parser) add(line int, format string, args ...any) {
+	p.errors = append(p.errors, Error{line, fmt.Sprintf(format, args...)})
+}
+
+// Parse analiza el texto y devuelve todos los problemas encontrados. Si hay
+// alguno el roadmap devuelto no debe usarse.
+func Parse(text string) (*Roadmap, []Error) {
+	p := &parser{byKey: map[string]int{}, lines: map[int][]string{}}
+	var stack []block
+
+	for i, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := i + 1
+		raw = strings.TrimRight(raw, " \t")
+		content := strings.TrimLeft(raw, " ")
+		if content == "" || strings.HasPrefix(content, "//") {
+			continue
+		}
+		if strings.HasPrefix(content, "\t") {
+			p.add(line, "Usa espacios, no tabuladores, para sangrar")
+			continue
+		}
+
+		// Cada línea cierra los nodos con la misma sangría o más
+		indent := len(raw) - len(content)
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		owner := -1
+		if len(stack) > 0 {
+			owner = stack[len(stack)-1].node
+		}
+
+		switch {
+		case strings.HasPrefix(content, "->"), strings.HasPrefix(content, "@"), strings.HasPrefix(content, ">"):
+			if owner < 0 {
+				p.add(line, "La línea debe ir sangrada bajo un nodo")
+				continue
+			}
+			from := p.roadmap.Nodes[owner].Title
+			switch content[0] {
+			case '-':
+				p.connection(line, from, content[2:])
+			case '@':
+				p.resource(line, from, content[1:])
+			default:
+				p.lines[owner] = append(p.lines[owner], strings.TrimPrefix(content[1:], " "))
+			}
+
+		default:
+			node, ok := p.node(line, content)
+			if !ok {
+				continue
+			}
+			if owner >= 0 {
+				p.roadmap.Connections = append(p.roadmap.Connections, Connection{
+					Line:   line,
+					From:   p.roadmap.Nodes[owner].Title,
+					To:     node.Title,
+					Type:   models.ConnectionTypeDefault,
+					Nested: true,
+				})
+			}
+			stack = append(stack, block{indent, len(p.roadmap.Nodes)})
+			p.byKey[titleKey(node.Title)] = len(p.roadmap.Nodes)
+			p.roadmap.Nodes = append(p.roadmap.Nodes, node)
+		}
+	}
+
+	for node, lines := range p.lines {
+		p.roadmap.Nodes[node].Description = strings.Join(lines, "\n")
+	}
+	p.resolve()
+	if len(p.errors) > 0 {
+		return nil, p.errors
+	}
+	return &p.roadmap, nil
+}
+
+// node analiza una línea de nodo: el título y sus atributos opcionales
+func (p *parser) node(line int, content string) (Node, bool) {
+	node := Node{Line: line, Type: models.NodeTypeTopic}
+
+	var attrs string
+	if strings.HasPrefix(content, `"`) {
+		title, rest, ok := unquote(content)
+		if !ok {
+			p.add(line, "Falta cerrar las comillas del título")
+			return node, false
+		}
+		node.Title = title
+		if rest = strings.TrimSpace(rest); rest != "" {
+			if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+				p.add(line, "Después del título solo pueden ir los atributos entre corchetes")
+				return node, false
+			}
+			attrs = rest[1 : len(rest)-1]
+		}
+	} else {
+		node.Title = content
+		if open := strings.LastIndex(content, "["); open >= 0 && strings.HasSuffix(content, "]") {
+			node.Title, attrs = strings.TrimSpace(content[:open]), content[open+1:len(content)-1]
+		}
+	}
+
+	node.Title = strings.TrimSpace(node.Title)
+	switch {
+	case node.Title == "":
+		p.add(line, "El nodo no tiene título")
+		return node, false
+	case utf8.RuneCountInString(node.Title) > 255:
+		p.add(line, "El título supera los 255 caracteres")
+		return node, false
+	}
+	if first, ok := p.byKey[titleKey(node.Title)]; ok {
+		p.add(line, "Título repetido: %s ya aparece en la línea %d", node.Title, p.roadmap.Nodes[first].Line)
+		return node, false
+	}
+
+	for _, attr := range strings.FieldsFunc(attrs, func(r rune) bool { return r == ' ' || r == ',' }) {
+		key, value, hasValue := strings.Cut(attr, "=")
+		if !hasValue {
+			if t := models.NodeType(key); t.Valid() {
+				node.Type = t
+			} else {
+				p.add(line, "Tipo de nodo inválido: %s", key)
+			}
+			continue
+		}
+		switch key {
+		case "color":
+			if value == "" || len(value) > 7 {
+				p.add(line, "Color inválido: %s", value)
+			} else {
+				node.Color = value
+			}
+		case "hours":
+			hours, err := strconv.ParseFloat(value, 64)
+			if err != nil || hours <= 0 {
+				p.add(line, "Las horas deben ser un número mayor que cero")
+			} else {
+				node.Estimate = &hours
+			}
+		case "order":
+			order, err := strconv.Atoi(value)
+			if err != nil {
+				p.add(line, "El orden debe ser un número entero")
+			} else {
+				node.OrderIndex = &order
+			}
+		default:
+			p.add(line, "Atributo desconocido: %s", key)
+		}
+	}
+	return node, true
+}
+
+// connection analiza una línea -> con su modificador opcional
+func (p *parser) connection(line int, from, content string) {
+	conn := Connection{Line: line, From: from, Type: models.ConnectionTypeDefault}
+	content = strings.TrimSpace(content)
+
+	if strings.HasPrefix(content, "[") {
+		end := closingBracket(content)
+		if end < 0 {
+			p.add(line, "Falta cerrar el corchete del modificador")
+			return
+		}
+		modifier := strings.TrimSpace(content[1:end])
+		content = strings.TrimSpace(content[end+1:])
+		for modifier != "" {
+			if strings.HasPrefix(modifier, `"`) {
+				label, rest, ok := unquote(modifier)
+				if !ok {
+					p.add(line, "Falta cerrar las comillas de la etiqueta")
+					return
+				}
+				if utf8.RuneCountInString(label) > 255 {
+					p.add(line, "La etiqueta supera los 255 caracteres")
+				}
+				conn.Label, modifier = label, strings.TrimSpace(rest)
+				continue
+			}
+			word, rest, _ := strings.Cut(modifier, " ")
+			if t := models.ConnectionType(word); t.Valid() {
+				conn.Type = t
+			} else {
+				p.add(line, "Tipo de conexión inválido: %s", word)
+			}
+			modifier = strings.TrimSpace(rest)
+		}
+	}
+
+	if strings.HasPrefix(content, `"`) {
+		target, rest, ok := unquote(content)
+		if !ok || strings.TrimSpace(rest) != "" {
+			p.add(line, "El destino de la conexión está mal escrito")
+			return
+		}
+		content = target
+	}
+	if conn.To = strings.TrimSpace(content); conn.To == "" {
+		p.add(line, "Falta el nodo de destino de la conexión")
+		return
+	}
+	p.roadmap.Connections = append(p.roadmap.Connections, conn)
+}
+
+// resource analiza una línea @tipo url título. Si la línea empieza
+// directamente por la url el tipo es link, y sin título se usa la url.
+func (p *parser) resource(line int, node, content string) {
+	fields := strings.Fields(content)
+	resource := Resource{Line: line, Node: node, Type: "link"}
+	if len(fields) > 0 && !strings.Contains(fields[0], "://") {
+		resource.Type, fields = fields[0], fields[1:]
+	}
+	if len(fields) == 0 {
+		p.add(line, "Falta la url del recurso")
+		return
+	}
+	resource.URL = fields[0]
+	resource.Title = strings.Join(fields[1:], " ")
+	if resource.Title == "" {
+		resource.Title = resource.URL
+	}
+
+	switch {
+	case utf8.RuneCountInString(resource.Type) > 50:
+		p.add(line, "El tipo del recurso supera los 50 caracteres")
+	case utf8.RuneCountInString(resource.Title) > 255:
+		p.add(line, "El título del recurso supera los 255 caracteres")
+	default:
+		p.roadmap.Resources = append(p.roadmap.Resources, resource)
+	}
+}
+
+// resolve comprueba las conexiones una vez conocidos todos los nodos y busca
+// ciclos de requisitos
+func (p *parser) resolve() {
+	pairs := map[[2]string]int{}
+	for _, conn := range p.roadmap.Connections {
+		to, ok := p.byKey[titleKey(conn.To)]
+		if !ok {
+			p.add(conn.Line, "Nodo desconocido: %s", conn.To)
+			continue
+		}
+		pair := [2]string{titleKey(conn.From), titleKey(conn.To)}
+		switch first, repeated := pairs[pair]; {
+		case pair[0] == pair[1]:
+			p.add(conn.Line, "Un nodo no puede conectarse consigo mismo")
+		case repeated:
+			p.add(conn.Line, "Conexión repetida de %s a %s: ya aparece en la línea %d", conn.From, p.roadmap.Nodes[to].Title, first)
+		default:
+			pairs[pair] = conn.Line
+		}
+	}
+	if len(p.errors) > 0 {
+		return
+	}
+
+	snapshot, _ := p.roadmap.snapshot(nil)
+	titles := make(map[models.ID]string, len(snapshot.Nodes))
+	for _, node := range snapshot.Nodes {
+		titles[node.ID] = node.Title
+	}
+	for _, cycle := range graph.New(snapshot.Nodes, snapshot.Connections).Cycles() {
+		names := make([]string, len(cycle))
+		for i, id := range cycle {
+			names[i] = titles[id]
+		}
+		a, b := titleKey(names[0]), titleKey(names[1])
+		line := pairs[[2]string{a, b}]
+		if line == 0 {
+			line = pairs[[2]string{b, a}]
+		}
+		p.add(line, "Las conexiones forman un ciclo de requisitos: %s", strings.Join(names, " → "))
+	}
+}
+
+// unquote lee el texto entre comillas del principio de s, con \" y \\ como
+// escapes, y devuelve también lo que queda detrás
+func unquote(s string) (value, rest string, ok bool) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+			}
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), s[i+1:], true
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", false
+}
+
+// closingBracket devuelve la posición del corchete que cierra el que abre s,
+// saltando los que aparecen entre comillas, o -1 si no se cierra
+func closingBracket(s string) int {
+	quoted := false
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quoted:
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == ']' && !quoted:
+			return i
+		}
+	}
+	return -1
+}
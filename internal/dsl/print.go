@@ -0,0 +1,191 @@
+package dsl
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"Gin/internal/graph"
+	"Gin/internal/models"
+)
+
+// printer escribe el texto de un contenido
+type printer struct {
+	b         strings.Builder
+	g         *graph.Graph
+	order     map[models.ID]int
+	parent    map[models.ID]models.ID
+	nested    map[models.ID]bool
+	printed   map[models.ID]bool
+	resources map[models.ID][]models.Resource
+}
+
+// Print escribe el contenido como texto. Los nodos con una sola conexión de
+// entrada, normal y sin etiqueta, se escriben sangrados bajo su origen; el
+// resto de conexiones se escriben con ->. Analizar el texto y aplicarlo sobre
+// el mismo contenido no produce cambios.
+func Print(snapshot models.Snapshot) string {
+	p := &printer{
+		g:         graph.New(snapshot.Nodes, snapshot.Connections),
+		order:     make(map[models.ID]int, len(snapshot.Nodes)),
+		parent:    make(map[models.ID]models.ID),
+		nested:    make(map[models.ID]bool),
+		printed:   make(map[models.ID]bool, len(snapshot.Nodes)),
+		resources: make(map[models.ID][]models.Resource),
+	}
+
+	nodes, err := p.g.Order()
+	if err != nil {
+		nodes = p.g.Nodes()
+		sort.SliceStable(nodes, func(i, j int) bool { return graph.Less(nodes[i], nodes[j]) })
+	}
+	for i, node := range nodes {
+		p.order[node.ID] = i
+		incoming := p.g.Incoming(node.ID)
+		if len(incoming) == 1 && incoming[0].ConnectionType == models.ConnectionTypeDefault &&
+			incoming[0].Label == "" && incoming[0].FromNodeID != node.ID {
+			p.parent[node.ID] = incoming[0].FromNodeID
+		}
+	}
+	for _, resource := range snapshot.Resources {
+		p.resources[resource.NodeID] = append(p.resources[resource.NodeID], resource)
+	}
+
+	// Primero los nodos sin padre y después los que solo tienen padre dentro
+	// de un ciclo de conexiones normales
+	for _, roots := range []bool{true, false} {
+		for _, node := range nodes {
+			_, hasParent := p.parent[node.ID]
+			if p.printed[node.ID] || (roots && hasParent) {
+				continue
+			}
+			if p.b.Len() > 0 {
+				p.b.WriteString("\n")
+			}
+			p.printed[node.ID] = true
+			p.node(node, 0)
+		}
+	}
+	return p.b.String()
+}
+
+// node escribe un nodo con su descripción, recursos, conexiones e hijos
+func (p *printer) node(node models.Node, depth int) {
+	indent := strings.Repeat("  ", depth)
+	inner := indent + "  "
+
+	// Los hijos se eligen antes de escribir las conexiones para no repetir
+	// con -> las que ya expresa la sangría
+	var children []models.Node
+	for _, conn := range p.outgoing(node.ID) {
+		child, _ := p.g.Node(conn.ToNodeID)
+		if p.parent[child.ID] == node.ID && !p.printed[child.ID] {
+			p.printed[child.ID] = true
+			p.nested[conn.ID] = true
+			children = append(children, child)
+		}
+	}
+
+	p.b.WriteString(indent + nodeLine(node) + "\n")
+	if node.Description != "" {
+		for _, line := range strings.Split(node.Description, "\n") {
+			p.b.WriteString(strings.TrimRight(inner+"> "+line, " ") + "\n")
+		}
+	}
+	for _, resource := range p.resources[node.ID] {
+		p.b.WriteString(inner + resourceLine(resource) + "\n")
+	}
+	for _, conn := range p.outgoing(node.ID) {
+		if !p.nested[conn.ID] {
+			target, _ := p.g.Node(conn.ToNodeID)
+			p.b.WriteString(inner + connectionLine(conn, target) + "\n")
+		}
+	}
+	for _, child := range children {
+		p.node(child, depth+1)
+	}
+}
+
+// outgoing devuelve las conexiones que salen del nodo en el orden de estudio
+// de su destino
+func (p *printer) outgoing(id models.ID) []models.Connection {
+	conns := append([]models.Connection(nil), p.g.Outgoing(id)...)
+	sort.SliceStable(conns, func(i, j int) bool {
+		return p.order[conns[i].ToNodeID] < p.order[conns[j].ToNodeID]
+	})
+	return conns
+}
+
+// nodeLine escribe el título y los atributos que no tienen su valor por
+// defecto
+func nodeLine(node models.Node) string {
+	var attrs []string
+	if node.Type != "" && node.Type != models.NodeTypeTopic {
+		attrs = append(attrs, string(node.Type))
+	}
+	if node.Color != "" && !strings.EqualFold(node.Color, DefaultColor) {
+		attrs = append(attrs, "color="+node.Color)
+	}
+	if node.Estimate != nil {
+		attrs = append(attrs, "hours="+strconv.FormatFloat(*node.Estimate, 'f', -1, 64))
+	}
+	if node.OrderIndex != nil {
+		attrs = append(attrs, "order="+strconv.Itoa(*node.OrderIndex))
+	}
+
+	line := title(node.Title)
+	if len(attrs) > 0 {
+		line += " [" + strings.Join(attrs, " ") + "]"
+	}
+	return line
+}
+
+// connectionLine escribe una conexión con su tipo y etiqueta
+func connectionLine(conn models.Connection, target models.Node) string {
+	var modifier []string
+	if conn.ConnectionType != "" && conn.ConnectionType != models.ConnectionTypeDefault {
+		modifier = append(modifier, string(conn.ConnectionType))
+	}
+	if conn.Label != "" {
+		modifier = append(modifier, quote(conn.Label))
+	}
+
+	line := "->"
+	if len(modifier) > 0 {
+		line += "[" + strings.Join(modifier, " ") + "]"
+	}
+	return line + " " + title(target.Title)
+}
+
+// resourceLine escribe un recurso; el título se omite si es la propia url
+func resourceLine(resource models.Resource) string {
+	kind := resource.Type
+	if kind == "" || strings.ContainsAny(kind, " \t") || strings.Contains(kind, "://") {
+		kind = "link"
+	}
+	line := "@" + kind + " " + resource.URL
+	if resource.Title != "" && resource.Title != resource.URL {
+		line += " " + resource.Title
+	}
+	return line
+}
+
+// title escribe un título entre comillas si de otro modo se leería como otra
+// cosa
+func title(s string) string {
+	for _, prefix := range []string{"->", "@", ">", "//", `"`} {
+		if strings.HasPrefix(s, prefix) {
+			return quote(s)
+		}
+	}
+	if strings.ContainsAny(s, "[]") {
+		return quote(s)
+	}
+	return s
+}
+
+// quote escribe s entre comillas escapando las comillas y las barras
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"Gin/internal/models"
+)
+
+// dotStyles son los atributos de Graphviz de cada tipo de conexión
+var dotStyles = map[models.ConnectionType]string{
+	models.ConnectionTypeDefault: "",
+	models.ConnectionTypeStrong:  "penwidth=2",
+	models.ConnectionTypeDashed:  "style=dashed",
+	models.ConnectionTypeWeak:    "style=dotted, arrowhead=none",
+}
+
+// dotShapes es la forma de Graphviz de cada tipo de nodo
+var dotShapes = map[models.NodeType]string{
+	models.NodeTypeTopic:     "box",
+	models.NodeTypeResource:  "note",
+	models.NodeTypeChallenge: "hexagon",
+	models.NodeTypeMilestone: "doubleoctagon",
+}
+
+// writeDOT escribe el roadmap como grafo dirigido de Graphviz. Los nodos se
+// identifican por su ID y se rellenan con su color.
+func writeDOT(buf *bytes.Buffer, r Roadmap) {
+	buf.WriteString("digraph roadmap {\n")
+	if r.Title != "" {
+		fmt.Fprintf(buf, "    label=%s;\n    labelloc=t;\n", dotString(r.Title))
+	}
+	buf.WriteString("    rankdir=TB;\n")
+	buf.WriteString("    node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\", fontname=\"Helvetica\"];\n")
+	buf.WriteString("    edge [fontname=\"Helvetica\"];\n")
+
+	ids := make(map[models.ID]bool, len(r.Nodes))
+	for _, node := range studyOrder(r) {
+		ids[node.ID] = true
+		attrs := []string{"label=" + dotString(node.Title)}
+		if shape, ok := dotShapes[node.Type]; ok && shape != "box" {
+			attrs = append(attrs, "shape="+shape)
+		}
+		if color, ok := nodeColor(node.Color); ok {
+			attrs = append(attrs, fmt.Sprintf("fillcolor=%q", color), fmt.Sprintf("fontcolor=%q", textColor(color)))
+		}
+		if description := strings.TrimSpace(node.Description); description != "" {
+			attrs = append(attrs, "tooltip="+dotString(description))
+		}
+		fmt.Fprintf(buf, "    %s [%s];\n", dotString(node.ID.String()), strings.Join(attrs, ", "))
+	}
+
+	for _, conn := range r.Connections {
+		if !ids[conn.FromNodeID] || !ids[conn.ToNodeID] {
+			continue
+		}
+		var attrs []string
+		if style := dotStyles[conn.ConnectionType]; style != "" {
+			attrs = append(attrs, style)
+		}
+		if label := strings.TrimSpace(conn.Label); label != "" {
+			attrs = append(attrs, "label="+dotString(label))
+		}
+		fmt.Fprintf(buf, "    %s -> %s", dotString(conn.FromNodeID.String()), dotString(conn.ToNodeID.String()))
+		if len(attrs) > 0 {
+			fmt.Fprintf(buf, " [%s]", strings.Join(attrs, ", "))
+		}
+		buf.WriteString(";\n")
+	}
+	buf.WriteString("}\n")
+}
+
+// dotString escribe un texto como cadena entre comillas de Graphviz
+func dotString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
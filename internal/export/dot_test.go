@@ -0,0 +1,68 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDOT(t *testing.T) {
+	r, ids := sample()
+	out, err := Export(FormatDOT, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer(
+		"FUNDAMENTOS", ids["fundamentos"].String(),
+		"EXTRA", ids["extra"].String(),
+		"GO", ids["go"].String(),
+		"PROYECTO", ids["proyecto"].String(),
+	).Replace(`digraph roadmap {
+    label="Go \"desde cero\"";
+    labelloc=t;
+    rankdir=TB;
+    node [shape=box, style="rounded,filled", fillcolor="#ffffff", fontname="Helvetica"];
+    edge [fontname="Helvetica"];
+    "FUNDAMENTOS" [label="Fundamentos", fillcolor="#10b981", fontcolor="#ffffff", tooltip="Sintaxis y tipos"];
+    "EXTRA" [label="Extra", shape=note];
+    "GO" [label="Go \"moderno\" [1.22]", shape=hexagon, fillcolor="#aabbcc", fontcolor="#000000"];
+    "PROYECTO" [label="Proyecto", shape=doubleoctagon];
+    "FUNDAMENTOS" -> "GO" [penwidth=2, label="antes \"de nada\""];
+    "GO" -> "PROYECTO" [style=dashed];
+    "FUNDAMENTOS" -> "EXTRA" [style=dotted, arrowhead=none];
+    "EXTRA" -> "PROYECTO";
+}
+`)
+	if string(out) != want {
+		t.Errorf("DOT:\n%s\nse esperaba:\n%s", out, want)
+	}
+}
+
+func TestDOTColors(t *testing.T) {
+	tests := []struct {
+		color, attrs string
+	}{
+		{"#EF4444", `fillcolor="#ef4444", fontcolor="#ffffff"`},
+		{"#fde68a", `fillcolor="#fde68a", fontcolor="#000000"`},
+		{"Orange", `fillcolor="orange", fontcolor="#000000"`},
+		{"", ""},
+		{"rgb(0,0,0)", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.color, func(t *testing.T) {
+			r, _ := sample()
+			r.Nodes[1].Color = tt.color
+			out, _ := Export(FormatDOT, r)
+			line := string(out)[strings.Index(string(out), `[label="Fundamentos"`):]
+			line = line[:strings.Index(line, "\n")]
+			if tt.attrs == "" && strings.Contains(line, "fillcolor") || !strings.Contains(line, tt.attrs) {
+				t.Errorf("color %q: %s", tt.color, line)
+			}
+		})
+	}
+}
+
+func TestDOTString(t *testing.T) {
+	if got := dotString("a\\b \"c\"\r\nd\ne"); got != `"a\\b \"c\"\nd\ne"` {
+		t.Errorf("dotString = %s", got)
+	}
+}
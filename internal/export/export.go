@@ -0,0 +1,143 @@
+// Package export convierte un roadmap a formatos que pueden guardarse junto a
+// la documentación: diagramas de Mermaid y Graphviz, un índice en Markdown y
+// una imagen SVG.
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"Gin/internal/graph"
+	"Gin/internal/models"
+)
+
+// Format es un formato de exportación
+type Format string
+
+const (
+	FormatMermaid  Format = "mermaid"
+	FormatDOT      Format = "dot"
+	FormatMarkdown Format = "markdown"
+	FormatSVG      Format = "svg"
+)
+
+// Valid indica si el formato es uno de los conocidos
+func (f Format) Valid() bool {
+	switch f {
+	case FormatMermaid, FormatDOT, FormatMarkdown, FormatSVG:
+		return true
+	}
+	return false
+}
+
+// ContentType devuelve el tipo MIME del formato
+func (f Format) ContentType() string {
+	switch f {
+	case FormatDOT:
+		return "text/vnd.graphviz; charset=utf-8"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatSVG:
+		return "image/svg+xml"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// Extension devuelve la extensión habitual de los ficheros del formato
+func (f Format) Extension() string {
+	switch f {
+	case FormatMermaid:
+		return "mmd"
+	case FormatDOT:
+		return "dot"
+	case FormatSVG:
+		return "svg"
+	}
+	return "md"
+}
+
+// Roadmap es el contenido que se exporta
+type Roadmap struct {
+	Title       string
+	Description string
+	models.Snapshot
+	// Progress son los estados de un usuario que se dibujan sobre los nodos;
+	// solo lo usa el formato SVG
+	Progress map[models.ID]models.ProgressStatus
+}
+
+// Export escribe el roadmap en el formato indicado
+func Export(format Format, r Roadmap) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case FormatMermaid:
+		writeMermaid(&buf, r)
+	case FormatDOT:
+		writeDOT(&buf, r)
+	case FormatMarkdown:
+		writeMarkdown(&buf, r)
+	case FormatSVG:
+		writeSVG(&buf, r)
+	default:
+		return nil, fmt.Errorf("formato de exportación desconocido: %s", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// studyOrder devuelve los nodos en orden de estudio. Si los requisitos
+// forman un ciclo los ordena solo por graph.Less.
+func studyOrder(r Roadmap) []models.Node {
+	if order, err := graph.New(r.Nodes, r.Connections).Order(); err == nil {
+		return order
+	}
+	order := append([]models.Node(nil), r.Nodes...)
+	sort.SliceStable(order, func(i, j int) bool { return graph.Less(order[i], order[j]) })
+	return order
+}
+
+// nodeColor normaliza el color de un nodo para Mermaid y Graphviz, que
+// entienden los colores hexadecimales en la forma #rrggbb y los nombres de
+// colores. Devuelve false si no es ninguno de los dos.
+func nodeColor(color string) (string, bool) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color == "" {
+		return "", false
+	}
+	if !strings.HasPrefix(color, "#") {
+		for _, r := range color {
+			if r < 'a' || r > 'z' {
+				return "", false
+			}
+		}
+		return color, true
+	}
+
+	digits := color[1:]
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+	if len(digits) != 6 {
+		return "", false
+	}
+	if _, err := strconv.ParseUint(digits, 16, 32); err != nil {
+		return "", false
+	}
+	return "#" + digits, true
+}
+
+// textColor elige texto negro o blanco según la luminosidad del fondo. Con
+// colores por nombre el texto es negro.
+func textColor(background string) string {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(background, "#"), 16, 32)
+	if err != nil || !strings.HasPrefix(background, "#") {
+		return "#000000"
+	}
+	r, g, b := float64(rgb>>16&0xff), float64(rgb>>8&0xff), float64(rgb&0xff)
+	if 0.299*r+0.587*g+0.114*b < 140 {
+		return "#ffffff"
+	}
+	return "#000000"
+}
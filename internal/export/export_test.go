@@ -0,0 +1,97 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"Gin/internal/models"
+)
+
+// sample devuelve un roadmap con todos los tipos de nodo y de conexión. En
+// orden de estudio los nodos son Fundamentos, Extra, Go y Proyecto, aunque
+// Proyecto está el primero en el canvas.
+func sample() (Roadmap, map[string]models.ID) {
+	ids := make(map[string]models.ID)
+	node := func(key, title string, typ models.NodeType, color string, x, y float64) models.Node {
+		ids[key] = models.NewID()
+		return models.Node{ID: ids[key], Title: title, Type: typ, Color: color, Position: models.Position{X: x, Y: y}}
+	}
+	nodes := []models.Node{
+		node("proyecto", "Proyecto", models.NodeTypeMilestone, "", 0, -100),
+		node("fundamentos", "Fundamentos", models.NodeTypeTopic, "#10B981", 0, 0),
+		node("go", `Go "moderno" [1.22]`, models.NodeTypeChallenge, "#abc", 0, 100),
+		node("extra", "Extra", models.NodeTypeResource, "no es un color", 300, 50),
+	}
+	nodes[1].Description = "Sintaxis y tipos"
+	conn := func(from, to string, typ models.ConnectionType, label string) models.Connection {
+		return models.Connection{ID: models.NewID(), FromNodeID: ids[from], ToNodeID: ids[to], ConnectionType: typ, Label: label}
+	}
+	return Roadmap{
+		Title: `Go "desde cero"`,
+		Snapshot: models.Snapshot{
+			Nodes: nodes,
+			Connections: []models.Connection{
+				conn("fundamentos", "go", models.ConnectionTypeStrong, `antes "de nada"`),
+				conn("go", "proyecto", models.ConnectionTypeDashed, ""),
+				conn("fundamentos", "extra", models.ConnectionTypeWeak, ""),
+				conn("extra", "proyecto", models.ConnectionTypeDefault, ""),
+			},
+			Resources: []models.Resource{
+				{ID: models.NewID(), NodeID: ids["extra"], Title: "Tour [oficial]", Type: "video", URL: "https://go.dev/tour"},
+				{ID: models.NewID(), NodeID: ids["extra"], Title: "Spec", URL: "https://go.dev/ref/spec (2024)"},
+			},
+		},
+	}, ids
+}
+
+func TestNodeColor(t *testing.T) {
+	tests := []struct {
+		color, want string
+		ok          bool
+	}{
+		{"#10B981", "#10b981", true},
+		{" #abc ", "#aabbcc", true},
+		{"red", "red", true},
+		{"", "", false},
+		{"#12345", "", false},
+		{"#gggggg", "", false},
+		{"no es un color", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := nodeColor(tt.color); got != tt.want || ok != tt.ok {
+			t.Errorf("nodeColor(%q) = %q, %v", tt.color, got, ok)
+		}
+	}
+}
+
+func TestTextColor(t *testing.T) {
+	tests := map[string]string{"#000000": "#ffffff", "#4f46e5": "#ffffff", "#ffffff": "#000000", "#10b981": "#ffffff", "#aabbcc": "#000000", "red": "#000000"}
+	for background, want := range tests {
+		if got := textColor(background); got != want {
+			t.Errorf("textColor(%q) = %q, se esperaba %q", background, got, want)
+		}
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	r, _ := sample()
+	if _, err := Export("pdf", r); err == nil {
+		t.Error("Export acepta un formato desconocido")
+	}
+}
+
+func TestStudyOrderRequirementCycle(t *testing.T) {
+	// Con un ciclo de requisitos los nodos siguen el orden del canvas
+	r, ids := sample()
+	r.Connections = []models.Connection{
+		{ID: models.NewID(), FromNodeID: ids["go"], ToNodeID: ids["proyecto"], ConnectionType: models.ConnectionTypeStrong},
+		{ID: models.NewID(), FromNodeID: ids["proyecto"], ToNodeID: ids["go"], ConnectionType: models.ConnectionTypeStrong},
+	}
+	var titles []string
+	for _, node := range studyOrder(r) {
+		titles = append(titles, node.Title)
+	}
+	if got := strings.Join(titles, ","); got != `Proyecto,Fundamentos,Extra,Go "moderno" [1.22]` {
+		t.Errorf("orden = %s", got)
+	}
+}
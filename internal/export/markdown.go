@@ -0,0 +1,109 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"Gin/internal/graph"
+	"Gin/internal/models"
+)
+
+// nodeTypeLabels es el nombre de cada tipo de nodo en el índice
+var nodeTypeLabels = map[models.NodeType]string{
+	models.NodeTypeTopic:     "Topic",
+	models.NodeTypeResource:  "Resource",
+	models.NodeTypeChallenge: "Challenge",
+	models.NodeTypeMilestone: "Milestone",
+}
+
+// writeMarkdown escribe el roadmap como índice en Markdown: una sección
+// numerada por nodo en orden de estudio con su descripción, sus requisitos y
+// sus recursos como enlaces
+func writeMarkdown(buf *bytes.Buffer, r Roadmap) {
+	title := strings.TrimSpace(r.Title)
+	if title == "" {
+		title = "Roadmap"
+	}
+	fmt.Fprintf(buf, "# %s\n", markdownText(title))
+	if description := strings.TrimSpace(r.Description); description != "" {
+		fmt.Fprintf(buf, "\n%s\n", description)
+	}
+
+	resources := make(map[models.ID][]models.Resource)
+	for _, resource := range r.Resources {
+		resources[resource.NodeID] = append(resources[resource.NodeID], resource)
+	}
+	g := graph.New(r.Nodes, r.Connections)
+
+	for i, node := range studyOrder(r) {
+		fmt.Fprintf(buf, "\n## %d. %s\n\n", i+1, markdownText(node.Title))
+
+		details := []string{"_" + nodeTypeLabel(node.Type) + "_"}
+		if node.Estimate != nil {
+			details = append(details, fmt.Sprintf("%gh", *node.Estimate))
+		}
+		buf.WriteString(strings.Join(details, " · ") + "\n")
+
+		if description := strings.TrimSpace(node.Description); description != "" {
+			fmt.Fprintf(buf, "\n%s\n", description)
+		}
+
+		if prerequisites := g.Prerequisites(node.ID); len(prerequisites) > 0 {
+			titles := make([]string, len(prerequisites))
+			for j, prerequisite := range prerequisites {
+				titles[j] = markdownText(prerequisite.Title)
+			}
+			fmt.Fprintf(buf, "\n**Requires:** %s\n", strings.Join(titles, ", "))
+		}
+
+		if len(resources[node.ID]) > 0 {
+			buf.WriteString("\n**Resources:**\n\n")
+			for _, resource := range resources[node.ID] {
+				buf.WriteString("- " + markdownLink(resource) + "\n")
+			}
+		}
+	}
+}
+
+// nodeTypeLabel devuelve el nombre del tipo de nodo
+func nodeTypeLabel(t models.NodeType) string {
+	if label, ok := nodeTypeLabels[t]; ok {
+		return label
+	}
+	return string(t)
+}
+
+// markdownLink escribe un recurso como enlace, o solo su título si no tiene
+// URL
+func markdownLink(resource models.Resource) string {
+	title := markdownText(resource.Title)
+	if title == "" {
+		title = markdownText(resource.URL)
+	}
+	link := title
+	if url := strings.TrimSpace(resource.URL); url != "" {
+		if strings.ContainsAny(url, " ()<>") {
+			url = "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(url) + ">"
+		}
+		link = fmt.Sprintf("[%s](%s)", title, url)
+	}
+	if resource.Type != "" {
+		link += " (" + resource.Type + ")"
+	}
+	if description := strings.TrimSpace(resource.Description); description != "" {
+		link += " — " + strings.Join(strings.Fields(description), " ")
+	}
+	return link
+}
+
+// markdownEscaper escapa los caracteres con significado en Markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+// markdownText prepara un texto de una línea para Markdown
+func markdownText(s string) string {
+	return markdownEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"testing"
+
+	"Gin/internal/models"
+)
+
+func TestMarkdown(t *testing.T) {
+	r, _ := sample()
+	r.Description = "Un camino para aprender Go."
+	hours := 1.5
+	r.Nodes[2].Estimate = &hours
+	out, err := Export(FormatMarkdown, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Go "desde cero"
+
+Un camino para aprender Go.
+
+## 1. Fundamentos
+
+_Topic_
+
+Sintaxis y tipos
+
+## 2. Extra
+
+_Resource_
+
+**Resources:**
+
+- [Tour \[oficial\]](https://go.dev/tour) (video)
+- [Spec](<https://go.dev/ref/spec (2024)>)
+
+## 3. Go "moderno" \[1.22\]
+
+_Challenge_ · 1.5h
+
+**Requires:** Fundamentos
+
+## 4. Proyecto
+
+_Milestone_
+`
+	if string(out) != want {
+		t.Errorf("Markdown:\n%s\nse esperaba:\n%s", out, want)
+	}
+}
+
+func TestMarkdownLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource models.Resource
+		want     string
+	}{
+		{"con título", models.Resource{Title: "Go *by* example", URL: "https://gobyexample.com"}, `[Go \*by\* example](https://gobyexample.com)`},
+		{"sin título", models.Resource{URL: "https://go.dev"}, "[https://go.dev](https://go.dev)"},
+		{"sin url", models.Resource{Title: "Libro", Type: "book"}, "Libro (book)"},
+		{"con descripción", models.Resource{Title: "Blog", URL: "https://go.dev/blog", Description: "Artículos\n  del equipo"}, "[Blog](https://go.dev/blog) — Artículos del equipo"},
+		{"url con símbolos", models.Resource{Title: "Raro", URL: "https://x.dev/<a>"}, "[Raro](<https://x.dev/%3Ca%3E>)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownLink(tt.resource); got != tt.want {
+				t.Errorf("markdownLink = %s, se esperaba %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"Gin/internal/models"
+)
+
+// mermaidArrows es la línea de Mermaid de cada tipo de conexión: los
+// requisitos son gruesos, las discontinuas punteadas y las débiles punteadas
+// y sin flecha
+var mermaidArrows = map[models.ConnectionType]string{
+	models.ConnectionTypeDefault: "-->",
+	models.ConnectionTypeStrong:  "==>",
+	models.ConnectionTypeDashed:  "-.->",
+	models.ConnectionTypeWeak:    "-.-",
+}
+
+// mermaidShapes es la forma de cada tipo de nodo, como apertura y cierre
+var mermaidShapes = map[models.NodeType][2]string{
+	models.NodeTypeTopic:     {"[", "]"},
+	models.NodeTypeResource:  {"[/", "/]"},
+	models.NodeTypeChallenge: {"{{", "}}"},
+	models.NodeTypeMilestone: {"([", "])"},
+}
+
+// writeMermaid escribe el roadmap como diagrama de flujo de Mermaid. Los
+// nodos se identifican como n1, n2... en orden de estudio.
+func writeMermaid(buf *bytes.Buffer, r Roadmap) {
+	if r.Title != "" {
+		fmt.Fprintf(buf, "---\ntitle: %q\n---\n", strings.Join(strings.Fields(r.Title), " "))
+	}
+	buf.WriteString("flowchart TD\n")
+
+	keys := make(map[models.ID]string, len(r.Nodes))
+	var styles []string
+	for i, node := range studyOrder(r) {
+		key := fmt.Sprintf("n%d", i+1)
+		keys[node.ID] = key
+		shape, ok := mermaidShapes[node.Type]
+		if !ok {
+			shape = mermaidShapes[models.NodeTypeTopic]
+		}
+		fmt.Fprintf(buf, "    %s%s\"%s\"%s\n", key, shape[0], mermaidText(node.Title), shape[1])
+		if color, ok := nodeColor(node.Color); ok {
+			styles = append(styles, fmt.Sprintf("    style %s fill:%s,color:%s\n", key, color, textColor(color)))
+		}
+	}
+
+	for _, conn := range r.Connections {
+		from, okFrom := keys[conn.FromNodeID]
+		to, okTo := keys[conn.ToNodeID]
+		if !okFrom || !okTo {
+			continue
+		}
+		arrow, ok := mermaidArrows[conn.ConnectionType]
+		if !ok {
+			arrow = mermaidArrows[models.ConnectionTypeDefault]
+		}
+		if label := strings.TrimSpace(conn.Label); label != "" {
+			fmt.Fprintf(buf, "    %s %s|\"%s\"| %s\n", from, arrow, mermaidText(label), to)
+		} else {
+			fmt.Fprintf(buf, "    %s %s %s\n", from, arrow, to)
+		}
+	}
+
+	for _, style := range styles {
+		buf.WriteString(style)
+	}
+}
+
+// mermaidText prepara un texto para ir entre comillas en Mermaid, que no
+// admite comillas ni saltos de línea dentro de las etiquetas
+func mermaidText(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"Gin/internal/models"
+)
+
+func TestMermaid(t *testing.T) {
+	r, _ := sample()
+	out, err := Export(FormatMermaid, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `---
+title: "Go \"desde cero\""
+---
+flowchart TD
+    n1["Fundamentos"]
+    n2[/"Extra"/]
+    n3{{"Go #quot;moderno#quot; [1.22]"}}
+    n4(["Proyecto"])
+    n1 ==>|"antes #quot;de nada#quot;"| n3
+    n3 -.-> n4
+    n1 -.- n2
+    n2 --> n4
+    style n1 fill:#10b981,color:#ffffff
+    style n3 fill:#aabbcc,color:#000000
+`
+	if string(out) != want {
+		t.Errorf("Mermaid:\n%s\nse esperaba:\n%s", out, want)
+	}
+}
+
+func TestMermaidArrows(t *testing.T) {
+	tests := []struct {
+		kind, arrow string
+	}{
+		{"", "-->"},
+		{"default", "-->"},
+		{"strong", "==>"},
+		{"dashed", "-.->"},
+		{"weak", "-.-"},
+		{"desconocido", "-->"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			r, _ := sample()
+			r.Connections[3].ConnectionType = models.ConnectionType(tt.kind)
+			out, _ := Export(FormatMermaid, r)
+			if !strings.Contains(string(out), "    n2 "+tt.arrow+" n4\n") {
+				t.Errorf("conexión %q:\n%s", tt.kind, out)
+			}
+		})
+	}
+}
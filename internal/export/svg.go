@@ -0,0 +1,247 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"Gin/internal/models"
+)
+
+const (
+	svgPadding    = 24 // margen alrededor de los nodos
+	svgLineHeight = 17 // separación entre las líneas del título de un nodo
+	svgLineRunes  = 22 // caracteres por línea del título
+	svgMaxLines   = 3  // líneas del título antes de recortarlo
+	svgCaption    = 32 // alto de la línea de progreso bajo el dibujo
+)
+
+// svgEdge es el trazo de un tipo de conexión
+type svgEdge struct {
+	stroke string
+	width  string
+	dash   string
+	marker string
+}
+
+// svgEdges son los trazos de cada tipo de conexión. Las débiles no llevan
+// flecha, igual que en Graphviz.
+var svgEdges = map[models.ConnectionType]svgEdge{
+	models.ConnectionTypeDefault: {"#64748b", "2", "", "arrow"},
+	models.ConnectionTypeStrong:  {"#334155", "3", "", "arrow-strong"},
+	models.ConnectionTypeDashed:  {"#64748b", "2", "8 5", "arrow"},
+	models.ConnectionTypeWeak:    {"#94a3b8", "1.5", "2 4", ""},
+}
+
+// writeSVG dibuja los nodos en su posición del editor con la forma de su tipo
+// y su color. Si el roadmap trae progreso, los nodos completados y en curso
+// llevan una marca, los pendientes se atenúan y se añade el total completado.
+func writeSVG(buf *bytes.Buffer, r Roadmap) {
+	nodes := studyOrder(r)
+	if len(nodes) == 0 {
+		buf.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="400" height="120" viewBox="0 0 400 120">` + "\n")
+		fmt.Fprintf(buf, "<title>%s</title>\n", html.EscapeString(r.Title))
+		buf.WriteString(`<rect width="400" height="120" rx="8" fill="#f8fafc"/>` + "\n")
+		buf.WriteString(`<text x="200" y="65" text-anchor="middle" font-family="Inter, system-ui, sans-serif" font-size="14" fill="#64748b">Roadmap vacío</text>` + "\n")
+		buf.WriteString("</svg>\n")
+		return
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	byID := make(map[models.ID]models.Node, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = node
+		minX, minY = min(minX, node.Position.X), min(minY, node.Position.Y)
+		maxX, maxY = max(maxX, node.Position.X+models.NodeWidth), max(maxY, node.Position.Y+models.NodeHeight)
+	}
+	x, y := minX-svgPadding, minY-svgPadding
+	width, height := maxX-minX+2*svgPadding, maxY-minY+2*svgPadding
+	if r.Progress != nil {
+		height += svgCaption
+	}
+
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="%s %s %s %s" font-family="Inter, system-ui, sans-serif">`+"\n",
+		svgNum(width), svgNum(height), svgNum(x), svgNum(y), svgNum(width), svgNum(height))
+	if r.Title != "" {
+		fmt.Fprintf(buf, "<title>%s</title>\n", html.EscapeString(r.Title))
+	}
+	buf.WriteString("<defs>\n")
+	for _, marker := range [][2]string{{"arrow", "#64748b"}, {"arrow-strong", "#334155"}} {
+		fmt.Fprintf(buf, `<marker id="%s" viewBox="0 0 10 10" refX="9" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M0 0L10 5L0 10z" fill="%s"/></marker>`+"\n", marker[0], marker[1])
+	}
+	buf.WriteString("</defs>\n")
+	fmt.Fprintf(buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="#f8fafc"/>`+"\n", svgNum(x), svgNum(y), svgNum(width), svgNum(height))
+
+	// Las conexiones van debajo de los nodos
+	for _, conn := range r.Connections {
+		from, okFrom := byID[conn.FromNodeID]
+		to, okTo := byID[conn.ToNodeID]
+		if !okFrom || !okTo || from.ID == to.ID {
+			continue
+		}
+		writeSVGConnection(buf, conn, from, to)
+	}
+
+	completed := 0
+	for _, node := range nodes {
+		status := models.ProgressNotStarted
+		if r.Progress != nil {
+			if s, ok := r.Progress[node.ID]; ok {
+				status = s
+			}
+			if status == models.ProgressCompleted {
+				completed++
+			}
+		}
+		writeSVGNode(buf, node, status, r.Progress != nil)
+	}
+
+	if r.Progress != nil {
+		fmt.Fprintf(buf, `<text x="%s" y="%s" font-size="14" fill="#334155">%d de %d completados</text>`+"\n",
+			svgNum(minX), svgNum(maxY+svgPadding+svgCaption/2), completed, len(nodes))
+	}
+	buf.WriteString("</svg>\n")
+}
+
+// writeSVGConnection dibuja una conexión recta entre los bordes de los nodos
+// con su etiqueta en el punto medio
+func writeSVGConnection(buf *bytes.Buffer, conn models.Connection, from, to models.Node) {
+	style, ok := svgEdges[conn.ConnectionType]
+	if !ok {
+		style = svgEdges[models.ConnectionTypeDefault]
+	}
+	fx, fy := boxEdge(from, to)
+	tx, ty := boxEdge(to, from)
+
+	fmt.Fprintf(buf, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s"`,
+		svgNum(fx), svgNum(fy), svgNum(tx), svgNum(ty), style.stroke, style.width)
+	if style.dash != "" {
+		fmt.Fprintf(buf, ` stroke-dasharray="%s"`, style.dash)
+	}
+	if style.marker != "" {
+		fmt.Fprintf(buf, ` marker-end="url(#%s)"`, style.marker)
+	}
+	buf.WriteString("/>\n")
+
+	if label := strings.TrimSpace(conn.Label); label != "" {
+		fmt.Fprintf(buf, `<text x="%s" y="%s" text-anchor="middle" font-size="12" fill="#334155" stroke="#f8fafc" stroke-width="4" paint-order="stroke">%s</text>`+"\n",
+			svgNum((fx+tx)/2), svgNum((fy+ty)/2+4), html.EscapeString(label))
+	}
+}
+
+// boxEdge devuelve el punto del borde del nodo en la dirección del otro nodo
+func boxEdge(node, toward models.Node) (float64, float64) {
+	cx, cy := node.Position.X+models.NodeWidth/2, node.Position.Y+models.NodeHeight/2
+	dx := toward.Position.X + models.NodeWidth/2 - cx
+	dy := toward.Position.Y + models.NodeHeight/2 - cy
+	if dx == 0 && dy == 0 {
+		return cx, cy
+	}
+	t := math.Inf(1)
+	if dx != 0 {
+		t = min(t, models.NodeWidth/2/math.Abs(dx))
+	}
+	if dy != 0 {
+		t = min(t, models.NodeHeight/2/math.Abs(dy))
+	}
+	return cx + t*dx, cy + t*dy
+}
+
+// writeSVGNode dibuja un nodo con la forma de su tipo y su título centrado.
+// Con progress, el estado decide la marca y la opacidad del nodo.
+func writeSVGNode(buf *bytes.Buffer, node models.Node, status models.ProgressStatus, progress bool) {
+	x, y := node.Position.X, node.Position.Y
+	w, h := float64(models.NodeWidth), float64(models.NodeHeight)
+	fill, ok := nodeColor(node.Color)
+	if !ok {
+		fill = "#ffffff"
+	}
+
+	buf.WriteString("<g")
+	if progress && status == models.ProgressNotStarted {
+		buf.WriteString(` opacity="0.5"`)
+	}
+	buf.WriteString(">\n")
+	fmt.Fprintf(buf, "<title>%s</title>\n", html.EscapeString(node.Title))
+
+	style := fmt.Sprintf(`fill="%s" stroke="#1e293b" stroke-width="1.5"`, fill)
+	switch node.Type {
+	case models.NodeTypeResource:
+		// Hoja con la esquina doblada, como la forma note de Graphviz
+		fmt.Fprintf(buf, `<path d="M%s %sH%sL%s %sV%sH%sZ" %s/>`+"\n",
+			svgNum(x), svgNum(y), svgNum(x+w-16), svgNum(x+w), svgNum(y+16), svgNum(y+h), svgNum(x), style)
+	case models.NodeTypeChallenge:
+		fmt.Fprintf(buf, `<polygon points="%s,%s %s,%s %s,%s %s,%s %s,%s %s,%s" %s/>`+"\n",
+			svgNum(x+16), svgNum(y), svgNum(x+w-16), svgNum(y), svgNum(x+w), svgNum(y+h/2),
+			svgNum(x+w-16), svgNum(y+h), svgNum(x+16), svgNum(y+h), svgNum(x), svgNum(y+h/2), style)
+	case models.NodeTypeMilestone:
+		fmt.Fprintf(buf, `<rect x="%s" y="%s" width="%s" height="%s" rx="%s" fill="%s" stroke="#1e293b" stroke-width="3"/>`+"\n",
+			svgNum(x), svgNum(y), svgNum(w), svgNum(h), svgNum(h/2), fill)
+	default:
+		fmt.Fprintf(buf, `<rect x="%s" y="%s" width="%s" height="%s" rx="8" %s/>`+"\n",
+			svgNum(x), svgNum(y), svgNum(w), svgNum(h), style)
+	}
+
+	lines := wrapTitle(node.Title)
+	fmt.Fprintf(buf, `<text x="%s" text-anchor="middle" font-size="14" fill="%s">`, svgNum(x+w/2), textColor(fill))
+	top := y + h/2 - float64(len(lines)-1)*svgLineHeight/2 + 5
+	for i, line := range lines {
+		fmt.Fprintf(buf, `<tspan x="%s" y="%s">%s</tspan>`, svgNum(x+w/2), svgNum(top+float64(i)*svgLineHeight), html.EscapeString(line))
+	}
+	buf.WriteString("</text>\n")
+
+	switch {
+	case progress && status == models.ProgressCompleted:
+		fmt.Fprintf(buf, `<circle cx="%s" cy="%s" r="11" fill="#16a34a" stroke="#ffffff" stroke-width="2"/>`+"\n", svgNum(x+w-4), svgNum(y+4))
+		fmt.Fprintf(buf, `<path d="M%s %sl4 4l7 -8" fill="none" stroke="#ffffff" stroke-width="2.5" stroke-linecap="round" stroke-linejoin="round"/>`+"\n", svgNum(x+w-10), svgNum(y+4))
+	case progress && status == models.ProgressInProgress:
+		fmt.Fprintf(buf, `<circle cx="%s" cy="%s" r="11" fill="#f59e0b" stroke="#ffffff" stroke-width="2"/>`+"\n", svgNum(x+w-4), svgNum(y+4))
+		fmt.Fprintf(buf, `<path d="M%s %sv-5M%s %sh4" fill="none" stroke="#ffffff" stroke-width="2" stroke-linecap="round"/>`+"\n",
+			svgNum(x+w-4), svgNum(y+4), svgNum(x+w-4), svgNum(y+4))
+	}
+	buf.WriteString("</g>\n")
+}
+
+// wrapTitle reparte el título en líneas cortas para que quepa en el nodo. Si
+// no cabe en svgMaxLines la última línea termina en puntos suspensivos.
+func wrapTitle(title string) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(title) {
+		if utf8.RuneCountInString(word) > svgLineRunes {
+			word = string([]rune(word)[:svgLineRunes-1]) + "…"
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= svgLineRunes:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) > svgMaxLines {
+		lines = lines[:svgMaxLines]
+		last := []rune(lines[svgMaxLines-1])
+		if len(last) >= svgLineRunes {
+			last = last[:svgLineRunes-1]
+		}
+		lines[svgMaxLines-1] = string(last) + "…"
+	}
+	return lines
+}
+
+// svgNum escribe una coordenada con un decimal como mucho
+func svgNum(f float64) string {
+	return strconv.FormatFloat(math.Round(f*10)/10, 'f', -1, 64)
+}
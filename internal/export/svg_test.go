@@ -0,0 +1,164 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"Gin/internal/models"
+)
+
+// svg dibuja el roadmap y devuelve el resultado como texto
+func svg(t *testing.T, r Roadmap) string {
+	t.Helper()
+	out, err := Export(FormatSVG, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestSVGNodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   models.NodeType
+		color string
+		want  []string
+	}{
+		{"tema", models.NodeTypeTopic, "#10B981", []string{`rx="8" fill="#10b981" stroke="#1e293b" stroke-width="1.5"/>`, `fill="#ffffff"><tspan`}},
+		{"recurso", models.NodeTypeResource, "#FDE68A", []string{`<path d="M0 0H184L200 16V100H0Z" fill="#fde68a"`, `fill="#000000"><tspan`}},
+		{"reto", models.NodeTypeChallenge, "#abc", []string{`<polygon points="16,0 184,0 200,50 184,100 16,100 0,50" fill="#aabbcc"`}},
+		{"hito", models.NodeTypeMilestone, "#1e293b", []string{`rx="50" fill="#1e293b" stroke="#1e293b" stroke-width="3"/>`, `fill="#ffffff"><tspan`}},
+		{"tipo desconocido", "otro", "", []string{`rx="8" fill="#ffffff"`, `fill="#000000"><tspan`}},
+		{"color inválido", models.NodeTypeTopic, "rgb(0,0,0)", []string{`rx="8" fill="#ffffff"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := svg(t, Roadmap{Snapshot: models.Snapshot{Nodes: []models.Node{
+				{ID: models.NewID(), Title: "Nodo <uno> & dos", Type: tt.typ, Color: tt.color},
+			}}})
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("falta %s en:\n%s", want, out)
+				}
+			}
+			if !strings.Contains(out, "<title>Nodo &lt;uno&gt; &amp; dos</title>") {
+				t.Errorf("el título no se escapa:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestSVGConnections(t *testing.T) {
+	tests := []struct {
+		typ  models.ConnectionType
+		want string
+	}{
+		{models.ConnectionTypeDefault, `stroke="#64748b" stroke-width="2" marker-end="url(#arrow)"/>`},
+		{models.ConnectionTypeStrong, `stroke="#334155" stroke-width="3" marker-end="url(#arrow-strong)"/>`},
+		{models.ConnectionTypeDashed, `stroke="#64748b" stroke-width="2" stroke-dasharray="8 5" marker-end="url(#arrow)"/>`},
+		{models.ConnectionTypeWeak, `stroke="#94a3b8" stroke-width="1.5" stroke-dasharray="2 4"/>`},
+		{"desconocido", `stroke="#64748b" stroke-width="2" marker-end="url(#arrow)"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			a := models.Node{ID: models.NewID(), Title: "A"}
+			b := models.Node{ID: models.NewID(), Title: "B", Position: models.Position{Y: 200}}
+			out := svg(t, Roadmap{Snapshot: models.Snapshot{
+				Nodes:       []models.Node{a, b},
+				Connections: []models.Connection{{ID: models.NewID(), FromNodeID: a.ID, ToNodeID: b.ID, ConnectionType: tt.typ, Label: "y <luego>"}},
+			}})
+			// La línea va del borde inferior de A al superior de B
+			if want := `<line x1="100" y1="100" x2="100" y2="200" ` + tt.want; !strings.Contains(out, want) {
+				t.Errorf("falta %s en:\n%s", want, out)
+			}
+			if !strings.Contains(out, ">y &lt;luego&gt;</text>") {
+				t.Errorf("falta la etiqueta en:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestSVGSkipsInvalidConnections(t *testing.T) {
+	a := models.Node{ID: models.NewID(), Title: "A"}
+	out := svg(t, Roadmap{Snapshot: models.Snapshot{
+		Nodes: []models.Node{a},
+		Connections: []models.Connection{
+			{ID: models.NewID(), FromNodeID: a.ID, ToNodeID: a.ID},
+			{ID: models.NewID(), FromNodeID: a.ID, ToNodeID: models.NewID()},
+		},
+	}})
+	if strings.Contains(out, "<line") {
+		t.Errorf("se dibujan conexiones inválidas:\n%s", out)
+	}
+}
+
+func TestSVGEmptyRoadmap(t *testing.T) {
+	out := svg(t, Roadmap{Title: "Go & más"})
+	for _, want := range []string{`width="400" height="120"`, "<title>Go &amp; más</title>", ">Roadmap vacío</text>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("falta %s en:\n%s", want, out)
+		}
+	}
+}
+
+func TestSVGProgress(t *testing.T) {
+	r, ids := sample()
+	tests := []struct {
+		name     string
+		progress map[models.ID]models.ProgressStatus
+		height   string
+		caption  string
+		faded    int
+		marks    []string
+	}{
+		{"sin progreso", nil, `height="348"`, "", 0, nil},
+		{"sin empezar", map[models.ID]models.ProgressStatus{}, `height="380"`, "0 de 4 completados", 4, nil},
+		{"con progreso", map[models.ID]models.ProgressStatus{
+			ids["fundamentos"]: models.ProgressCompleted,
+			ids["go"]:          models.ProgressInProgress,
+			ids["extra"]:       models.ProgressCompleted,
+		}, `height="380"`, "2 de 4 completados", 1, []string{`fill="#16a34a"`, `fill="#f59e0b"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.Progress = tt.progress
+			out := svg(t, r)
+			if !strings.Contains(out, tt.height) {
+				t.Errorf("falta %s en:\n%s", tt.height, out)
+			}
+			if tt.caption == "" && strings.Contains(out, "completados") || !strings.Contains(out, tt.caption+"</text>") {
+				t.Errorf("total completado: se esperaba %q en:\n%s", tt.caption, out)
+			}
+			if faded := strings.Count(out, `<g opacity="0.5">`); faded != tt.faded {
+				t.Errorf("nodos atenuados = %d, se esperaban %d", faded, tt.faded)
+			}
+			for _, mark := range tt.marks {
+				if !strings.Contains(out, mark) {
+					t.Errorf("falta la marca %s en:\n%s", mark, out)
+				}
+			}
+			if tt.marks == nil && strings.Contains(out, "<circle") {
+				t.Errorf("marcas sin progreso:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestWrapTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Go", "Go"},
+		{"  Fundamentos   de Go  ", "Fundamentos de Go"},
+		{"Programación concurrente con goroutines y canales", "Programación|concurrente con|goroutines y canales"},
+		{"Una palabra extremadamente larguísima", "Una palabra|extremadamente|larguísima"},
+		{"Supercalifragilisticoespialidoso", "Supercalifragilistico…"},
+		{"uno dos tres cuatro cinco seis siete ocho nueve diez once doce trece catorce quince", "uno dos tres cuatro|cinco seis siete ocho|nueve diez once doce…"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(wrapTitle(tt.title), "|"); got != tt.want {
+			t.Errorf("wrapTitle(%q) = %q, se esperaba %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"Gin/internal/models"
+)
+
+// next devuelve los nodos a los que llegan las conexiones de requisito que
+// salen del nodo, sin autoconexiones ni repetidos
+func (g *Graph) next(id models.ID) []models.ID {
+	var next []models.ID
+	seen := make(map[models.ID]bool)
+	for _, conn := range g.out[id] {
+		if !conn.ConnectionType.Prerequisite() || conn.ToNodeID == id || seen[conn.ToNodeID] {
+			continue
+		}
+		seen[conn.ToNodeID] = true
+		next = append(next, conn.ToNodeID)
+	}
+	return next
+}
+
+// requirementPath devuelve el camino más corto de from a to siguiendo las
+// conexiones de requisito y pasando solo por nodos que cumplen allow, o nil
+// si no hay ninguno
+func (g *Graph) requirementPath(from, to models.ID, allow func(models.ID) bool) []models.ID {
+	previous := map[models.ID]models.ID{from: ""}
+	queue := []models.ID{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			path := []models.ID{}
+			for ; id != ""; id = previous[id] {
+				path = append([]models.ID{id}, path...)
+			}
+			return path
+		}
+		for _, next := range g.next(id) {
+			if _, seen := previous[next]; seen || !allow(next) {
+				continue
+			}
+			previous[next] = id
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+// CycleWith devuelve el ciclo de requisitos que cerraría una nueva conexión
+// de requisito de from a to, empezando y terminando en from, o nil si no
+// cerraría ninguno
+func (g *Graph) CycleWith(from, to models.ID) []models.ID {
+	path := g.requirementPath(to, from, func(models.ID) bool { return true })
+	if path == nil {
+		return nil
+	}
+	return append([]models.ID{from}, path...)
+}
+
+// Cycles devuelve un ciclo de requisitos por cada grupo de nodos que se
+// requieren entre sí. Cada ciclo empieza y termina en el mismo nodo; las
+// autoconexiones no cuentan como ciclo.
+func (g *Graph) Cycles() [][]models.ID {
+	cycles := [][]models.ID{}
+	for _, component := range g.components() {
+		if len(component) < 2 {
+			continue
+		}
+		inside := make(map[models.ID]bool, len(component))
+		for _, id := range component {
+			inside[id] = true
+		}
+
+		// El ciclo más corto que pasa por el primer nodo del grupo
+		start := component[0]
+		var shortest []models.ID
+		for _, next := range g.next(start) {
+			if !inside[next] {
+				continue
+			}
+			path := g.requirementPath(next, start, func(id models.ID) bool { return inside[id] })
+			if path != nil && (shortest == nil || len(path) < len(shortest)) {
+				shortest = path
+			}
+		}
+		cycles = append(cycles, append([]models.ID{start}, shortest...))
+	}
+	return cycles
+}
+
+// components agrupa los nodos en componentes fuertemente conexas según las
+// conexiones de requisito (algoritmo de Tarjan). Cada componente conserva el
+// orden original de sus nodos y las componentes se ordenan por su primer nodo.
+func (g *Graph) components() [][]models.ID {
+	index := make(map[models.ID]int, len(g.nodes))
+	low := make(map[models.ID]int, len(g.nodes))
+	onStack := make(map[models.ID]bool, len(g.nodes))
+	var stack []models.ID
+	var found [][]models.ID
+
+	var visit func(id models.ID)
+	visit = func(id models.ID) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, next := range g.next(id) {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				low[id] = min(low[id], low[next])
+			} else if onStack[next] {
+				low[id] = min(low[id], index[next])
+			}
+		}
+
+		if low[id] == index[id] {
+			var component []models.ID
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			found = append(found, component)
+		}
+	}
+
+	for _, node := range g.nodes {
+		if _, visited := index[node.ID]; !visited {
+			visit(node.ID)
+		}
+	}
+
+	// Ordenar según el orden original de los nodos
+	component := make(map[models.ID]int, len(g.nodes))
+	for i, ids := range found {
+		for _, id := range ids {
+			component[id] = i
+		}
+	}
+	ordered := make([][]models.ID, len(found))
+	var order []int
+	for _, node := range g.nodes {
+		i := component[node.ID]
+		if ordered[i] == nil {
+			order = append(order, i)
+		}
+		ordered[i] = append(ordered[i], node.ID)
+	}
+
+	components := make([][]models.ID, 0, len(order))
+	for _, i := range order {
+		components = append(components, ordered[i])
+	}
+	return components
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"testing"
+
+	"Gin/internal/models"
+)
+
+func TestCycles(t *testing.T) {
+	strong, normal := models.ConnectionTypeStrong, models.ConnectionTypeDefault
+	tests := []struct {
+		name   string
+		edges  []edge
+		cycles []string
+	}{
+		{"sin ciclos", []edge{{"A", "B", strong}, {"B", "C", strong}, {"A", "C", strong}}, nil},
+		{"dos nodos", []edge{{"A", "B", strong}, {"B", "A", strong}}, []string{"A,B,A"}},
+		{"el más corto", []edge{{"A", "B", strong}, {"B", "C", strong}, {"C", "A", strong}, {"B", "A", strong}}, []string{"A,B,A"}},
+		{"dos grupos", []edge{{"A", "B", strong}, {"B", "A", strong}, {"C", "D", strong}, {"D", "C", strong}}, []string{"A,B,A", "C,D,C"}},
+		{"conexiones normales", []edge{{"A", "B", normal}, {"B", "A", normal}}, nil},
+		{"autoconexión", []edge{{"A", "A", strong}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, connections, _ := build([]string{"A", "B", "C", "D"}, tt.edges...)
+			g := New(nodes, connections)
+			cycles := g.Cycles()
+			if len(cycles) != len(tt.cycles) {
+				t.Fatalf("ciclos = %v, se esperaban %v", cycles, tt.cycles)
+			}
+			for i, cycle := range cycles {
+				var path []models.Node
+				for _, id := range cycle {
+					node, _ := g.Node(id)
+					path = append(path, node)
+				}
+				if got := titles(path); got != tt.cycles[i] {
+					t.Errorf("ciclo %d = %s, se esperaba %s", i, got, tt.cycles[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCycleWith(t *testing.T) {
+	nodes, connections, ids := build([]string{"A", "B", "C"},
+		edge{"A", "B", models.ConnectionTypeStrong},
+		edge{"B", "C", models.ConnectionTypeStrong},
+	)
+	g := New(nodes, connections)
+
+	if cycle := g.CycleWith(ids["C"], ids["A"]); len(cycle) != 4 || cycle[0] != ids["C"] || cycle[3] != ids["C"] {
+		t.Errorf("C → A cerraría el ciclo %v", cycle)
+	}
+	if cycle := g.CycleWith(ids["A"], ids["C"]); cycle != nil {
+		t.Errorf("A → C no cierra ningún ciclo, pero se obtuvo %v", cycle)
+	}
+}
@@ -0,0 +1,91 @@
+// Package graph analiza la estructura de un roadmap como grafo dirigido: los
+// requisitos entre nodos, los ciclos y la integridad de las conexiones.
+package graph
+
+import (
+	"Gin/internal/models"
+)
+
+// Graph es el grafo dirigido formado por los nodos y las conexiones de un
+// roadmap. Las conexiones cuyos extremos no están entre los nodos se ignoran.
+type Graph struct {
+	nodes []models.Node
+	index map[models.ID]int
+	in    map[models.ID][]models.Connection
+	out   map[models.ID][]models.Connection
+}
+
+// New construye el grafo conservando el orden de los nodos
+func New(nodes []models.Node, connections []models.Connection) *Graph {
+	g := &Graph{
+		nodes: nodes,
+		index: make(map[models.ID]int, len(nodes)),
+		in:    make(map[models.ID][]models.Connection),
+		out:   make(map[models.ID][]models.Connection),
+	}
+	for i, node := range nodes {
+		g.index[node.ID] = i
+	}
+	for _, conn := range connections {
+		if !g.Has(conn.FromNodeID) || !g.Has(conn.ToNodeID) {
+			continue
+		}
+		g.out[conn.FromNodeID] = append(g.out[conn.FromNodeID], conn)
+		g.in[conn.ToNodeID] = append(g.in[conn.ToNodeID], conn)
+	}
+	return g
+}
+
+// Has indica si el nodo forma parte del grafo
+func (g *Graph) Has(id models.ID) bool {
+	_, ok := g.index[id]
+	return ok
+}
+
+// Node devuelve el nodo con el id indicado
+func (g *Graph) Node(id models.ID) (models.Node, bool) {
+	i, ok := g.index[id]
+	if !ok {
+		return models.Node{}, false
+	}
+	return g.nodes[i], true
+}
+
+// Nodes devuelve los nodos del grafo en su orden original
+func (g *Graph) Nodes() []models.Node {
+	return g.nodes
+}
+
+// Incoming devuelve las conexiones que llegan al nodo
+func (g *Graph) Incoming(id models.ID) []models.Connection {
+	return g.in[id]
+}
+
+// Outgoing devuelve las conexiones que salen del nodo
+func (g *Graph) Outgoing(id models.ID) []models.Connection {
+	return g.out[id]
+}
+
+// Reachable devuelve los nodos a los que se llega desde los indicados
+// siguiendo conexiones de cualquier tipo, incluidos los de partida
+func (g *Graph) Reachable(from ...models.ID) map[models.ID]bool {
+	reached := make(map[models.ID]bool, len(g.nodes))
+	queue := []models.ID{}
+	for _, id := range from {
+		if g.Has(id) && !reached[id] {
+			reached[id] = true
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, conn := range g.out[id] {
+			if !reached[conn.ToNodeID] {
+				reached[conn.ToNodeID] = true
+				queue = append(queue, conn.ToNodeID)
+			}
+		}
+	}
+	return reached
+}
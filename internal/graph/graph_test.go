@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"Gin/internal/models"
+)
+
+// edge es una conexión entre dos nodos por su título
+type edge struct {
+	from, to string
+	kind     models.ConnectionType
+}
+
+// build construye los nodos con los títulos indicados, de arriba abajo en ese
+// orden, y las conexiones entre ellos. Devuelve también el id de cada título.
+func build(titles []string, edges ...edge) ([]models.Node, []models.Connection, map[string]models.ID) {
+	ids := make(map[string]models.ID, len(titles))
+	nodes := make([]models.Node, len(titles))
+	for i, title := range titles {
+		nodes[i] = models.Node{ID: models.NewID(), Title: title, Position: models.Position{Y: float64(i * 100)}}
+		ids[title] = nodes[i].ID
+	}
+	connections := make([]models.Connection, len(edges))
+	for i, e := range edges {
+		connections[i] = models.Connection{ID: models.NewID(), FromNodeID: ids[e.from], ToNodeID: ids[e.to], ConnectionType: e.kind}
+	}
+	return nodes, connections, ids
+}
+
+// titles une los títulos de los nodos para compararlos fácilmente
+func titles(nodes []models.Node) string {
+	names := make([]string, len(nodes))
+	for i, node := range nodes {
+		names[i] = node.Title
+	}
+	return strings.Join(names, ",")
+}
+
+func TestNewIgnoresDanglingConnections(t *testing.T) {
+	nodes, connections, ids := build([]string{"A", "B"}, edge{"A", "B", models.ConnectionTypeStrong})
+	connections = append(connections, models.Connection{ID: models.NewID(), FromNodeID: ids["A"], ToNodeID: models.NewID()})
+
+	g := New(nodes, connections)
+	if len(g.Outgoing(ids["A"])) != 1 || len(g.Incoming(ids["B"])) != 1 {
+		t.Errorf("salida de A = %v, entrada de B = %v", g.Outgoing(ids["A"]), g.Incoming(ids["B"]))
+	}
+	if _, ok := g.Node(models.NewID()); ok {
+		t.Error("Node encuentra un nodo que no existe")
+	}
+}
+
+func TestReachable(t *testing.T) {
+	nodes, connections, ids := build([]string{"A", "B", "C", "D"},
+		edge{"A", "B", models.ConnectionTypeDefault},
+		edge{"B", "C", models.ConnectionTypeDashed},
+	)
+	reached := New(nodes, connections).Reachable(ids["A"])
+	if len(reached) != 3 || !reached[ids["C"]] || reached[ids["D"]] {
+		t.Errorf("alcanzados = %v", reached)
+	}
+}
+
+func TestPrerequisites(t *testing.T) {
+	nodes, connections, ids := build([]string{"A", "B", "C", "D"},
+		edge{"A", "C", models.ConnectionTypeStrong},
+		edge{"B", "C", models.ConnectionTypeStrong},
+		edge{"D", "C", models.ConnectionTypeWeak},
+		edge{"C", "C", models.ConnectionTypeStrong},
+	)
+	g := New(nodes, connections)
+
+	if got := titles(g.Prerequisites(ids["C"])); got != "A,B" {
+		t.Errorf("requisitos de C = %s", got)
+	}
+
+	tests := []struct {
+		name      string
+		completed []string
+		missing   string
+		unlocked  string
+	}{
+		{"sin progreso", nil, "A,B", "A,B,D"},
+		{"un requisito", []string{"A"}, "B", "B,D"},
+		{"todos los requisitos", []string{"A", "B"}, "", "C,D"},
+		{"todo completado", []string{"A", "B", "C", "D"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statuses := make(map[models.ID]models.ProgressStatus)
+			for _, title := range tt.completed {
+				statuses[ids[title]] = models.ProgressCompleted
+			}
+			if got := titles(g.Missing(ids["C"], statuses)); got != tt.missing {
+				t.Errorf("faltan = %s, se esperaba %s", got, tt.missing)
+			}
+			if got := titles(g.Unlocked(statuses)); got != tt.unlocked {
+				t.Errorf("desbloqueados = %s, se esperaba %s", got, tt.unlocked)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+
+	"Gin/internal/models"
+)
+
+// ErrCycle indica que los requisitos forman un ciclo y no hay orden posible
+var ErrCycle = errors.New("los requisitos forman un ciclo")
+
+// Levels ordena topológicamente los nodos en niveles: cada nivel contiene los
+// nodos que pueden estudiarse en paralelo una vez completados los anteriores.
+// Todas las conexiones marcan orden, pero solo los requisitos son
+// obligatorios: si el resto forma un ciclo, se ignoran las que llegan al
+// primer nodo del ciclo. Dentro de un nivel los nodos se ordenan con Less.
+func (g *Graph) Levels() ([][]models.Node, error) {
+	// Conexiones de entrada pendientes de cada nodo, sin autoconexiones
+	pending := make(map[models.ID]int, len(g.nodes))
+	required := make(map[models.ID]int, len(g.nodes))
+	for _, node := range g.nodes {
+		for _, conn := range g.in[node.ID] {
+			if conn.FromNodeID == node.ID {
+				continue
+			}
+			pending[node.ID]++
+			if conn.ConnectionType.Prerequisite() {
+				required[node.ID]++
+			}
+		}
+	}
+
+	done := make(map[models.ID]bool, len(g.nodes))
+	levels := [][]models.Node{}
+	for len(done) < len(g.nodes) {
+		var level []models.Node
+		for _, node := range g.nodes {
+			if !done[node.ID] && pending[node.ID] == 0 {
+				level = append(level, node)
+			}
+		}
+		// Solo quedan ciclos: se rompen empezando por el primer nodo sin
+		// requisitos pendientes
+		if len(level) == 0 {
+			for _, node := range g.nodes {
+				if !done[node.ID] && required[node.ID] == 0 && (len(level) == 0 || Less(node, level[0])) {
+					level = []models.Node{node}
+				}
+			}
+		}
+		if len(level) == 0 {
+			return nil, ErrCycle
+		}
+
+		sort.SliceStable(level, func(i, j int) bool { return Less(level[i], level[j]) })
+		for _, node := range level {
+			done[node.ID] = true
+		}
+		for _, node := range level {
+			for _, conn := range g.out[node.ID] {
+				if conn.ToNodeID == node.ID || done[conn.ToNodeID] {
+					continue
+				}
+				pending[conn.ToNodeID]--
+				if conn.ConnectionType.Prerequisite() {
+					required[conn.ToNodeID]--
+				}
+			}
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// Order devuelve los nodos en orden de estudio: los niveles uno tras otro
+func (g *Graph) Order() ([]models.Node, error) {
+	levels, err := g.Levels()
+	if err != nil {
+		return nil, err
+	}
+	order := make([]models.Node, 0, len(g.nodes))
+	for _, level := range levels {
+		order = append(order, level...)
+	}
+	return order, nil
+}
+
+// Less ordena los nodos por su OrderIndex, dejando al final los que no tienen,
+// y después por su posición de arriba abajo y de izquierda a derecha
+func Less(a, b models.Node) bool {
+	if (a.OrderIndex == nil) != (b.OrderIndex == nil) {
+		return a.OrderIndex != nil
+	}
+	if a.OrderIndex != nil && *a.OrderIndex != *b.OrderIndex {
+		return *a.OrderIndex < *b.OrderIndex
+	}
+	if a.Position.Y != b.Position.Y {
+		return a.Position.Y < b.Position.Y
+	}
+	return a.Position.X < b.Position.X
+}
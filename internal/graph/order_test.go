@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"Gin/internal/models"
+)
+
+func TestLevels(t *testing.T) {
+	strong, normal := models.ConnectionTypeStrong, models.ConnectionTypeDefault
+	tests := []struct {
+		name   string
+		edges  []edge
+		levels string // niveles separados por |
+	}{
+		{"sin conexiones", nil, "A,B,C,D"},
+		{"cadena", []edge{{"A", "B", strong}, {"B", "C", normal}, {"C", "D", strong}}, "A|B|C|D"},
+		{"en paralelo", []edge{{"A", "C", strong}, {"B", "C", strong}, {"C", "D", normal}}, "A,B|C|D"},
+		{"orden por posición", []edge{{"D", "A", normal}}, "B,C,D|A"},
+		{"ciclo de conexiones normales", []edge{{"A", "B", normal}, {"B", "A", normal}, {"C", "D", strong}}, "C|D|A|B"},
+		{"ciclo roto por el nodo sin requisitos", []edge{{"A", "B", normal}, {"B", "A", strong}}, "C,D|B|A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, connections, _ := build([]string{"A", "B", "C", "D"}, tt.edges...)
+			levels, err := New(nodes, connections).Levels()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(levels))
+			for i, level := range levels {
+				got[i] = titles(level)
+			}
+			if strings.Join(got, "|") != tt.levels {
+				t.Errorf("niveles = %s, se esperaba %s", strings.Join(got, "|"), tt.levels)
+			}
+		})
+	}
+}
+
+func TestOrderRequirementCycle(t *testing.T) {
+	nodes, connections, _ := build([]string{"A", "B"},
+		edge{"A", "B", models.ConnectionTypeStrong},
+		edge{"B", "A", models.ConnectionTypeStrong},
+	)
+	if _, err := New(nodes, connections).Order(); !errors.Is(err, ErrCycle) {
+		t.Errorf("err = %v, se esperaba ErrCycle", err)
+	}
+}
+
+func TestLess(t *testing.T) {
+	one, two := 1, 2
+	tests := []struct {
+		name string
+		a, b models.Node
+	}{
+		{"orden indicado", models.Node{OrderIndex: &one, Position: models.Position{Y: 500}}, models.Node{OrderIndex: &two}},
+		{"con orden antes que sin él", models.Node{OrderIndex: &two, Position: models.Position{Y: 500}}, models.Node{}},
+		{"de arriba abajo", models.Node{Position: models.Position{X: 500, Y: 0}}, models.Node{Position: models.Position{Y: 100}}},
+		{"de izquierda a derecha", models.Node{Position: models.Position{X: 0}}, models.Node{Position: models.Position{X: 100}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !Less(tt.a, tt.b) || Less(tt.b, tt.a) {
+				t.Errorf("Less(a, b) = %v, Less(b, a) = %v", Less(tt.a, tt.b), Less(tt.b, tt.a))
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"container/heap"
+
+	"Gin/internal/models"
+)
+
+// DefaultEstimate son las horas que se suponen para un nodo sin estimación
+const DefaultEstimate = 1.0
+
+// Effort devuelve las horas estimadas del nodo, o DefaultEstimate si no tiene
+func Effort(node models.Node) float64 {
+	if node.Estimate == nil || *node.Estimate <= 0 {
+		return DefaultEstimate
+	}
+	return *node.Estimate
+}
+
+// PathTo devuelve los nodos sin completar según statuses que hacen falta para
+// llegar a target, incluido él mismo, en orden de estudio. Un nodo con
+// requisitos necesita todos ellos; uno sin requisitos necesita solo uno de los
+// nodos que llegan a él por una conexión normal, el más barato según las horas
+// estimadas. Las conexiones débiles y discontinuas no obligan a nada. Si
+// target ya está completado el camino está vacío.
+func (g *Graph) PathTo(target models.ID, statuses map[models.ID]models.ProgressStatus) ([]models.Node, error) {
+	completed := func(id models.ID) bool { return statuses[id] == models.ProgressCompleted }
+	if completed(target) {
+		return []models.Node{}, nil
+	}
+
+	choice := g.cheapestRoutes(completed)
+	if _, ok := choice[target]; !ok {
+		return nil, ErrCycle
+	}
+
+	// Se recorren hacia atrás las elecciones desde target
+	needed := make(map[models.ID]bool)
+	var visit func(id models.ID)
+	visit = func(id models.ID) {
+		if needed[id] || completed(id) {
+			return
+		}
+		needed[id] = true
+		for _, prev := range choice[id] {
+			visit(prev)
+		}
+	}
+	visit(target)
+
+	var nodes []models.Node
+	for _, node := range g.nodes {
+		if needed[node.ID] {
+			nodes = append(nodes, node)
+		}
+	}
+	var connections []models.Connection
+	for _, node := range nodes {
+		connections = append(connections, g.out[node.ID]...)
+	}
+	return New(nodes, connections).Order()
+}
+
+// cheapestRoutes calcula para cada nodo al que se puede llegar los nodos de
+// los que depende en el camino más barato: todos sus requisitos, o el nodo de
+// entrada de menor coste si no tiene. Es una variante de Dijkstra en la que un
+// nodo con requisitos se cierra cuando se han cerrado todos ellos, y su coste
+// es la suma de los de sus requisitos más el suyo. Los nodos completados
+// cuestan cero. Los nodos sin entrada posible, como los de un ciclo de
+// conexiones normales, se toman como punto de partida empezando por el
+// primero según Less.
+func (g *Graph) cheapestRoutes(completed func(models.ID) bool) map[models.ID][]models.ID {
+	cost := func(node models.Node) float64 {
+		if completed(node.ID) {
+			return 0
+		}
+		return Effort(node)
+	}
+
+	// Requisitos de cada nodo, cuántos quedan por cerrar y coste acumulado de
+	// los ya cerrados
+	prerequisites := make(map[models.ID][]models.ID, len(g.nodes))
+	remaining := make(map[models.ID]int, len(g.nodes))
+	required := make(map[models.ID]float64, len(g.nodes))
+	hasEntry := make(map[models.ID]bool, len(g.nodes))
+	for _, node := range g.nodes {
+		for _, prerequisite := range g.Prerequisites(node.ID) {
+			prerequisites[node.ID] = append(prerequisites[node.ID], prerequisite.ID)
+		}
+		remaining[node.ID] = len(prerequisites[node.ID])
+		for _, conn := range g.in[node.ID] {
+			if conn.FromNodeID != node.ID && conn.ConnectionType == models.ConnectionTypeDefault {
+				hasEntry[node.ID] = true
+			}
+		}
+	}
+
+	dist := make(map[models.ID]float64, len(g.nodes))
+	choice := make(map[models.ID][]models.ID, len(g.nodes))
+	queue := &routeQueue{graph: g}
+	push := func(id models.ID, d float64, from []models.ID) {
+		if old, ok := dist[id]; ok && old <= d {
+			return
+		}
+		dist[id] = d
+		choice[id] = from
+		heap.Push(queue, route{id: id, dist: d})
+	}
+
+	for _, node := range g.nodes {
+		switch {
+		case completed(node.ID):
+			push(node.ID, 0, nil)
+		case remaining[node.ID] == 0 && !hasEntry[node.ID]:
+			push(node.ID, cost(node), nil)
+		}
+	}
+
+	closed := make(map[models.ID]bool, len(g.nodes))
+	for len(closed) < len(g.nodes) {
+		if queue.Len() == 0 {
+			// Sin más nodos alcanzables se parte del primer nodo abierto sin
+			// requisitos
+			var start *models.Node
+			for i, node := range g.nodes {
+				if !closed[node.ID] && len(prerequisites[node.ID]) == 0 && (start == nil || Less(node, *start)) {
+					start = &g.nodes[i]
+				}
+			}
+			if start == nil {
+				// Solo quedan nodos en ciclos de requisitos
+				break
+			}
+			push(start.ID, cost(*start), nil)
+		}
+
+		r := heap.Pop(queue).(route)
+		if closed[r.id] || r.dist > dist[r.id] {
+			continue
+		}
+		closed[r.id] = true
+
+		for _, conn := range g.out[r.id] {
+			to := conn.ToNodeID
+			if to == r.id || closed[to] {
+				continue
+			}
+			node, _ := g.Node(to)
+			if len(prerequisites[to]) > 0 {
+				if !conn.ConnectionType.Prerequisite() {
+					continue
+				}
+				remaining[to]--
+				required[to] += r.dist
+				if remaining[to] == 0 {
+					push(to, required[to]+cost(node), prerequisites[to])
+				}
+			} else if conn.ConnectionType == models.ConnectionTypeDefault {
+				push(to, r.dist+cost(node), []models.ID{r.id})
+			}
+		}
+	}
+
+	// Solo se devuelven las elecciones de los nodos cerrados
+	for id := range choice {
+		if !closed[id] {
+			delete(choice, id)
+		}
+	}
+	return choice
+}
+
+// route es una entrada de la cola de prioridad de cheapestRoutes
+type route struct {
+	id   models.ID
+	dist float64
+}
+
+// routeQueue ordena las rutas por coste y, a igual coste, por el orden de
+// los nodos según Less
+type routeQueue struct {
+	graph  *Graph
+	routes []route
+}
+
+func (q *routeQueue) Len() int { return len(q.routes) }
+
+func (q *routeQueue) Less(i, j int) bool {
+	a, b := q.routes[i], q.routes[j]
+	if a.dist != b.dist {
+		return a.dist < b.dist
+	}
+	na, _ := q.graph.Node(a.id)
+	nb, _ := q.graph.Node(b.id)
+	return Less(na, nb)
+}
+
+func (q *routeQueue) Swap(i, j int) { q.routes[i], q.routes[j] = q.routes[j], q.routes[i] }
+
+func (q *routeQueue) Push(x any) { q.routes = append(q.routes, x.(route)) }
+
+func (q *routeQueue) Pop() any {
+	last := q.routes[len(q.routes)-1]
+	q.routes = q.routes[:len(q.routes)-1]
+	return last
+}
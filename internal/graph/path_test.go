@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+
+	"Gin/internal/models"
+)
+
+func TestPathTo(t *testing.T) {
+	strong, normal, weak := models.ConnectionTypeStrong, models.ConnectionTypeDefault, models.ConnectionTypeWeak
+	tests := []struct {
+		name      string
+		edges     []edge
+		hours     map[string]float64
+		completed []string
+		target    string
+		path      string
+	}{
+		{"entrada más barata", []edge{{"A", "C", normal}, {"B", "C", normal}}, map[string]float64{"A": 5, "B": 1}, nil, "C", "B,C"},
+		{"todos los requisitos", []edge{{"A", "C", strong}, {"B", "C", strong}}, nil, nil, "C", "A,B,C"},
+		{"cadena", []edge{{"A", "B", normal}, {"B", "C", strong}, {"C", "D", normal}}, nil, nil, "D", "A,B,C,D"},
+		{"sin los completados", []edge{{"A", "B", normal}, {"B", "C", strong}}, nil, []string{"A"}, "C", "B,C"},
+		{"ruta ya completada", []edge{{"A", "C", normal}, {"B", "C", normal}}, map[string]float64{"A": 5, "B": 1}, []string{"A"}, "C", "C"},
+		{"objetivo completado", []edge{{"A", "B", strong}}, nil, []string{"B"}, "B", ""},
+		{"las débiles no obligan", []edge{{"A", "B", weak}}, nil, nil, "B", "B"},
+		{"requisitos más caros por otra entrada", []edge{{"A", "C", strong}, {"B", "C", normal}}, map[string]float64{"A": 8}, nil, "C", "A,C"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, connections, ids := build([]string{"A", "B", "C", "D"}, tt.edges...)
+			for i := range nodes {
+				if hours, ok := tt.hours[nodes[i].Title]; ok {
+					nodes[i].Estimate = &hours
+				}
+			}
+			statuses := make(map[models.ID]models.ProgressStatus)
+			for _, title := range tt.completed {
+				statuses[ids[title]] = models.ProgressCompleted
+			}
+
+			path, err := New(nodes, connections).PathTo(ids[tt.target], statuses)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(path); got != tt.path {
+				t.Errorf("camino = %s, se esperaba %s", got, tt.path)
+			}
+		})
+	}
+}
+
+func TestPathToRequirementCycle(t *testing.T) {
+	nodes, connections, ids := build([]string{"A", "B", "C"},
+		edge{"A", "B", models.ConnectionTypeStrong},
+		edge{"B", "A", models.ConnectionTypeStrong},
+		edge{"A", "C", models.ConnectionTypeStrong},
+	)
+	if _, err := New(nodes, connections).PathTo(ids["C"], nil); !errors.Is(err, ErrCycle) {
+		t.Errorf("err = %v, se esperaba ErrCycle", err)
+	}
+}
+
+func TestEffort(t *testing.T) {
+	hours, zero := 3.0, 0.0
+	if got := Effort(models.Node{Estimate: &hours}); got != 3 {
+		t.Errorf("Effort = %v", got)
+	}
+	if got := Effort(models.Node{Estimate: &zero}); got != DefaultEstimate {
+		t.Errorf("Effort sin horas válidas = %v", got)
+	}
+	if got := Effort(models.Node{}); got != DefaultEstimate {
+		t.Errorf("Effort sin estimación = %v", got)
+	}
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"Gin/internal/models"
+)
+
+// Prerequisites devuelve los requisitos directos del nodo: los nodos de los
+// que sale una conexión de requisito hacia él, sin repetir
+func (g *Graph) Prerequisites(id models.ID) []models.Node {
+	prerequisites := []models.Node{}
+	seen := make(map[models.ID]bool)
+	for _, conn := range g.in[id] {
+		if !conn.ConnectionType.Prerequisite() || conn.FromNodeID == id || seen[conn.FromNodeID] {
+			continue
+		}
+		seen[conn.FromNodeID] = true
+		node, _ := g.Node(conn.FromNodeID)
+		prerequisites = append(prerequisites, node)
+	}
+	return prerequisites
+}
+
+// Missing devuelve los requisitos del nodo que todavía no están completados
+// según statuses
+func (g *Graph) Missing(id models.ID, statuses map[models.ID]models.ProgressStatus) []models.Node {
+	missing := []models.Node{}
+	for _, node := range g.Prerequisites(id) {
+		if statuses[node.ID] != models.ProgressCompleted {
+			missing = append(missing, node)
+		}
+	}
+	return missing
+}
+
+// Unlocked devuelve los nodos sin completar cuyos requisitos ya están todos
+// completados según statuses, en el orden de los nodos
+func (g *Graph) Unlocked(statuses map[models.ID]models.ProgressStatus) []models.Node {
+	unlocked := []models.Node{}
+	for _, node := range g.nodes {
+		if statuses[node.ID] != models.ProgressCompleted && len(g.Missing(node.ID, statuses)) == 0 {
+			unlocked = append(unlocked, node)
+		}
+	}
+	return unlocked
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"fmt"
+
+	"Gin/internal/models"
+)
+
+// IssueKind es el tipo de problema de integridad de un roadmap
+type IssueKind string
+
+const (
+	// IssueMissingNode: la conexión referencia un nodo que no está en el roadmap
+	IssueMissingNode IssueKind = "missing_node"
+	// IssueSelfLoop: la conexión une un nodo consigo mismo
+	IssueSelfLoop IssueKind = "self_loop"
+	// IssueDuplicateConnection: ya hay otra conexión entre los mismos nodos
+	IssueDuplicateConnection IssueKind = "duplicate_connection"
+	// IssueInvalidConnectionType: la conexión tiene un tipo desconocido
+	IssueInvalidConnectionType IssueKind = "invalid_connection_type"
+	// IssueCycle: varios nodos se requieren entre sí
+	IssueCycle IssueKind = "cycle"
+)
+
+// Issue es un problema de integridad de un roadmap. En los ciclos, Nodes es
+// el camino del ciclo, que empieza y termina en el mismo nodo.
+type Issue struct {
+	Kind        IssueKind   `json:"kind"`
+	Message     string      `json:"message"`
+	Nodes       []models.ID `json:"nodes,omitempty"`
+	Connections []models.ID `json:"connections,omitempty"`
+}
+
+// Validate comprueba la integridad de los nodos y conexiones de un roadmap y
+// devuelve todos los problemas encontrados
+func Validate(nodes []models.Node, connections []models.Connection) []Issue {
+	issues := []Issue{}
+	exists := make(map[models.ID]bool, len(nodes))
+	for _, node := range nodes {
+		exists[node.ID] = true
+	}
+
+	var valid []models.Connection
+	seen := make(map[[2]models.ID]models.ID, len(connections))
+	for _, conn := range connections {
+		switch key := [2]models.ID{conn.FromNodeID, conn.ToNodeID}; {
+		case !exists[conn.FromNodeID] || !exists[conn.ToNodeID]:
+			issues = append(issues, Issue{
+				Kind:        IssueMissingNode,
+				Message:     "La conexión referencia un nodo que no existe en el roadmap",
+				Connections: []models.ID{conn.ID},
+			})
+		case conn.FromNodeID == conn.ToNodeID:
+			issues = append(issues, Issue{
+				Kind:        IssueSelfLoop,
+				Message:     "Un nodo no puede conectarse consigo mismo",
+				Nodes:       []models.ID{conn.FromNodeID},
+				Connections: []models.ID{conn.ID},
+			})
+		case seen[key] != "":
+			issues = append(issues, Issue{
+				Kind:        IssueDuplicateConnection,
+				Message:     "Ya existe una conexión entre estos nodos",
+				Nodes:       []models.ID{conn.FromNodeID, conn.ToNodeID},
+				Connections: []models.ID{seen[key], conn.ID},
+			})
+		case !conn.ConnectionType.Valid():
+			issues = append(issues, Issue{
+				Kind:        IssueInvalidConnectionType,
+				Message:     fmt.Sprintf("Tipo de conexión inválido: %s", conn.ConnectionType),
+				Connections: []models.ID{conn.ID},
+			})
+		default:
+			seen[key] = conn.ID
+			valid = append(valid, conn)
+		}
+	}
+
+	for _, cycle := range New(nodes, valid).Cycles() {
+		issues = append(issues, Issue{
+			Kind:    IssueCycle,
+			Message: "Los nodos forman un ciclo de requisitos",
+			Nodes:   cycle,
+		})
+	}
+	return issues
+}
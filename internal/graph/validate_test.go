@@ -0,0 +1,42 @@
+package graph
+
+import (
+	"testing"
+
+	"Gin/internal/models"
+)
+
+func TestValidate(t *testing.T) {
+	nodes, _, ids := build([]string{"A", "B"})
+	conn := func(from, to models.ID, kind models.ConnectionType) models.Connection {
+		return models.Connection{ID: models.NewID(), FromNodeID: from, ToNodeID: to, ConnectionType: kind}
+	}
+	a, b := ids["A"], ids["B"]
+
+	tests := []struct {
+		name        string
+		connections []models.Connection
+		kinds       []IssueKind
+	}{
+		{"válido", []models.Connection{conn(a, b, models.ConnectionTypeStrong)}, nil},
+		{"nodo inexistente", []models.Connection{conn(a, models.NewID(), models.ConnectionTypeDefault)}, []IssueKind{IssueMissingNode}},
+		{"autoconexión", []models.Connection{conn(a, a, models.ConnectionTypeDefault)}, []IssueKind{IssueSelfLoop}},
+		{"duplicada", []models.Connection{conn(a, b, models.ConnectionTypeDefault), conn(a, b, models.ConnectionTypeWeak)}, []IssueKind{IssueDuplicateConnection}},
+		{"tipo inválido", []models.Connection{conn(a, b, "otro")}, []IssueKind{IssueInvalidConnectionType}},
+		{"ciclo", []models.Connection{conn(a, b, models.ConnectionTypeStrong), conn(b, a, models.ConnectionTypeStrong)}, []IssueKind{IssueCycle}},
+		{"varios", []models.Connection{conn(a, a, models.ConnectionTypeDefault), conn(b, a, "otro")}, []IssueKind{IssueSelfLoop, IssueInvalidConnectionType}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := Validate(nodes, tt.connections)
+			if len(issues) != len(tt.kinds) {
+				t.Fatalf("problemas = %+v, se esperaban %v", issues, tt.kinds)
+			}
+			for i, issue := range issues {
+				if issue.Kind != tt.kinds[i] {
+					t.Errorf("problema %d = %s, se esperaba %s", i, issue.Kind, tt.kinds[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,471 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type AssignmentHandler struct {
+	store repository.Store
+}
+
+func NewAssignmentHandler(store repository.Store) *AssignmentHandler {
+	return &AssignmentHandler{store: store}
+}
+
+// assignmentRequest son los datos de una asignación. Sin node_ids se asigna
+// el roadmap completo.
+type assignmentRequest struct {
+	Title       string      `json:"title" binding:"required"`
+	Description string      `json:"description"`
+	NodeIDs     []models.ID `json:"node_ids"`
+	DueAt       time.Time   `json:"due_at" binding:"required"`
+}
+
+// apply valida los nodos de la petición y la copia en la asignación. Si no
+// son válidos responde 400 y devuelve false.
+func (req *assignmentRequest) apply(c *gin.Context, store repository.Store, assignment *models.Assignment) bool {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El título no puede estar vacío"})
+		return false
+	}
+
+	nodeIDs := []models.ID{}
+	seen := make(map[models.ID]bool, len(req.NodeIDs))
+	for _, nodeID := range req.NodeIDs {
+		if seen[nodeID] {
+			continue
+		}
+		seen[nodeID] = true
+
+		_, err := store.Nodes().GetByID(assignment.RoadmapID, nodeID)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Nodo no encontrado: %s", nodeID)})
+			return false
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar los nodos"})
+			return false
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+
+	assignment.Title = title
+	assignment.Description = req.Description
+	assignment.AllNodes = len(nodeIDs) == 0
+	assignment.NodeIDs = nodeIDs
+	assignment.DueAt = req.DueAt
+	return true
+}
+
+// ListAssignments devuelve las asignaciones del roadmap con el recuento de
+// asignados por estado. Con overdue=true solo las que tienen algún asignado
+// fuera de plazo.
+func (h *AssignmentHandler) ListAssignments(c *gin.Context) {
+	onlyOverdue := c.Query("overdue") == "true"
+
+	assignments, err := h.store.Assignments().ListByRoadmap(middleware.GetRoadmapID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las asignaciones"})
+		return
+	}
+
+	now := time.Now()
+	summaries := []models.AssignmentSummary{}
+	for _, assignment := range assignments {
+		assignees, err := h.assignees(&assignment, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso de los asignados"})
+			return
+		}
+		summary := summarize(assignment, assignees)
+		if onlyOverdue && summary.Overdue == 0 {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignments": summaries})
+}
+
+// summarize cuenta los asignados por estado
+func summarize(assignment models.Assignment, assignees []models.Assignee) models.AssignmentSummary {
+	summary := models.AssignmentSummary{Assignment: assignment, Assignees: len(assignees)}
+	total := 0
+	for _, assignee := range assignees {
+		if assignee.Status == models.AssigneeStatusCompleted {
+			summary.Completed++
+		}
+		if assignee.Overdue {
+			summary.Overdue++
+		}
+		total += assignee.Completion
+	}
+	if len(assignees) > 0 {
+		summary.AvgCompletion = total / len(assignees)
+	}
+	return summary
+}
+
+// CreateAssignment asigna el roadmap, o parte de sus nodos, a los usuarios
+// indicados por username
+func (h *AssignmentHandler) CreateAssignment(c *gin.Context) {
+	var req struct {
+		assignmentRequest
+		Usernames []string `json:"usernames"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	assignment := models.Assignment{RoadmapID: middleware.GetRoadmapID(c), CreatedBy: userID}
+	if !req.apply(c, h.store, &assignment) {
+		return
+	}
+	users, ok := h.resolveAssignees(c, req.Usernames)
+	if !ok {
+		return
+	}
+
+	err := h.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Assignments().Create(&assignment); err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := tx.Assignments().AddAssignee(assignment.ID, user.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear la asignación"})
+		return
+	}
+
+	h.respondAssignment(c, http.StatusCreated, &assignment)
+}
+
+// resolveAssignees busca los usuarios por username sin repetir ninguno. Todos
+// deben poder ver el roadmap. Si alguno no es válido responde y devuelve false.
+func (h *AssignmentHandler) resolveAssignees(c *gin.Context, usernames []string) ([]*models.User, bool) {
+	roadmap, err := h.store.Roadmaps().GetByID(middleware.GetRoadmapID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return nil, false
+	}
+
+	var users []*models.User
+	seen := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		username = strings.TrimSpace(username)
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		user, err := h.store.Users().GetByUsername(username)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Usuario no encontrado: %s", username)})
+			return nil, false
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar el usuario"})
+			return nil, false
+		}
+
+		if !roadmap.IsPublic {
+			role, err := middleware.RoadmapRole(h.store, roadmap, user.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar permisos sobre el roadmap"})
+				return nil, false
+			}
+			if role == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("El usuario %s no tiene acceso al roadmap", username)})
+				return nil, false
+			}
+		}
+		users = append(users, user)
+	}
+	return users, true
+}
+
+// GetAssignment devuelve la asignación con el progreso de cada asignado. Con
+// status solo se incluyen los asignados en ese estado.
+func (h *AssignmentHandler) GetAssignment(c *gin.Context) {
+	assignment, ok := h.loadAssignment(c)
+	if !ok {
+		return
+	}
+	h.respondAssignment(c, http.StatusOK, assignment)
+}
+
+// respondAssignment responde con la asignación, el resumen y sus asignados
+func (h *AssignmentHandler) respondAssignment(c *gin.Context, status int, assignment *models.Assignment) {
+	assignees, err := h.assignees(assignment, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso de los asignados"})
+		return
+	}
+	summary := summarize(*assignment, assignees)
+
+	if filter := models.AssigneeStatus(c.Query("status")); filter != "" {
+		filtered := []models.Assignee{}
+		for _, assignee := range assignees {
+			if assignee.Status == filter {
+				filtered = append(filtered, assignee)
+			}
+		}
+		assignees = filtered
+	}
+
+	c.JSON(status, gin.H{
+		"assignment": summary,
+		"assignees":  assignees,
+	})
+}
+
+// assignees obtiene los asignados con su estado en el momento indicado
+func (h *AssignmentHandler) assignees(assignment *models.Assignment, at time.Time) ([]models.Assignee, error) {
+	assignees, err := h.store.Assignments().ListAssignees(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range assignees {
+		assignees[i].Evaluate(assignment.DueAt, at)
+	}
+	return assignees, nil
+}
+
+// UpdateAssignment modifica el título, la descripción, los nodos o la fecha
+// límite de la asignación
+func (h *AssignmentHandler) UpdateAssignment(c *gin.Context) {
+	var req assignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	assignment, ok := h.loadAssignment(c)
+	if !ok {
+		return
+	}
+	if !req.apply(c, h.store, assignment) {
+		return
+	}
+
+	assignment.UpdatedAt = time.Now()
+	err := h.store.Transaction(func(tx repository.Store) error {
+		return tx.Assignments().Update(assignment)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar la asignación"})
+		return
+	}
+
+	h.respondAssignment(c, http.StatusOK, assignment)
+}
+
+// DeleteAssignment elimina la asignación; el progreso de los asignados se conserva
+func (h *AssignmentHandler) DeleteAssignment(c *gin.Context) {
+	assignment, ok := h.loadAssignment(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Assignments().Delete(assignment.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar la asignación"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Asignación eliminada correctamente"})
+}
+
+// AddAssignees añade usuarios a la asignación; los que ya estaban se ignoran
+func (h *AssignmentHandler) AddAssignees(c *gin.Context) {
+	var req struct {
+		Usernames []string `json:"usernames" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	assignment, ok := h.loadAssignment(c)
+	if !ok {
+		return
+	}
+	users, ok := h.resolveAssignees(c, req.Usernames)
+	if !ok {
+		return
+	}
+
+	err := h.store.Transaction(func(tx repository.Store) error {
+		for _, user := range users {
+			if err := tx.Assignments().AddAssignee(assignment.ID, user.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al añadir los asignados"})
+		return
+	}
+
+	h.respondAssignment(c, http.StatusOK, assignment)
+}
+
+// RemoveAssignee quita a un usuario de la asignación
+func (h *AssignmentHandler) RemoveAssignee(c *gin.Context) {
+	assignment, ok := h.loadAssignment(c)
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "user_id", "ID de usuario inválido")
+	if !ok {
+		return
+	}
+
+	err := h.store.Assignments().RemoveAssignee(assignment.ID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "El usuario no está asignado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al quitar el asignado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Asignado eliminado correctamente"})
+}
+
+// ExportAssignment descarga el estado de los asignados en CSV o, con
+// format=json, en JSON
+func (h *AssignmentHandler) ExportAssignment(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido: usa csv o json"})
+		return
+	}
+
+	assignment, ok := h.loadAssignment(c)
+	if !ok {
+		return
+	}
+	generatedAt := time.Now()
+	assignees, err := h.assignees(assignment, generatedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso de los asignados"})
+		return
+	}
+
+	filename := "asignacion-" + assignment.ID.String() + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{
+			"assignment":   summarize(*assignment, assignees),
+			"assignees":    assignees,
+			"generated_at": generatedAt,
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{
+		"user_id", "username", "assigned_at", "due_at", "status", "overdue",
+		"completed", "in_progress", "total_nodes", "completion", "completed_at", "last_activity_at",
+	})
+	for _, a := range assignees {
+		w.Write([]string{
+			a.UserID.String(), a.Username, formatTime(&a.AssignedAt), formatTime(&assignment.DueAt),
+			string(a.Status), strconv.FormatBool(a.Overdue),
+			strconv.Itoa(a.Completed), strconv.Itoa(a.InProgress), strconv.Itoa(a.TotalNodes),
+			strconv.Itoa(a.Completion), formatTime(a.CompletedAt), formatTime(a.LastActivityAt),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al generar el CSV"})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// formatTime formatea una fecha para la exportación, o "" si no hay ninguna
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// loadAssignment obtiene la asignación de la ruta dentro del roadmap. Si
+// falla responde y devuelve false.
+func (h *AssignmentHandler) loadAssignment(c *gin.Context) (*models.Assignment, bool) {
+	assignmentID, ok := parseIDParam(c, "assignment_id", "ID de asignación inválido")
+	if !ok {
+		return nil, false
+	}
+
+	assignment, err := h.store.Assignments().GetByID(assignmentID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && assignment.RoadmapID != middleware.GetRoadmapID(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Asignación no encontrada"})
+		return nil, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener la asignación"})
+		return nil, false
+	}
+	return assignment, true
+}
+
+// ListMyAssignments devuelve las asignaciones del usuario con su progreso en
+// cada una
+func (h *AssignmentHandler) ListMyAssignments(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	assignments, err := h.store.Assignments().ListByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las asignaciones"})
+		return
+	}
+
+	type myAssignment struct {
+		models.Assignment
+		Progress models.Assignee `json:"progress"`
+	}
+	now := time.Now()
+	result := []myAssignment{}
+	for _, assignment := range assignments {
+		assignees, err := h.assignees(&assignment, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso"})
+			return
+		}
+		for _, assignee := range assignees {
+			if assignee.UserID == userID {
+				result = append(result, myAssignment{Assignment: assignment, Progress: assignee})
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignments": result})
+}
@@ -2,27 +2,28 @@ package handlers
 
 import (
 	"crypto/rand"
-	"database/sql"
 	"encoding/base64"
+	"errors"
 	"net/http"
 	"strings"
 
-	"Gin/internal/database"
+	"Gin/internal/middleware"
 	"Gin/internal/models"
+	"Gin/internal/repository"
 	"Gin/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler struct {
-	db              *database.DB
-	jwtService      *services.JWTService
+	store             repository.Store
+	jwtService        *services.JWTService
 	googleAuthService *services.GoogleAuthService
 }
 
-func NewAuthHandler(db *database.DB, jwtService *services.JWTService, googleAuthService *services.GoogleAuthService) *AuthHandler {
+func NewAuthHandler(store repository.Store, jwtService *services.JWTService, googleAuthService *services.GoogleAuthService) *AuthHandler {
 	return &AuthHandler{
-		db:              db,
-		jwtService:      jwtService,
+		store:             store,
+		jwtService:        jwtService,
 		googleAuthService: googleAuthService,
 	}
 }
@@ -64,20 +65,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	// Insertar usuario en la base de datos
-	query := `
-		INSERT INTO users (username, email, password_hash)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at, updated_at`
-
-	err := h.db.GetDB().QueryRow(
-		query,
-		user.Username,
-		user.Email,
-		user.PasswordHash,
-	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
-
-	if err != nil {
-		if err.Error() == "pq: duplicate key value violates unique constraint" {
+	if err := h.store.Users().Create(user); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
 			c.JSON(http.StatusConflict, gin.H{"error": "El usuario o email ya existe"})
 			return
 		}
@@ -108,18 +97,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// Buscar usuario por email
-	var user models.User
-	query := `SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE email = $1`
-	err := h.db.GetDB().QueryRow(query, input.Email).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
+	user, err := h.store.Users().GetByEmail(input.Email)
+	if errors.Is(err, repository.ErrNotFound) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Credenciales inválidas"})
 		return
 	} else if err != nil {
@@ -187,49 +166,24 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 	}
 
 	// Buscar o crear usuario
-	var user models.User
-	query := `
-		SELECT id, username, email, avatar_url, created_at, updated_at 
-		FROM users 
-		WHERE google_id = $1 OR email = $2`
-
-	err = h.db.GetDB().QueryRow(query, googleUser.ID, googleUser.Email).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.AvatarURL,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
+	user, err := h.store.Users().FindForGoogle(googleUser.ID, googleUser.Email)
+	if errors.Is(err, repository.ErrNotFound) {
 		// Crear nuevo usuario
 		username := googleUser.GivenName + googleUser.FamilyName
 		if username == "" {
 			username = googleUser.Email[:strings.Index(googleUser.Email, "@")]
 		}
 
-		query = `
-			INSERT INTO users (username, email, google_id, avatar_url)
-			VALUES ($1, $2, $3, $4)
-			RETURNING id, created_at, updated_at`
-
-		err = h.db.GetDB().QueryRow(
-			query,
-			username,
-			googleUser.Email,
-			googleUser.ID,
-			googleUser.Picture,
-		).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
-
-		if err != nil {
+		user = &models.User{
+			Username:  username,
+			Email:     googleUser.Email,
+			GoogleID:  googleUser.ID,
+			AvatarURL: googleUser.Picture,
+		}
+		if err := h.store.Users().Create(user); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear usuario"})
 			return
 		}
-
-		user.Username = username
-		user.Email = googleUser.Email
-		user.AvatarURL = googleUser.Picture
 	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar usuario"})
 		return
@@ -249,26 +203,15 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 // GetMe retorna la información del usuario actual
 func (h *AuthHandler) GetMe(c *gin.Context) {
 	// Obtener user_id del contexto (establecido por el middleware)
-	userID, exists := c.Get("user_id")
+	userID, exists := middleware.GetUserID(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
 		return
 	}
 
 	// Buscar usuario en la base de datos
-	var user models.User
-	query := `SELECT id, username, email, avatar_url, bio, created_at, updated_at FROM users WHERE id = $1`
-	err := h.db.GetDB().QueryRow(query, userID).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.AvatarURL,
-		&user.Bio,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
+	user, err := h.store.Users().GetByID(userID)
+	if errors.Is(err, repository.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
 		return
 	} else if err != nil {
@@ -277,4 +220,4 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"user": user})
-}
\ No newline at end of file
+}
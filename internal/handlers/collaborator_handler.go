@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type CollaboratorHandler struct {
+	store repository.Store
+	hub   *realtime.Hub
+}
+
+func NewCollaboratorHandler(store repository.Store, hub *realtime.Hub) *CollaboratorHandler {
+	return &CollaboratorHandler{store: store, hub: hub}
+}
+
+// ListCollaborators devuelve los colaboradores del roadmap, incluidas las
+// invitaciones pendientes
+func (h *CollaboratorHandler) ListCollaborators(c *gin.Context) {
+	collaborators, err := h.store.Collaborators().ListByRoadmap(middleware.GetRoadmapID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los colaboradores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collaborators": collaborators,
+		"role":          middleware.GetRoadmapRole(c),
+	})
+}
+
+// InviteCollaborator invita a un usuario, por username o email, a colaborar
+// con el rol indicado. El rol no tiene efecto hasta que acepta la invitación.
+func (h *CollaboratorHandler) InviteCollaborator(c *gin.Context) {
+	var req struct {
+		Username string      `json:"username"`
+		Email    string      `json:"email"`
+		Role     models.Role `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	req.Email = strings.TrimSpace(req.Email)
+	if (req.Username == "") == (req.Email == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Indica el username o el email del usuario"})
+		return
+	}
+	if !req.Role.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rol inválido"})
+		return
+	}
+	if !canAssignRole(c, req.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Solo el propietario puede gestionar administradores"})
+		return
+	}
+
+	var user *models.User
+	var err error
+	if req.Username != "" {
+		user, err = h.store.Users().GetByUsername(req.Username)
+	} else {
+		user, err = h.store.Users().GetByEmail(req.Email)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar el usuario"})
+		return
+	}
+
+	roadmapID := middleware.GetRoadmapID(c)
+	roadmap, err := h.store.Roadmaps().GetByID(roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+	if roadmap.AuthorID == user.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El autor ya es el propietario del roadmap"})
+		return
+	}
+
+	invitedBy, _ := middleware.GetUserID(c)
+	collaborator := models.Collaborator{
+		RoadmapID: roadmapID,
+		UserID:    user.ID,
+		Role:      req.Role,
+		Status:    models.CollaboratorStatusPending,
+		InvitedBy: invitedBy,
+	}
+	err = h.store.Collaborators().Create(&collaborator)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "El usuario ya está invitado a este roadmap"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al invitar al usuario"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collaborator)
+}
+
+// UpdateCollaborator cambia el rol de un colaborador
+func (h *CollaboratorHandler) UpdateCollaborator(c *gin.Context) {
+	var req struct {
+		Role models.Role `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+	if !req.Role.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rol inválido"})
+		return
+	}
+
+	collaborator, ok := h.loadCollaborator(c)
+	if !ok {
+		return
+	}
+	if !canAssignRole(c, req.Role) || !canAssignRole(c, collaborator.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Solo el propietario puede gestionar administradores"})
+		return
+	}
+
+	collaborator.Role = req.Role
+	collaborator.UpdatedAt = time.Now()
+	if err := h.store.Collaborators().Update(collaborator); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el colaborador"})
+		return
+	}
+
+	c.JSON(http.StatusOK, collaborator)
+}
+
+// RemoveCollaborator revoca el acceso de un colaborador o cancela su
+// invitación. Cualquier colaborador puede abandonar el roadmap.
+func (h *CollaboratorHandler) RemoveCollaborator(c *gin.Context) {
+	collaborator, ok := h.loadCollaborator(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	if collaborator.UserID != userID {
+		if !middleware.GetRoadmapRole(c).Includes(models.RoleAdmin) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "No tienes permiso para realizar esta acción en el roadmap"})
+			return
+		}
+		if !canAssignRole(c, collaborator.Role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Solo el propietario puede gestionar administradores"})
+			return
+		}
+	}
+
+	if err := h.store.Collaborators().Delete(collaborator.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar el colaborador"})
+		return
+	}
+
+	// Cerrar las conexiones en tiempo real que tuviera abiertas
+	h.hub.Disconnect(collaborator.RoadmapID, collaborator.UserID)
+	c.JSON(http.StatusOK, gin.H{"message": "Colaborador eliminado correctamente"})
+}
+
+// loadCollaborator obtiene el colaborador de la ruta dentro del roadmap. Si
+// falla responde y devuelve false.
+func (h *CollaboratorHandler) loadCollaborator(c *gin.Context) (*models.Collaborator, bool) {
+	collaboratorID, ok := parseIDParam(c, "collaborator_id", "ID de colaborador inválido")
+	if !ok {
+		return nil, false
+	}
+
+	collaborator, err := h.store.Collaborators().GetByID(collaboratorID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && collaborator.RoadmapID != middleware.GetRoadmapID(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Colaborador no encontrado"})
+		return nil, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el colaborador"})
+		return nil, false
+	}
+	return collaborator, true
+}
+
+// canAssignRole indica si el usuario puede dar o quitar el rol indicado.
+// Solo el propietario gestiona a los administradores.
+func canAssignRole(c *gin.Context, role models.Role) bool {
+	return role != models.RoleAdmin || middleware.GetRoadmapRole(c) == models.RoleOwner
+}
+
+// ListInvitations devuelve las invitaciones pendientes del usuario
+func (h *CollaboratorHandler) ListInvitations(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	invitations, err := h.store.Collaborators().ListByUser(userID, models.CollaboratorStatusPending)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las invitaciones"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// AcceptInvitation acepta una invitación pendiente del usuario
+func (h *CollaboratorHandler) AcceptInvitation(c *gin.Context) {
+	invitation, ok := h.loadInvitation(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	invitation.Status = models.CollaboratorStatusAccepted
+	invitation.AcceptedAt = &now
+	invitation.UpdatedAt = now
+	if err := h.store.Collaborators().Update(invitation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al aceptar la invitación"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
+
+// DeclineInvitation rechaza una invitación pendiente del usuario
+func (h *CollaboratorHandler) DeclineInvitation(c *gin.Context) {
+	invitation, ok := h.loadInvitation(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Collaborators().Delete(invitation.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al rechazar la invitación"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitación rechazada"})
+}
+
+// loadInvitation obtiene la invitación pendiente de la ruta, que debe ser
+// del usuario autenticado. Si falla responde y devuelve false.
+func (h *CollaboratorHandler) loadInvitation(c *gin.Context) (*models.Collaborator, bool) {
+	invitationID, ok := parseIDParam(c, "invitation_id", "ID de invitación inválido")
+	if !ok {
+		return nil, false
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return nil, false
+	}
+
+	invitation, err := h.store.Collaborators().GetByID(invitationID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && invitation.UserID != userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitación no encontrada"})
+		return nil, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener la invitación"})
+		return nil, false
+	}
+	if invitation.Status != models.CollaboratorStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "La invitación ya fue aceptada"})
+		return nil, false
+	}
+	return invitation, true
+}
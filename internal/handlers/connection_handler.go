@@ -1,28 +1,35 @@
 package handlers
 
 import (
-	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
+	"Gin/internal/graph"
+	"Gin/internal/middleware"
 	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
+var errRequirementCycle = errors.New("la conexión cierra un ciclo de requisitos")
+
 type ConnectionHandler struct {
-	db *sql.DB
+	store repository.Store
+	hub   *realtime.Hub
 }
 
-func NewConnectionHandler(db *sql.DB) *ConnectionHandler {
-	return &ConnectionHandler{db: db}
+func NewConnectionHandler(store repository.Store, hub *realtime.Hub) *ConnectionHandler {
+	return &ConnectionHandler{store: store, hub: hub}
 }
 
 // CreateConnection crea una nueva conexión entre nodos
 func (h *ConnectionHandler) CreateConnection(c *gin.Context) {
 	type createConnectionRequest struct {
-		FromNodeID     int64                 `json:"from_node_id" binding:"required"`
-		ToNodeID       int64                 `json:"to_node_id" binding:"required"`
+		FromNodeID     models.ID             `json:"from_node_id" binding:"required"`
+		ToNodeID       models.ID             `json:"to_node_id" binding:"required"`
 		ConnectionType models.ConnectionType `json:"connection_type" binding:"required"`
 	}
 
@@ -32,64 +39,31 @@ func (h *ConnectionHandler) CreateConnection(c *gin.Context) {
 		return
 	}
 
-	roadmapID := c.GetInt64("roadmap_id")
-
-	// Verificar que ambos nodos existen y pertenecen al roadmap
-	var count int
-	err := h.db.QueryRow(`
-		SELECT COUNT(*)
-		FROM nodes
-		WHERE roadmap_id = $1 AND id IN ($2, $3)`,
-		roadmapID, req.FromNodeID, req.ToNodeID,
-	).Scan(&count)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar nodos"})
+	if req.FromNodeID == req.ToNodeID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Un nodo no puede conectarse consigo mismo"})
 		return
 	}
-
-	if count != 2 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Uno o ambos nodos no existen en este roadmap"})
+	if !req.ConnectionType.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Tipo de conexión inválido: %s", req.ConnectionType)})
 		return
 	}
 
-	// Verificar que no existe ya una conexión entre estos nodos
-	err = h.db.QueryRow(`
-		SELECT COUNT(*)
-		FROM connections
-		WHERE roadmap_id = $1 AND from_node_id = $2 AND to_node_id = $3`,
-		roadmapID, req.FromNodeID, req.ToNodeID,
-	).Scan(&count)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar conexión existente"})
-		return
-	}
+	roadmapID := middleware.GetRoadmapID(c)
 
-	if count > 0 {
-		c.JSON(http.StatusConflict, gin.H{"error": "Ya existe una conexión entre estos nodos"})
-		return
+	// Verificar que ambos nodos existen y pertenecen al roadmap
+	for _, nodeID := range []models.ID{req.FromNodeID, req.ToNodeID} {
+		_, err := h.store.Nodes().GetByID(roadmapID, nodeID)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Uno o ambos nodos no existen en este roadmap"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar nodos"})
+			return
+		}
 	}
 
 	now := time.Now()
-
-	// Crear la conexión
-	var connectionID int64
-	err = h.db.QueryRow(`
-		INSERT INTO connections (roadmap_id, from_node_id, to_node_id, connection_type, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id`,
-		roadmapID, req.FromNodeID, req.ToNodeID, req.ConnectionType, now, now,
-	).Scan(&connectionID)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear conexión"})
-		return
-	}
-
-	// Devolver la conexión creada
 	connection := models.Connection{
-		ID:             connectionID,
 		RoadmapID:      roadmapID,
 		FromNodeID:     req.FromNodeID,
 		ToNodeID:       req.ToNodeID,
@@ -98,40 +72,73 @@ func (h *ConnectionHandler) CreateConnection(c *gin.Context) {
 		UpdatedAt:      now,
 	}
 
+	// Crear la conexión; falla si ya existe una entre estos nodos o si es un
+	// requisito que cierra un ciclo
+	var cycle []models.ID
+	err := h.hub.Commit(roadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			if connection.ConnectionType.Prerequisite() {
+				current, err := loadRoadmapGraph(tx, roadmapID)
+				if err != nil {
+					return err
+				}
+				g := graph.New(current.Nodes, current.Connections)
+				if cycle = g.CycleWith(connection.FromNodeID, connection.ToNodeID); cycle != nil {
+					return errRequirementCycle
+				}
+			}
+
+			if err := tx.Connections().Create(&connection); err != nil {
+				return err
+			}
+			emit(realtime.EventConnectionCreated, connection)
+			return recordRevision(tx, c, &models.Revision{RoadmapID: roadmapID, Action: models.RevisionActionConnectionCreate})
+		})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Ya existe una conexión entre estos nodos"})
+			return
+		}
+		if errors.Is(err, errRequirementCycle) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "La conexión cierra un ciclo de requisitos",
+				"cycle": cycle,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear conexión"})
+		return
+	}
+
 	c.JSON(http.StatusCreated, connection)
 }
 
 // DeleteConnection elimina una conexión existente
 func (h *ConnectionHandler) DeleteConnection(c *gin.Context) {
-	connectionID, err := strconv.ParseInt(c.Param("conn_id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de conexión inválido"})
+	connectionID, ok := parseIDParam(c, "conn_id", "ID de conexión inválido")
+	if !ok {
 		return
 	}
 
-	roadmapID := c.GetInt64("roadmap_id")
-
 	// Eliminar la conexión
-	result, err := h.db.Exec(`
-		DELETE FROM connections
-		WHERE id = $1 AND roadmap_id = $2`,
-		connectionID, roadmapID,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar conexión"})
-		return
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar eliminación"})
-		return
-	}
-
-	if rowsAffected == 0 {
+	roadmapID := middleware.GetRoadmapID(c)
+	err := h.hub.Commit(roadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			if err := tx.Connections().Delete(roadmapID, connectionID); err != nil {
+				return err
+			}
+			emit(realtime.EventConnectionDeleted, gin.H{"id": connectionID})
+			return recordRevision(tx, c, &models.Revision{RoadmapID: roadmapID, Action: models.RevisionActionConnectionDelete})
+		})
+	})
+	if errors.Is(err, repository.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Conexión no encontrada"})
 		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar conexión"})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Conexión eliminada correctamente"})
-}
\ No newline at end of file
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag publica la versión del recurso en la cabecera ETag
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// parseETag extrae la versión de un ETag ("3" o W/"3")
+func parseETag(value string) (int, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	version, err := strconv.Atoi(strings.Trim(value, `"`))
+	return version, err == nil && version > 0
+}
+
+// ifMatchVersion devuelve la versión exigida en If-Match, o 0 si no se exige
+// ninguna. Si la cabecera no es válida responde 400 y devuelve false.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	version, ok := parseETag(header)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cabecera If-Match inválida"})
+		return 0, false
+	}
+	return version, true
+}
+
+// notModified responde 304 si If-None-Match coincide con la versión actual
+func notModified(c *gin.Context, version int) bool {
+	for _, value := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		if v, ok := parseETag(value); ok && v == version {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
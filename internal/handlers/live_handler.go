@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIDHeader identifica la pestaña del editor que hace una operación
+const ClientIDHeader = "X-Client-ID"
+
+// keepAliveInterval es cada cuánto se envía un comentario para que los
+// proxies no cierren el canal por inactividad
+const keepAliveInterval = 25 * time.Second
+
+type LiveHandler struct {
+	store repository.Store
+	hub   *realtime.Hub
+}
+
+func NewLiveHandler(store repository.Store, hub *realtime.Hub) *LiveHandler {
+	return &LiveHandler{store: store, hub: hub}
+}
+
+// liveOrigin identifica al editor que hace la petición. El hub solo acepta la
+// cabecera X-Client-ID si corresponde a una conexión del usuario autenticado.
+func liveOrigin(c *gin.Context) realtime.Origin {
+	userID, ok := middleware.GetUserID(c)
+	clientID := c.GetHeader(ClientIDHeader)
+	if !ok || len(clientID) > 64 {
+		clientID = ""
+	}
+	return realtime.Origin{ClientID: clientID, UserID: userID}
+}
+
+// Stream abre el canal de eventos del roadmap (Server-Sent Events). El primer
+// evento es "hello" con el identificador del editor, el número de secuencia
+// actual y quién está conectado. Al reconectarse con Last-Event-ID se
+// reenvían las operaciones perdidas o, si ya no están disponibles, se pide
+// recargar el roadmap con resync.
+func (h *LiveHandler) Stream(c *gin.Context) {
+	after := int64(-1)
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID != "" {
+		seq, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || seq < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Last-Event-ID inválido"})
+			return
+		}
+		after = seq
+	}
+
+	clientID := c.Query("client_id")
+	if len(clientID) > 64 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "client_id inválido"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	user, err := h.store.Users().GetByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el usuario"})
+		return
+	}
+
+	presence := realtime.Presence{ClientID: clientID, UserID: user.ID, Username: user.Username}
+	sub, welcome := h.hub.Join(middleware.GetRoadmapID(c), presence, after)
+	defer h.hub.Leave(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	writeSSE(c.Writer, "", realtime.EventHello, welcome)
+	for _, event := range welcome.Missed {
+		writeSSE(c.Writer, strconv.FormatInt(event.Seq, 10), event.Type, event)
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Sustituido por otra conexión o demasiado lento: el editor
+				// se reconectará con Last-Event-ID
+				return
+			}
+			id := ""
+			if event.Type != realtime.EventPresence {
+				id = strconv.FormatInt(event.Seq, 10)
+			}
+			writeSSE(c.Writer, id, event.Type, event)
+		case <-ticker.C:
+			io.WriteString(c.Writer, ": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeSSE escribe un evento con el formato de Server-Sent Events. Los
+// eventos de presencia no llevan id para no alterar Last-Event-ID.
+func writeSSE(w io.Writer, id string, eventType realtime.EventType, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, payload)
+}
+
+// ListPresence devuelve los editores conectados al roadmap
+func (h *LiveHandler) ListPresence(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"presence": h.hub.Presence(middleware.GetRoadmapID(c))})
+}
+
+// UpdatePresence comunica al resto de editores qué nodo tiene seleccionado
+// el editor, o ninguno si selected_node_id es null
+func (h *LiveHandler) UpdatePresence(c *gin.Context) {
+	var req struct {
+		ClientID       string     `json:"client_id" binding:"required"`
+		SelectedNodeID *models.ID `json:"selected_node_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	roadmapID := middleware.GetRoadmapID(c)
+	if req.SelectedNodeID != nil {
+		_, err := h.store.Nodes().GetByID(roadmapID, *req.SelectedNodeID)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "El nodo no existe en este roadmap"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar el nodo"})
+			return
+		}
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	if err := h.hub.Select(roadmapID, req.ClientID, userID, req.SelectedNodeID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "El editor no está conectado a este roadmap"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,32 +1,38 @@
 package handlers
 
 import (
-	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
+	"Gin/internal/middleware"
 	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
 type NodeHandler struct {
-	db *sql.DB
+	store repository.Store
+	hub   *realtime.Hub
 }
 
-func NewNodeHandler(db *sql.DB) *NodeHandler {
-	return &NodeHandler{db: db}
+func NewNodeHandler(store repository.Store, hub *realtime.Hub) *NodeHandler {
+	return &NodeHandler{store: store, hub: hub}
 }
 
 // CreateNode crea un nuevo nodo en el roadmap
 func (h *NodeHandler) CreateNode(c *gin.Context) {
 	type createNodeRequest struct {
-		Title       string       `json:"title" binding:"required"`
-		Description string       `json:"description"`
+		Title       string          `json:"title" binding:"required"`
+		Description string          `json:"description"`
 		Type        models.NodeType `json:"node_type" binding:"required"`
-		PositionX   float64     `json:"position_x" binding:"required"`
-		PositionY   float64     `json:"position_y" binding:"required"`
-		Color       string      `json:"color" binding:"required"`
+		PositionX   float64         `json:"position_x" binding:"required"`
+		PositionY   float64         `json:"position_y" binding:"required"`
+		Color       string          `json:"color" binding:"required"`
+		OrderIndex  *int            `json:"order_index"`
+		Estimate    *float64        `json:"estimated_hours" binding:"omitempty,gt=0"`
 	}
 
 	var req createNodeRequest
@@ -34,56 +40,90 @@ func (h *NodeHandler) CreateNode(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
 		return
 	}
-
-	roadmapID := c.GetInt64("roadmap_id")
-	now := time.Now()
-
-	// Insertar el nuevo nodo
-	var nodeID int64
-	err := h.db.QueryRow(`
-		INSERT INTO nodes (roadmap_id, title, description, type, position_x, position_y, color, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id`,
-		roadmapID, req.Title, req.Description, req.Type, req.PositionX, req.PositionY, req.Color, "not_started", now, now,
-	).Scan(&nodeID)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear el nodo"})
+	if !req.Type.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Tipo de nodo inválido: %s", req.Type)})
 		return
 	}
 
-	// Devolver el nodo creado
+	now := time.Now()
 	node := models.Node{
-		ID:          nodeID,
-		RoadmapID:   roadmapID,
+		RoadmapID:   middleware.GetRoadmapID(c),
 		Title:       req.Title,
 		Description: req.Description,
 		Type:        req.Type,
 		Position:    models.Position{X: req.PositionX, Y: req.PositionY},
 		Status:      "not_started",
 		Color:       req.Color,
+		OrderIndex:  req.OrderIndex,
+		Estimate:    req.Estimate,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
+	// Insertar el nuevo nodo
+	err := h.hub.Commit(node.RoadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			if err := tx.Nodes().Create(&node); err != nil {
+				return err
+			}
+			emit(realtime.EventNodeCreated, node)
+			return recordRevision(tx, c, &models.Revision{RoadmapID: node.RoadmapID, Action: models.RevisionActionNodeCreate})
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear el nodo"})
+		return
+	}
+
+	setETag(c, node.Version)
 	c.JSON(http.StatusCreated, node)
 }
 
-// UpdateNode actualiza un nodo existente
+// GetNode devuelve un nodo con su versión en la cabecera ETag
+func (h *NodeHandler) GetNode(c *gin.Context) {
+	nodeID, ok := parseIDParam(c, "node_id", "ID de nodo inválido")
+	if !ok {
+		return
+	}
+
+	node, err := h.store.Nodes().GetByID(middleware.GetRoadmapID(c), nodeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nodo no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el nodo"})
+		return
+	}
+
+	setETag(c, node.Version)
+	if notModified(c, node.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, node)
+}
+
+// UpdateNode actualiza un nodo existente. Con If-Match, solo si el nodo sigue
+// en la versión indicada; si cambió, responde 409 con el nodo actual.
 func (h *NodeHandler) UpdateNode(c *gin.Context) {
-	nodeID, err := strconv.ParseInt(c.Param("node_id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de nodo inválido"})
+	nodeID, ok := parseIDParam(c, "node_id", "ID de nodo inválido")
+	if !ok {
+		return
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
 	type updateNodeRequest struct {
-		Title       *string       `json:"title"`
-		Description *string       `json:"description"`
+		Title       *string          `json:"title"`
+		Description *string          `json:"description"`
 		Type        *models.NodeType `json:"node_type"`
-		PositionX   *float64     `json:"position_x"`
-		PositionY   *float64     `json:"position_y"`
-		Color       *string      `json:"color"`
+		PositionX   *float64         `json:"position_x"`
+		PositionY   *float64         `json:"position_y"`
+		Color       *string          `json:"color"`
+		OrderIndex  *int             `json:"order_index"`
+		Estimate    *float64         `json:"estimated_hours" binding:"omitempty,gt=0"`
 	}
 
 	var req updateNodeRequest
@@ -91,25 +131,24 @@ func (h *NodeHandler) UpdateNode(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
 		return
 	}
+	if req.Type != nil && !req.Type.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Tipo de nodo inválido: %s", *req.Type)})
+		return
+	}
 
 	// Obtener el nodo actual
-	var node models.Node
-	err = h.db.QueryRow(`
-		SELECT id, roadmap_id, title, description, type, position_x, position_y, status, color, created_at, updated_at
-		FROM nodes WHERE id = $1 AND roadmap_id = $2`,
-		nodeID, c.GetInt64("roadmap_id"),
-	).Scan(
-		&node.ID, &node.RoadmapID, &node.Title, &node.Description, &node.Type,
-		&node.Position.X, &node.Position.Y, &node.Status, &node.Color, &node.CreatedAt, &node.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
+	node, err := h.store.Nodes().GetByID(middleware.GetRoadmapID(c), nodeID)
+	if errors.Is(err, repository.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Nodo no encontrado"})
 		return
 	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el nodo"})
 		return
 	}
+	if expected != 0 && node.Version != expected {
+		respondNodeConflict(c, node)
+		return
+	}
 
 	// Actualizar solo los campos proporcionados
 	if req.Title != nil {
@@ -130,113 +169,87 @@ func (h *NodeHandler) UpdateNode(c *gin.Context) {
 	if req.Color != nil {
 		node.Color = *req.Color
 	}
+	if req.OrderIndex != nil {
+		node.OrderIndex = req.OrderIndex
+	}
+	if req.Estimate != nil {
+		node.Estimate = req.Estimate
+	}
 	node.UpdatedAt = time.Now()
 
 	// Actualizar el nodo en la base de datos
-	_, err = h.db.Exec(`
-		UPDATE nodes
-		SET title = $1, description = $2, type = $3, position_x = $4, position_y = $5, color = $6, updated_at = $7
-		WHERE id = $8 AND roadmap_id = $9`,
-		node.Title, node.Description, node.Type, node.Position.X, node.Position.Y, node.Color, node.UpdatedAt,
-		node.ID, node.RoadmapID,
-	)
-
+	err = h.hub.Commit(node.RoadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			if err := tx.Nodes().Update(node); err != nil {
+				return err
+			}
+			emit(realtime.EventNodeUpdated, *node)
+			return recordRevision(tx, c, &models.Revision{RoadmapID: node.RoadmapID, Action: models.RevisionActionNodeUpdate})
+		})
+	})
+	if errors.Is(err, repository.ErrVersionConflict) {
+		// Otra operación lo modificó entre la lectura y la escritura
+		if current, err := h.store.Nodes().GetByID(node.RoadmapID, nodeID); err == nil {
+			respondNodeConflict(c, current)
+			return
+		}
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el nodo"})
 		return
 	}
 
+	setETag(c, node.Version)
 	c.JSON(http.StatusOK, node)
 }
 
+// respondNodeConflict responde 409 con el estado actual del nodo
+func respondNodeConflict(c *gin.Context, current *models.Node) {
+	setETag(c, current.Version)
+	c.JSON(http.StatusConflict, gin.H{
+		"error":   "El nodo fue modificado por otra sesión",
+		"current": current,
+	})
+}
+
 // DeleteNode elimina un nodo y sus conexiones
 func (h *NodeHandler) DeleteNode(c *gin.Context) {
-	nodeID, err := strconv.ParseInt(c.Param("node_id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de nodo inválido"})
-		return
-	}
-
-	roadmapID := c.GetInt64("roadmap_id")
-
-	// Iniciar transacción
-	tx, err := h.db.Begin()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al iniciar transacción"})
-		return
-	}
-	defer tx.Rollback()
-
-	// Eliminar las conexiones relacionadas con el nodo
-	_, err = tx.Exec(`
-		DELETE FROM connections
-		WHERE roadmap_id = $1 AND (from_node_id = $2 OR to_node_id = $2)`,
-		roadmapID, nodeID,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar conexiones"})
-		return
-	}
-
-	// Eliminar los recursos del nodo
-	_, err = tx.Exec(`
-		DELETE FROM resources
-		WHERE node_id = $1`,
-		nodeID,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar recursos"})
+	nodeID, ok := parseIDParam(c, "node_id", "ID de nodo inválido")
+	if !ok {
 		return
 	}
 
-	// Eliminar el progreso relacionado con el nodo
-	_, err = tx.Exec(`
-		DELETE FROM progress
-		WHERE node_id = $1`,
-		nodeID,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar progreso"})
-		return
-	}
-
-	// Eliminar el nodo
-	result, err := tx.Exec(`
-		DELETE FROM nodes
-		WHERE id = $1 AND roadmap_id = $2`,
-		nodeID, roadmapID,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar nodo"})
-		return
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar eliminación"})
-		return
-	}
-
-	if rowsAffected == 0 {
+	// El repositorio elimina también las conexiones, recursos y progreso
+	roadmapID := middleware.GetRoadmapID(c)
+	err := h.hub.Commit(roadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			if err := tx.Nodes().Delete(roadmapID, nodeID); err != nil {
+				return err
+			}
+			emit(realtime.EventNodeDeleted, gin.H{"id": nodeID})
+			return recordRevision(tx, c, &models.Revision{RoadmapID: roadmapID, Action: models.RevisionActionNodeDelete})
+		})
+	})
+	if errors.Is(err, repository.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Nodo no encontrado"})
 		return
-	}
-
-	// Confirmar transacción
-	if err := tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al confirmar transacción"})
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar nodo"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Nodo eliminado correctamente"})
 }
 
-// UpdateNodePositions actualiza las posiciones de múltiples nodos
+// UpdateNodePositions actualiza las posiciones de múltiples nodos. Los nodos
+// que indican su versión solo se mueven si no han cambiado; si alguno cambió
+// no se mueve ninguno y se responde 409 con el estado actual de esos nodos.
 func (h *NodeHandler) UpdateNodePositions(c *gin.Context) {
 	type nodePosition struct {
-		NodeID    int64   `json:"node_id" binding:"required"`
-		PositionX float64 `json:"position_x" binding:"required"`
-		PositionY float64 `json:"position_y" binding:"required"`
+		NodeID    models.ID `json:"node_id" binding:"required"`
+		PositionX float64   `json:"position_x" binding:"required"`
+		PositionY float64   `json:"position_y" binding:"required"`
+		Version   int       `json:"version"`
 	}
 
 	var positions []nodePosition
@@ -245,36 +258,58 @@ func (h *NodeHandler) UpdateNodePositions(c *gin.Context) {
 		return
 	}
 
-	roadmapID := c.GetInt64("roadmap_id")
+	roadmapID := middleware.GetRoadmapID(c)
 	now := time.Now()
 
-	// Iniciar transacción
-	tx, err := h.db.Begin()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al iniciar transacción"})
-		return
-	}
-	defer tx.Rollback()
-
-	// Actualizar cada posición
-	for _, pos := range positions {
-		_, err := tx.Exec(`
-			UPDATE nodes
-			SET position_x = $1, position_y = $2, updated_at = $3
-			WHERE id = $4 AND roadmap_id = $5`,
-			pos.PositionX, pos.PositionY, now, pos.NodeID, roadmapID,
-		)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar posiciones"})
-			return
+	// Actualizar cada posición en una única transacción; los nodos que no
+	// pertenecen al roadmap se ignoran
+	versions := make(map[models.ID]int, len(positions))
+	var stale []models.ID
+	err := h.hub.Commit(roadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			moved := make([]gin.H, 0, len(positions))
+			for _, pos := range positions {
+				position := models.Position{X: pos.PositionX, Y: pos.PositionY}
+				version, err := tx.Nodes().UpdatePosition(roadmapID, pos.NodeID, position, pos.Version, now)
+				switch {
+				case errors.Is(err, repository.ErrVersionConflict):
+					stale = append(stale, pos.NodeID)
+				case errors.Is(err, repository.ErrNotFound):
+				case err != nil:
+					return err
+				default:
+					versions[pos.NodeID] = version
+					moved = append(moved, gin.H{"id": pos.NodeID, "position": position, "version": version})
+				}
+			}
+			if len(stale) > 0 {
+				return repository.ErrVersionConflict
+			}
+			if len(moved) > 0 {
+				emit(realtime.EventNodePositions, gin.H{"nodes": moved})
+			}
+			return recordRevision(tx, c, &models.Revision{RoadmapID: roadmapID, Action: models.RevisionActionNodePositions})
+		})
+	})
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current := make([]models.Node, 0, len(stale))
+		for _, nodeID := range stale {
+			if node, err := h.store.Nodes().GetByID(roadmapID, nodeID); err == nil {
+				current = append(current, *node)
+			}
 		}
-	}
-
-	// Confirmar transacción
-	if err := tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al confirmar transacción"})
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Algunos nodos fueron modificados por otra sesión",
+			"current": current,
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar posiciones"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Posiciones actualizadas correctamente"})
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Posiciones actualizadas correctamente",
+		"versions": versions,
+	})
+}
@@ -0,0 +1,629 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// slugPattern son los slugs válidos: minúsculas, dígitos y guiones sueltos
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+const maxSlugLength = 50
+
+type OrganizationHandler struct {
+	store repository.Store
+	hub   *realtime.Hub
+}
+
+func NewOrganizationHandler(store repository.Store, hub *realtime.Hub) *OrganizationHandler {
+	return &OrganizationHandler{store: store, hub: hub}
+}
+
+// slugify genera un slug a partir del nombre de la organización
+func slugify(name string) string {
+	replacer := strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+	name = replacer.Replace(strings.ToLower(name))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// CreateOrganization crea una organización de la que el usuario es propietario
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req struct {
+		Name        string `json:"name" binding:"required"`
+		Slug        string `json:"slug"`
+		Description string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	organization := models.Organization{
+		Name:        strings.TrimSpace(req.Name),
+		Slug:        strings.TrimSpace(req.Slug),
+		Description: req.Description,
+		CreatedBy:   userID,
+	}
+	if organization.Slug == "" {
+		organization.Slug = slugify(organization.Name)
+	}
+	if !validOrganization(c, &organization) {
+		return
+	}
+
+	err := h.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Organizations().Create(&organization); err != nil {
+			return err
+		}
+		return tx.Organizations().AddMember(&models.OrganizationMember{
+			OrganizationID: organization.ID,
+			UserID:         userID,
+			Role:           models.OrgRoleOwner,
+		})
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Ya existe una organización con ese slug"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear la organización"})
+		return
+	}
+
+	organization.Role = models.OrgRoleOwner
+	c.JSON(http.StatusCreated, organization)
+}
+
+// validOrganization comprueba el nombre y el slug. Si no son válidos responde
+// 400 y devuelve false.
+func validOrganization(c *gin.Context, organization *models.Organization) bool {
+	if organization.Name == "" || len(organization.Name) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El nombre debe tener entre 1 y 100 caracteres"})
+		return false
+	}
+	if len(organization.Slug) > maxSlugLength || !slugPattern.MatchString(organization.Slug) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El slug solo puede contener minúsculas, números y guiones"})
+		return false
+	}
+	return true
+}
+
+// ListOrganizations devuelve las organizaciones del usuario con su rol en cada una
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	organizations, err := h.store.Organizations().ListByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las organizaciones"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"organizations": organizations})
+}
+
+// GetOrganization devuelve la organización con el rol del usuario en ella
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	organization, ok := h.loadOrganization(c)
+	if !ok {
+		return
+	}
+
+	organization.Role = middleware.GetOrganizationRole(c)
+	c.JSON(http.StatusOK, organization)
+}
+
+// UpdateOrganization modifica el nombre, el slug o la descripción
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	var req struct {
+		Name        *string `json:"name"`
+		Slug        *string `json:"slug"`
+		Description *string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	organization, ok := h.loadOrganization(c)
+	if !ok {
+		return
+	}
+
+	if req.Name != nil {
+		organization.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Slug != nil {
+		organization.Slug = strings.TrimSpace(*req.Slug)
+	}
+	if req.Description != nil {
+		organization.Description = *req.Description
+	}
+	if !validOrganization(c, organization) {
+		return
+	}
+
+	organization.UpdatedAt = time.Now()
+	err := h.store.Organizations().Update(organization)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Ya existe una organización con ese slug"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar la organización"})
+		return
+	}
+
+	organization.Role = middleware.GetOrganizationRole(c)
+	c.JSON(http.StatusOK, organization)
+}
+
+// DeleteOrganization elimina la organización. Sus roadmaps se conservan y
+// vuelven a pertenecer solo a sus autores.
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	organizationID := middleware.GetOrganizationID(c)
+	var (
+		members  []models.OrganizationMember
+		roadmaps []models.RoadmapSummary
+	)
+	err := h.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if members, err = tx.Organizations().ListMembers(organizationID); err != nil {
+			return err
+		}
+		if roadmaps, err = tx.Roadmaps().ListByOrganization(organizationID); err != nil {
+			return err
+		}
+		return tx.Organizations().Delete(organizationID)
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organización no encontrada"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar la organización"})
+		return
+	}
+
+	for _, roadmap := range roadmaps {
+		roadmap.OrganizationID = nil
+		h.disconnectWithoutAccess(&roadmap.Roadmap, members)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Organización eliminada correctamente"})
+}
+
+// loadOrganization obtiene la organización validada por el middleware. Si
+// falla responde y devuelve false.
+func (h *OrganizationHandler) loadOrganization(c *gin.Context) (*models.Organization, bool) {
+	organization, err := h.store.Organizations().GetByID(middleware.GetOrganizationID(c))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organización no encontrada"})
+		return nil, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener la organización"})
+		return nil, false
+	}
+	return organization, true
+}
+
+// ListMembers devuelve los miembros de la organización
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	members, err := h.store.Organizations().ListMembers(middleware.GetOrganizationID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los miembros"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"members": members,
+		"role":    middleware.GetOrganizationRole(c),
+	})
+}
+
+// AddMember añade a la organización a un usuario, por username o email, con
+// el rol indicado (member por defecto)
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	var req struct {
+		Username string         `json:"username"`
+		Email    string         `json:"email"`
+		Role     models.OrgRole `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	req.Email = strings.TrimSpace(req.Email)
+	if (req.Username == "") == (req.Email == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Indica el username o el email del usuario"})
+		return
+	}
+	if req.Role == "" {
+		req.Role = models.OrgRoleMember
+	}
+	if !req.Role.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rol inválido"})
+		return
+	}
+	if !canAssignOrgRole(c, req.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Solo los propietarios pueden gestionar administradores"})
+		return
+	}
+
+	var user *models.User
+	var err error
+	if req.Username != "" {
+		user, err = h.store.Users().GetByUsername(req.Username)
+	} else {
+		user, err = h.store.Users().GetByEmail(req.Email)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar el usuario"})
+		return
+	}
+
+	member := models.OrganizationMember{
+		OrganizationID: middleware.GetOrganizationID(c),
+		UserID:         user.ID,
+		Role:           req.Role,
+	}
+	err = h.store.Organizations().AddMember(&member)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "El usuario ya es miembro de la organización"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al añadir el miembro"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateMember cambia el rol de un miembro
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	var req struct {
+		Role models.OrgRole `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+	if !req.Role.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rol inválido"})
+		return
+	}
+
+	member, ok := h.loadMember(c)
+	if !ok {
+		return
+	}
+	if !canAssignOrgRole(c, req.Role) || !canAssignOrgRole(c, member.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Solo los propietarios pueden gestionar administradores"})
+		return
+	}
+
+	err := h.store.Transaction(func(tx repository.Store) error {
+		if member.Role == models.OrgRoleOwner && req.Role != models.OrgRoleOwner {
+			if err := requireAnotherOwner(tx, member); err != nil {
+				return err
+			}
+		}
+		member.Role = req.Role
+		return tx.Organizations().UpdateMember(member)
+	})
+	if errors.Is(err, errLastOwner) {
+		c.JSON(http.StatusConflict, gin.H{"error": "La organización debe tener al menos un propietario"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el miembro"})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember saca a un miembro de la organización. Cualquier miembro puede
+// abandonarla mientras quede otro propietario.
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	member, ok := h.loadMember(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	if member.UserID != userID {
+		if !middleware.GetOrganizationRole(c).Includes(models.OrgRoleAdmin) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "No tienes permiso para realizar esta acción en la organización"})
+			return
+		}
+		if !canAssignOrgRole(c, member.Role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Solo los propietarios pueden gestionar administradores"})
+			return
+		}
+	}
+
+	var roadmaps []models.RoadmapSummary
+	err := h.store.Transaction(func(tx repository.Store) error {
+		if member.Role == models.OrgRoleOwner {
+			if err := requireAnotherOwner(tx, member); err != nil {
+				return err
+			}
+		}
+		if err := tx.Organizations().RemoveMember(member.OrganizationID, member.UserID); err != nil {
+			return err
+		}
+		var err error
+		roadmaps, err = tx.Roadmaps().ListByOrganization(member.OrganizationID)
+		return err
+	})
+	if errors.Is(err, errLastOwner) {
+		c.JSON(http.StatusConflict, gin.H{"error": "La organización debe tener al menos un propietario"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar el miembro"})
+		return
+	}
+
+	// Cerrar las conexiones en tiempo real a los roadmaps a los que ya no
+	// tenga acceso
+	for _, roadmap := range roadmaps {
+		h.disconnectWithoutAccess(&roadmap.Roadmap, []models.OrganizationMember{*member})
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Miembro eliminado correctamente"})
+}
+
+// disconnectWithoutAccess cierra las conexiones en tiempo real al roadmap de
+// los miembros que ya no tienen ningún rol en él
+func (h *OrganizationHandler) disconnectWithoutAccess(roadmap *models.Roadmap, members []models.OrganizationMember) {
+	for _, member := range members {
+		role, err := middleware.RoadmapRole(h.store, roadmap, member.UserID)
+		if err == nil && role == "" {
+			h.hub.Disconnect(roadmap.ID, member.UserID)
+		}
+	}
+}
+
+// errLastOwner impide que una organización se quede sin propietarios
+var errLastOwner = errors.New("la organización se quedaría sin propietarios")
+
+// requireAnotherOwner devuelve errLastOwner si member es el único propietario
+func requireAnotherOwner(tx repository.Store, member *models.OrganizationMember) error {
+	members, err := tx.Organizations().ListMembers(member.OrganizationID)
+	if err != nil {
+		return err
+	}
+	for _, other := range members {
+		if other.Role == models.OrgRoleOwner && other.UserID != member.UserID {
+			return nil
+		}
+	}
+	return errLastOwner
+}
+
+// loadMember obtiene el miembro de la ruta dentro de la organización. Si
+// falla responde y devuelve false.
+func (h *OrganizationHandler) loadMember(c *gin.Context) (*models.OrganizationMember, bool) {
+	userID, ok := parseIDParam(c, "user_id", "ID de usuario inválido")
+	if !ok {
+		return nil, false
+	}
+
+	member, err := h.store.Organizations().GetMember(middleware.GetOrganizationID(c), userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Miembro no encontrado"})
+		return nil, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el miembro"})
+		return nil, false
+	}
+	return member, true
+}
+
+// canAssignOrgRole indica si el usuario puede dar o quitar el rol indicado.
+// Solo los propietarios gestionan a administradores y propietarios.
+func canAssignOrgRole(c *gin.Context, role models.OrgRole) bool {
+	return role == models.OrgRoleMember || middleware.GetOrganizationRole(c) == models.OrgRoleOwner
+}
+
+// ListRoadmaps devuelve los roadmaps de la organización, públicos o no
+func (h *OrganizationHandler) ListRoadmaps(c *gin.Context) {
+	roadmaps, err := h.store.Roadmaps().ListByOrganization(middleware.GetOrganizationID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los roadmaps"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roadmaps": roadmaps})
+}
+
+// Dashboard resume el progreso de los miembros en los roadmaps de la
+// organización, por miembro y por roadmap
+func (h *OrganizationHandler) Dashboard(c *gin.Context) {
+	organizationID := middleware.GetOrganizationID(c)
+
+	var members []models.OrganizationMember
+	var roadmaps []models.RoadmapSummary
+	var progress []models.MemberProgress
+	err := h.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if members, err = tx.Organizations().ListMembers(organizationID); err != nil {
+			return err
+		}
+		if roadmaps, err = tx.Roadmaps().ListByOrganization(organizationID); err != nil {
+			return err
+		}
+		progress, err = tx.Organizations().ListProgress(organizationID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso de la organización"})
+		return
+	}
+
+	memberSummaries := make([]models.MemberSummary, len(members))
+	memberIndex := make(map[models.ID]int, len(members))
+	for i, member := range members {
+		memberSummaries[i] = models.MemberSummary{
+			UserID:   member.UserID,
+			Username: member.Username,
+			Role:     member.Role,
+			Roadmaps: []models.MemberProgress{},
+		}
+		memberIndex[member.UserID] = i
+	}
+
+	roadmapSummaries := make([]models.RoadmapProgressSummary, len(roadmaps))
+	roadmapIndex := make(map[models.ID]int, len(roadmaps))
+	completionSums := make([]int, len(roadmaps))
+	for i, roadmap := range roadmaps {
+		roadmapSummaries[i] = models.RoadmapProgressSummary{RoadmapID: roadmap.ID, Title: roadmap.Title}
+		roadmapIndex[roadmap.ID] = i
+	}
+
+	for _, p := range progress {
+		mi, ok := memberIndex[p.UserID]
+		if !ok {
+			continue
+		}
+		ri, ok := roadmapIndex[p.RoadmapID]
+		if !ok {
+			continue
+		}
+
+		member := &memberSummaries[mi]
+		member.Roadmaps = append(member.Roadmaps, p)
+		member.TotalNodes += p.TotalNodes
+		member.Completed += p.Completed
+		member.InProgress += p.InProgress
+		if p.LastActivityAt != nil && (member.LastActivityAt == nil || p.LastActivityAt.After(*member.LastActivityAt)) {
+			member.LastActivityAt = p.LastActivityAt
+		}
+
+		roadmap := &roadmapSummaries[ri]
+		roadmap.TotalNodes = p.TotalNodes
+		if p.Completed+p.InProgress > 0 {
+			roadmap.MembersStarted++
+		}
+		if p.TotalNodes > 0 && p.Completed == p.TotalNodes {
+			roadmap.MembersCompleted++
+		}
+		completionSums[ri] += percentage(p.Completed, p.TotalNodes)
+	}
+
+	for i := range memberSummaries {
+		memberSummaries[i].Completion = percentage(memberSummaries[i].Completed, memberSummaries[i].TotalNodes)
+	}
+	if len(members) > 0 {
+		for i := range roadmapSummaries {
+			roadmapSummaries[i].AvgCompletion = completionSums[i] / len(members)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"members":  memberSummaries,
+		"roadmaps": roadmapSummaries,
+	})
+}
+
+// percentage devuelve el porcentaje entero de part sobre total
+func percentage(part, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return part * 100 / total
+}
+
+// SetRoadmapOrganization asigna el roadmap a una organización de la que el
+// propietario es miembro
+func (h *OrganizationHandler) SetRoadmapOrganization(c *gin.Context) {
+	var req struct {
+		OrganizationID models.ID `json:"organization_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	role, err := middleware.OrganizationRole(h.store, req.OrganizationID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar la organización"})
+		return
+	}
+	if role == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organización no encontrada"})
+		return
+	}
+
+	h.updateRoadmapOrganization(c, &req.OrganizationID)
+}
+
+// RemoveRoadmapOrganization deja el roadmap sin organización
+func (h *OrganizationHandler) RemoveRoadmapOrganization(c *gin.Context) {
+	h.updateRoadmapOrganization(c, nil)
+}
+
+func (h *OrganizationHandler) updateRoadmapOrganization(c *gin.Context, organizationID *models.ID) {
+	roadmap, err := h.store.Roadmaps().GetByID(middleware.GetRoadmapID(c))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	previous := roadmap.OrganizationID
+	roadmap.OrganizationID = organizationID
+	roadmap.UpdatedAt = time.Now()
+	if err := h.store.Roadmaps().Update(roadmap); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el roadmap"})
+		return
+	}
+
+	// Los miembros de la organización anterior pueden haber perdido el acceso
+	if previous != nil && (organizationID == nil || *organizationID != *previous) {
+		if members, err := h.store.Organizations().ListMembers(*previous); err == nil {
+			h.disconnectWithoutAccess(roadmap, members)
+		}
+	}
+	c.JSON(http.StatusOK, roadmap)
+}
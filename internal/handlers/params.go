@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"Gin/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// parseIDParam lee un identificador de la ruta. Si no es válido responde
+// 400 con el mensaje indicado y devuelve false.
+func parseIDParam(c *gin.Context, param, message string) (models.ID, bool) {
+	id, err := models.ParseID(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return "", false
+	}
+	return id, true
+}
+
+// parseRevisionNumber valida un número de revisión. Si no es válido responde
+// 400 y devuelve false.
+func parseRevisionNumber(c *gin.Context, value string) (int, bool) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Número de revisión inválido"})
+		return 0, false
+	}
+	return number, true
+}
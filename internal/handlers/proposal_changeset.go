@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"Gin/internal/graph"
+	"Gin/internal/merge"
+	"Gin/internal/models"
+)
+
+// proposalOperationRequest es una operación de una propuesta tal como la
+// envía el cliente. En las creaciones, ID es una clave provisional con la
+// que las operaciones siguientes pueden referirse al nuevo elemento.
+type proposalOperationRequest struct {
+	Op         merge.Op         `json:"op"`
+	Kind       merge.Kind       `json:"kind"`
+	ID         clientID         `json:"id"`
+	Node       *nodePatch       `json:"node"`
+	Connection *connectionPatch `json:"connection"`
+	Resource   *resourcePatch   `json:"resource"`
+}
+
+// nodePatch contiene los campos de un nodo que cambia la operación
+type nodePatch struct {
+	Title       *string          `json:"title"`
+	Description *string          `json:"description"`
+	Type        *models.NodeType `json:"type"`
+	Position    *models.Position `json:"position"`
+	Color       *string          `json:"color"`
+	OrderIndex  *int             `json:"order_index"`
+	Estimate    *float64         `json:"estimated_hours"`
+}
+
+// connectionPatch contiene los campos de una conexión que cambia la operación
+type connectionPatch struct {
+	FromNodeID     *clientID              `json:"from_node_id"`
+	ToNodeID       *clientID              `json:"to_node_id"`
+	Label          *string                `json:"label"`
+	ConnectionType *models.ConnectionType `json:"connection_type"`
+}
+
+// resourcePatch contiene los campos de un recurso que cambia la operación
+type resourcePatch struct {
+	NodeID      *clientID `json:"node_id"`
+	Title       *string   `json:"title"`
+	Type        *string   `json:"type"`
+	URL         *string   `json:"url"`
+	Description *string   `json:"description"`
+}
+
+// changeset aplica las operaciones de una propuesta sobre una copia del
+// contenido del roadmap, validándolas una a una
+type changeset struct {
+	roadmapID   models.ID
+	nodes       map[models.ID]models.Node
+	connections map[models.ID]models.Connection
+	resources   map[models.ID]models.Resource
+	// order conserva el orden de los elementos para el snapshot resultante
+	order []models.ID
+	// ids traduce las claves provisionales a los ids asignados
+	ids map[clientID]models.ID
+}
+
+func newChangeset(roadmapID models.ID, current models.Snapshot) *changeset {
+	cs := &changeset{
+		roadmapID:   roadmapID,
+		nodes:       make(map[models.ID]models.Node, len(current.Nodes)),
+		connections: make(map[models.ID]models.Connection, len(current.Connections)),
+		resources:   make(map[models.ID]models.Resource, len(current.Resources)),
+		ids:         make(map[clientID]models.ID),
+	}
+	for _, node := range current.Nodes {
+		cs.nodes[node.ID] = node
+		cs.order = append(cs.order, node.ID)
+	}
+	for _, conn := range current.Connections {
+		cs.connections[conn.ID] = conn
+		cs.order = append(cs.order, conn.ID)
+	}
+	for _, resource := range current.Resources {
+		cs.resources[resource.ID] = resource
+		cs.order = append(cs.order, resource.ID)
+	}
+	return cs
+}
+
+// snapshot devuelve el contenido resultante de las operaciones aplicadas
+func (cs *changeset) snapshot() models.Snapshot {
+	snapshot := models.Snapshot{
+		Nodes:       []models.Node{},
+		Connections: []models.Connection{},
+		Resources:   []models.Resource{},
+	}
+	for _, id := range cs.order {
+		if node, ok := cs.nodes[id]; ok {
+			snapshot.Nodes = append(snapshot.Nodes, node)
+		} else if conn, ok := cs.connections[id]; ok {
+			snapshot.Connections = append(snapshot.Connections, conn)
+		} else if resource, ok := cs.resources[id]; ok {
+			snapshot.Resources = append(snapshot.Resources, resource)
+		}
+	}
+	return snapshot
+}
+
+// ref traduce una referencia del cliente al id del elemento
+func (cs *changeset) ref(id clientID) models.ID {
+	if real, ok := cs.ids[id]; ok {
+		return real
+	}
+	return models.ID(id)
+}
+
+// newID asigna un id al elemento creado con la clave provisional indicada
+func (cs *changeset) newID(key clientID) (models.ID, string) {
+	id := models.NewID()
+	if key == "" {
+		return id, ""
+	}
+	if _, ok := cs.ids[key]; ok || cs.exists(models.ID(key)) {
+		return "", fmt.Sprintf("Clave duplicada: %s", key)
+	}
+	cs.ids[key] = id
+	return id, ""
+}
+
+// exists indica si el id pertenece a algún elemento
+func (cs *changeset) exists(id models.ID) bool {
+	_, node := cs.nodes[id]
+	_, conn := cs.connections[id]
+	_, resource := cs.resources[id]
+	return node || conn || resource
+}
+
+// apply aplica una operación y devuelve cómo queda registrada en la propuesta,
+// o un mensaje de error si no es válida
+func (cs *changeset) apply(req proposalOperationRequest) (models.ProposalOperation, string) {
+	op := models.ProposalOperation{Op: string(req.Op), Kind: string(req.Kind)}
+	switch req.Op {
+	case merge.OpCreate, merge.OpUpdate, merge.OpDelete:
+	default:
+		return op, fmt.Sprintf("Operación inválida: %s", req.Op)
+	}
+
+	var msg string
+	switch req.Kind {
+	case merge.KindNode:
+		msg = cs.applyNode(req, &op)
+	case merge.KindConnection:
+		msg = cs.applyConnection(req, &op)
+	case merge.KindResource:
+		msg = cs.applyResource(req, &op)
+	default:
+		msg = fmt.Sprintf("Tipo de elemento inválido: %s", req.Kind)
+	}
+	return op, msg
+}
+
+func (cs *changeset) applyNode(req proposalOperationRequest, op *models.ProposalOperation) string {
+	var node models.Node
+	switch req.Op {
+	case merge.OpCreate:
+		id, msg := cs.newID(req.ID)
+		if msg != "" {
+			return msg
+		}
+		node = models.Node{ID: id, RoadmapID: cs.roadmapID, Type: models.NodeTypeTopic, Status: "not_started"}
+		cs.order = append(cs.order, id)
+	default:
+		var ok bool
+		if node, ok = cs.nodes[cs.ref(req.ID)]; !ok {
+			return fmt.Sprintf("Nodo no encontrado: %s", req.ID)
+		}
+	}
+	op.ID = node.ID
+
+	if req.Op == merge.OpDelete {
+		delete(cs.nodes, node.ID)
+		for id, conn := range cs.connections {
+			if conn.FromNodeID == node.ID || conn.ToNodeID == node.ID {
+				delete(cs.connections, id)
+			}
+		}
+		for id, resource := range cs.resources {
+			if resource.NodeID == node.ID {
+				delete(cs.resources, id)
+			}
+		}
+		return ""
+	}
+
+	patch := req.Node
+	if patch == nil {
+		return fmt.Sprintf("Faltan los datos del nodo %s", req.ID)
+	}
+	if patch.Title != nil {
+		node.Title = *patch.Title
+	}
+	if patch.Description != nil {
+		node.Description = *patch.Description
+	}
+	if patch.Type != nil {
+		node.Type = *patch.Type
+	}
+	if patch.Position != nil {
+		node.Position = *patch.Position
+	}
+	if patch.Color != nil {
+		node.Color = *patch.Color
+	}
+	if patch.OrderIndex != nil {
+		node.OrderIndex = patch.OrderIndex
+	}
+	if patch.Estimate != nil {
+		node.Estimate = patch.Estimate
+	}
+
+	if strings.TrimSpace(node.Title) == "" {
+		return fmt.Sprintf("El nodo %s no tiene título", req.ID)
+	}
+	if !node.Type.Valid() {
+		return fmt.Sprintf("Tipo de nodo inválido: %s", node.Type)
+	}
+	if node.Estimate != nil && *node.Estimate <= 0 {
+		return fmt.Sprintf("El nodo %s tiene una estimación inválida", req.ID)
+	}
+
+	cs.nodes[node.ID] = node
+	op.Node = &node
+	return ""
+}
+
+func (cs *changeset) applyConnection(req proposalOperationRequest, op *models.ProposalOperation) string {
+	var conn models.Connection
+	switch req.Op {
+	case merge.OpCreate:
+		id, msg := cs.newID(req.ID)
+		if msg != "" {
+			return msg
+		}
+		conn = models.Connection{ID: id, RoadmapID: cs.roadmapID, ConnectionType: models.ConnectionTypeDefault}
+		cs.order = append(cs.order, id)
+	default:
+		var ok bool
+		if conn, ok = cs.connections[cs.ref(req.ID)]; !ok {
+			return fmt.Sprintf("Conexión no encontrada: %s", req.ID)
+		}
+	}
+	op.ID = conn.ID
+
+	if req.Op == merge.OpDelete {
+		delete(cs.connections, conn.ID)
+		return ""
+	}
+
+	patch := req.Connection
+	if patch == nil {
+		return fmt.Sprintf("Faltan los datos de la conexión %s", req.ID)
+	}
+	if patch.FromNodeID != nil {
+		conn.FromNodeID = cs.ref(*patch.FromNodeID)
+	}
+	if patch.ToNodeID != nil {
+		conn.ToNodeID = cs.ref(*patch.ToNodeID)
+	}
+	if patch.Label != nil {
+		conn.Label = *patch.Label
+	}
+	if patch.ConnectionType != nil {
+		conn.ConnectionType = *patch.ConnectionType
+	}
+
+	_, fromOK := cs.nodes[conn.FromNodeID]
+	_, toOK := cs.nodes[conn.ToNodeID]
+	if !fromOK || !toOK {
+		return "Las conexiones deben referenciar nodos del roadmap"
+	}
+	if conn.FromNodeID == conn.ToNodeID {
+		return "Un nodo no puede conectarse consigo mismo"
+	}
+	for id, other := range cs.connections {
+		if id != conn.ID && other.FromNodeID == conn.FromNodeID && other.ToNodeID == conn.ToNodeID {
+			return "Ya existe una conexión entre estos nodos"
+		}
+	}
+	if !conn.ConnectionType.Valid() {
+		return fmt.Sprintf("Tipo de conexión inválido: %s", conn.ConnectionType)
+	}
+	if conn.ConnectionType.Prerequisite() {
+		if msg := cs.checkCycle(conn); msg != "" {
+			return msg
+		}
+	}
+
+	cs.connections[conn.ID] = conn
+	op.Connection = &conn
+	return ""
+}
+
+// checkCycle comprueba que la conexión de requisito no cierra un ciclo con
+// las demás conexiones del changeset
+func (cs *changeset) checkCycle(conn models.Connection) string {
+	snapshot := cs.snapshot()
+	others := make([]models.Connection, 0, len(snapshot.Connections))
+	for _, other := range snapshot.Connections {
+		if other.ID != conn.ID {
+			others = append(others, other)
+		}
+	}
+
+	cycle := graph.New(snapshot.Nodes, others).CycleWith(conn.FromNodeID, conn.ToNodeID)
+	if cycle == nil {
+		return ""
+	}
+	titles := make([]string, len(cycle))
+	for i, id := range cycle {
+		titles[i] = cs.nodes[id].Title
+	}
+	return fmt.Sprintf("La conexión cierra un ciclo de requisitos: %s", strings.Join(titles, " → "))
+}
+
+func (cs *changeset) applyResource(req proposalOperationRequest, op *models.ProposalOperation) string {
+	var resource models.Resource
+	switch req.Op {
+	case merge.OpCreate:
+		id, msg := cs.newID(req.ID)
+		if msg != "" {
+			return msg
+		}
+		resource = models.Resource{ID: id}
+		cs.order = append(cs.order, id)
+	default:
+		var ok bool
+		if resource, ok = cs.resources[cs.ref(req.ID)]; !ok {
+			return fmt.Sprintf("Recurso no encontrado: %s", req.ID)
+		}
+	}
+	op.ID = resource.ID
+
+	if req.Op == merge.OpDelete {
+		delete(cs.resources, resource.ID)
+		return ""
+	}
+
+	patch := req.Resource
+	if patch == nil {
+		return fmt.Sprintf("Faltan los datos del recurso %s", req.ID)
+	}
+	if patch.NodeID != nil {
+		resource.NodeID = cs.ref(*patch.NodeID)
+	}
+	if patch.Title != nil {
+		resource.Title = *patch.Title
+	}
+	if patch.Type != nil {
+		resource.Type = *patch.Type
+	}
+	if patch.URL != nil {
+		resource.URL = *patch.URL
+	}
+	if patch.Description != nil {
+		resource.Description = *patch.Description
+	}
+
+	if _, ok := cs.nodes[resource.NodeID]; !ok {
+		return "Los recursos deben pertenecer a un nodo del roadmap"
+	}
+	if strings.TrimSpace(resource.Title) == "" || strings.TrimSpace(resource.URL) == "" || resource.Type == "" {
+		return fmt.Sprintf("El recurso %s necesita título, tipo y URL", req.ID)
+	}
+
+	cs.resources[resource.ID] = resource
+	op.Resource = &resource
+	return ""
+}
@@ -0,0 +1,429 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"Gin/internal/merge"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errProposalClosed    = errors.New("la propuesta ya fue revisada")
+	errProposalConflicts = errors.New("la propuesta tiene conflictos sin resolver")
+)
+
+// Resoluciones de conflictos de una propuesta: quedarse con la versión
+// propuesta o con la actual del roadmap
+const (
+	resolutionProposal = "proposal"
+	resolutionCurrent  = "current"
+)
+
+type ProposalHandler struct {
+	store repository.Store
+	hub   *realtime.Hub
+}
+
+func NewProposalHandler(store repository.Store, hub *realtime.Hub) *ProposalHandler {
+	return &ProposalHandler{store: store, hub: hub}
+}
+
+// proposalConflict es un conflicto entre la propuesta y los cambios hechos
+// en el roadmap desde que se envió
+type proposalConflict struct {
+	ID       string       `json:"id"`
+	Kind     merge.Kind   `json:"kind"`
+	Key      models.ID    `json:"key"`
+	Reason   merge.Reason `json:"reason"`
+	Fields   []string     `json:"fields,omitempty"`
+	Base     any          `json:"base,omitempty"`
+	Proposed any          `json:"proposed,omitempty"`
+	Current  any          `json:"current,omitempty"`
+}
+
+// proposalReasons traduce los motivos de la fusión al vocabulario de las propuestas
+var proposalReasons = map[merge.Reason]merge.Reason{
+	merge.ReasonUpstreamDeleted: "proposal_deleted",
+	merge.ReasonForkDeleted:     "current_deleted",
+}
+
+func proposalConflicts(conflicts []merge.Conflict) []proposalConflict {
+	out := make([]proposalConflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		reason := c.Reason
+		if translated, ok := proposalReasons[reason]; ok {
+			reason = translated
+		}
+		out = append(out, proposalConflict{
+			ID:       c.ID,
+			Kind:     c.Kind,
+			Key:      c.Key,
+			Reason:   reason,
+			Fields:   c.Fields,
+			Base:     c.Base,
+			Proposed: c.Upstream,
+			Current:  c.Fork,
+		})
+	}
+	return out
+}
+
+// currentContent carga el contenido actual del roadmap identificando cada
+// elemento por su propio id, que es como lo identifican las propuestas
+func currentContent(store repository.Store, roadmapID models.ID) (*models.Snapshot, error) {
+	snapshot, err := loadSnapshot(store, roadmapID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range snapshot.Nodes {
+		snapshot.Nodes[i].OriginID = nil
+	}
+	for i := range snapshot.Connections {
+		snapshot.Connections[i].OriginID = nil
+	}
+	for i := range snapshot.Resources {
+		snapshot.Resources[i].OriginID = nil
+	}
+	return snapshot, nil
+}
+
+// diffProposal fusiona la propuesta con el contenido actual del roadmap. Las
+// propuestas ya revisadas se comparan con el contenido sobre el que se enviaron.
+func diffProposal(store repository.Store, proposal *models.Proposal, resolutions map[string]merge.Choice) (merge.Result, error) {
+	if proposal.Status != models.ProposalStatusOpen {
+		return merge.Merge(proposal.Base, proposal.Proposed, proposal.Base, nil), nil
+	}
+
+	current, err := currentContent(store, proposal.RoadmapID)
+	if err != nil {
+		return merge.Result{}, err
+	}
+	return merge.Merge(proposal.Base, proposal.Proposed, *current, resolutions), nil
+}
+
+// CreateProposal envía una propuesta de cambios sobre un roadmap que el
+// usuario no puede editar
+func (h *ProposalHandler) CreateProposal(c *gin.Context) {
+	type createProposalRequest struct {
+		Title       string                     `json:"title" binding:"required"`
+		Description string                     `json:"description"`
+		Operations  []proposalOperationRequest `json:"operations" binding:"required"`
+	}
+
+	roadmapID, ok := parseIDParam(c, "id", "ID de roadmap inválido")
+	if !ok {
+		return
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req createProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El título no puede estar vacío"})
+		return
+	}
+
+	roadmap, err := h.store.Roadmaps().GetByID(roadmapID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !canViewRoadmap(c, h.store, roadmap)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+	role, err := middleware.RoadmapRole(h.store, roadmap, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar permisos sobre el roadmap"})
+		return
+	}
+	if role.Includes(models.RoleEditor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Puedes editar el roadmap directamente"})
+		return
+	}
+
+	current, err := currentContent(h.store, roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	cs := newChangeset(roadmapID, *current)
+	operations := make([]models.ProposalOperation, 0, len(req.Operations))
+	for _, opReq := range req.Operations {
+		op, msg := cs.apply(opReq)
+		if msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		operations = append(operations, op)
+	}
+
+	proposed := cs.snapshot()
+	if len(merge.Merge(*current, proposed, *current, nil).Changes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "La propuesta no contiene cambios"})
+		return
+	}
+
+	proposal := models.Proposal{
+		RoadmapID:   roadmapID,
+		AuthorID:    userID,
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      models.ProposalStatusOpen,
+		Operations:  operations,
+		Base:        *current,
+		Proposed:    proposed,
+	}
+	if err := h.store.Proposals().Create(&proposal); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear la propuesta"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, proposal)
+}
+
+// ListRoadmapProposals devuelve las propuestas recibidas por un roadmap
+func (h *ProposalHandler) ListRoadmapProposals(c *gin.Context) {
+	proposals, err := h.store.Proposals().ListByRoadmap(middleware.GetRoadmapID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las propuestas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"proposals": proposals})
+}
+
+// ListMyProposals devuelve las propuestas enviadas por el usuario con su estado
+func (h *ProposalHandler) ListMyProposals(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	proposals, err := h.store.Proposals().ListByAuthor(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las propuestas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"proposals": proposals})
+}
+
+// GetProposal devuelve una propuesta junto con los cambios que aplicaría
+// sobre el roadmap y los conflictos con lo modificado desde su envío
+func (h *ProposalHandler) GetProposal(c *gin.Context) {
+	proposal, _, ok := h.loadProposal(c, false)
+	if !ok {
+		return
+	}
+
+	result, err := diffProposal(h.store, proposal, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al comparar la propuesta"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"proposal":  proposal,
+		"changes":   result.Changes,
+		"conflicts": proposalConflicts(result.Conflicts),
+	})
+}
+
+// AcceptProposal aplica la propuesta sobre el roadmap en una sola
+// transacción. Si choca con cambios posteriores a su envío no se aplica
+// nada hasta que se resuelvan todos los conflictos, ni tampoco si cerraría un
+// ciclo de requisitos con ellos.
+func (h *ProposalHandler) AcceptProposal(c *gin.Context) {
+	var req struct {
+		Comment     string            `json:"comment"`
+		Resolutions map[string]string `json:"resolutions"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+			return
+		}
+	}
+
+	resolutions := make(map[string]merge.Choice, len(req.Resolutions))
+	for id, choice := range req.Resolutions {
+		switch choice {
+		case resolutionProposal:
+			resolutions[id] = merge.ChoiceUpstream
+		case resolutionCurrent:
+			resolutions[id] = merge.ChoiceFork
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Resolución inválida para " + id})
+			return
+		}
+	}
+
+	proposal, reviewerID, ok := h.loadProposal(c, true)
+	if !ok {
+		return
+	}
+
+	var result merge.Result
+	var cycle []models.ID
+	err := h.hub.Commit(proposal.RoadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			// Releer la propuesta dentro de la transacción para no aceptarla dos veces
+			current, err := tx.Proposals().GetByID(proposal.ID)
+			if err != nil {
+				return err
+			}
+			if current.Status != models.ProposalStatusOpen {
+				return errProposalClosed
+			}
+
+			if result, err = diffProposal(tx, current, resolutions); err != nil {
+				return err
+			}
+			if len(result.Conflicts) > 0 {
+				return errProposalConflicts
+			}
+			if cycle, err = mergeCycle(tx, current.RoadmapID, &result); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			if err := applyMerge(tx, current.RoadmapID, &result, now, false); err != nil {
+				return err
+			}
+			revision := models.Revision{RoadmapID: current.RoadmapID, Action: models.RevisionActionProposalAccept}
+			if err := recordRevision(tx, c, &revision); err != nil {
+				return err
+			}
+			if len(result.Changes) > 0 {
+				emit(realtime.EventGraphReplaced, gin.H{"action": models.RevisionActionProposalAccept, "revision": revision.Number})
+			}
+
+			proposal = current
+			reviewProposal(proposal, models.ProposalStatusAccepted, reviewerID, req.Comment, now)
+			return tx.Proposals().Review(proposal)
+		})
+	})
+	switch {
+	case errors.Is(err, errProposalClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": "La propuesta ya fue revisada"})
+	case errors.Is(err, errProposalConflicts):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     "La propuesta choca con cambios posteriores del roadmap",
+			"conflicts": proposalConflicts(result.Conflicts),
+		})
+	case errors.Is(err, errRequirementCycle):
+		respondMergeCycle(c, cycle)
+	case errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "La propuesta choca con el contenido actual del roadmap"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al aplicar la propuesta"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"proposal": proposal, "applied": result.Changes})
+	}
+}
+
+// RejectProposal rechaza la propuesta con un comentario para su autor
+func (h *ProposalHandler) RejectProposal(c *gin.Context) {
+	var req struct {
+		Comment string `json:"comment"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+			return
+		}
+	}
+
+	proposal, reviewerID, ok := h.loadProposal(c, true)
+	if !ok {
+		return
+	}
+	if proposal.Status != models.ProposalStatusOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "La propuesta ya fue revisada"})
+		return
+	}
+
+	reviewProposal(proposal, models.ProposalStatusRejected, reviewerID, req.Comment, time.Now())
+	if err := h.store.Proposals().Review(proposal); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al rechazar la propuesta"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"proposal": proposal})
+}
+
+func reviewProposal(proposal *models.Proposal, status models.ProposalStatus, reviewerID models.ID, comment string, now time.Time) {
+	proposal.Status = status
+	proposal.ReviewerID = &reviewerID
+	proposal.ReviewComment = comment
+	proposal.ReviewedAt = &now
+	proposal.UpdatedAt = now
+}
+
+// loadProposal obtiene la propuesta de la ruta y comprueba que el usuario
+// puede verla: su autor o quien pueda ver las propuestas del roadmap. Con
+// review solo se permite a quien pueda revisarlas. Si falla responde y
+// devuelve false.
+func (h *ProposalHandler) loadProposal(c *gin.Context, review bool) (*models.Proposal, models.ID, bool) {
+	proposalID, ok := parseIDParam(c, "proposal_id", "ID de propuesta inválido")
+	if !ok {
+		return nil, "", false
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return nil, "", false
+	}
+
+	proposal, err := h.store.Proposals().GetByID(proposalID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Propuesta no encontrada"})
+		return nil, "", false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener la propuesta"})
+		return nil, "", false
+	}
+
+	roadmap, err := h.store.Roadmaps().GetByID(proposal.RoadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return nil, "", false
+	}
+
+	role, err := middleware.RoadmapRole(h.store, roadmap, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar permisos sobre el roadmap"})
+		return nil, "", false
+	}
+
+	switch {
+	case role.Includes(models.RoleAdmin):
+	case !review && (proposal.AuthorID == userID || role.Includes(models.RoleCommenter)):
+	case proposal.AuthorID == userID || role.Includes(models.RoleCommenter):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Solo los administradores del roadmap pueden revisar la propuesta"})
+		return nil, "", false
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Propuesta no encontrada"})
+		return nil, "", false
+	}
+
+	return proposal, userID, true
+}
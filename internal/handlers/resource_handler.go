@@ -1,36 +1,36 @@
 package handlers
 
 import (
-	"database/sql"
+	"errors"
 	"net/http"
-	"strconv"
 	"time"
 
+	"Gin/internal/middleware"
 	"Gin/internal/models"
+	"Gin/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
 type ResourceHandler struct {
-	db *sql.DB
+	store repository.Store
 }
 
-func NewResourceHandler(db *sql.DB) *ResourceHandler {
-	return &ResourceHandler{db: db}
+func NewResourceHandler(store repository.Store) *ResourceHandler {
+	return &ResourceHandler{store: store}
 }
 
 // AddNodeResource añade un nuevo recurso a un nodo
 func (h *ResourceHandler) AddNodeResource(c *gin.Context) {
-	nodeID, err := strconv.ParseInt(c.Param("node_id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de nodo inválido"})
+	nodeID, ok := parseIDParam(c, "node_id", "ID de nodo inválido")
+	if !ok {
 		return
 	}
 
 	type addResourceRequest struct {
 		ResourceType string `json:"resource_type" binding:"required"`
-		Title       string `json:"title" binding:"required"`
-		URL         string `json:"url" binding:"required"`
-		Description string `json:"description"`
+		Title        string `json:"title" binding:"required"`
+		URL          string `json:"url" binding:"required"`
+		Description  string `json:"description"`
 	}
 
 	var req addResourceRequest
@@ -39,48 +39,19 @@ func (h *ResourceHandler) AddNodeResource(c *gin.Context) {
 		return
 	}
 
-	roadmapID := c.GetInt64("roadmap_id")
-
 	// Verificar que el nodo existe y pertenece al roadmap
-	var exists bool
-	err = h.db.QueryRow(`
-		SELECT EXISTS(
-			SELECT 1
-			FROM nodes
-			WHERE id = $1 AND roadmap_id = $2
-		)`,
-		nodeID, roadmapID,
-	).Scan(&exists)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar nodo"})
-		return
-	}
-
-	if !exists {
+	roadmapID := middleware.GetRoadmapID(c)
+	_, err := h.store.Nodes().GetByID(roadmapID, nodeID)
+	if errors.Is(err, repository.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Nodo no encontrado"})
 		return
-	}
-
-	now := time.Now()
-
-	// Crear el recurso
-	var resourceID int64
-	err = h.db.QueryRow(`
-		INSERT INTO resources (node_id, title, type, url, description, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id`,
-		nodeID, req.Title, req.ResourceType, req.URL, req.Description, now, now,
-	).Scan(&resourceID)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear recurso"})
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar nodo"})
 		return
 	}
 
-	// Devolver el recurso creado
+	now := time.Now()
 	resource := models.Resource{
-		ID:          resourceID,
 		NodeID:      nodeID,
 		Title:       req.Title,
 		Type:        req.ResourceType,
@@ -90,5 +61,17 @@ func (h *ResourceHandler) AddNodeResource(c *gin.Context) {
 		UpdatedAt:   now,
 	}
 
+	// Crear el recurso
+	err = h.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Resources().Create(&resource); err != nil {
+			return err
+		}
+		return recordRevision(tx, c, &models.Revision{RoadmapID: roadmapID, Action: models.RevisionActionResourceCreate})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear recurso"})
+		return
+	}
+
 	c.JSON(http.StatusCreated, resource)
-}
\ No newline at end of file
+}
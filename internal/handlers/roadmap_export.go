@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"Gin/internal/export"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportRoadmap descarga el roadmap como diagrama de Mermaid, grafo de
+// Graphviz, índice en Markdown, imagen SVG o documento en el formato de
+// intercambio JSON según el parámetro format. Los roadmaps públicos los puede
+// exportar cualquiera.
+func (h *RoadmapHandler) ExportRoadmap(c *gin.Context) {
+	format := export.Format(c.DefaultQuery("format", string(export.FormatMarkdown)))
+	if !format.Valid() && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido: usa mermaid, dot, markdown, svg o json"})
+		return
+	}
+
+	roadmapID, ok := parseIDParam(c, "id", "ID de roadmap inválido")
+	if !ok {
+		return
+	}
+	roadmap, err := h.store.Roadmaps().GetByID(roadmapID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !canViewRoadmap(c, h.store, roadmap)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+	snapshot, err := loadSnapshot(h.store, roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+	if format == "json" {
+		h.exportDocument(c, roadmap, snapshot)
+		return
+	}
+
+	data, err := export.Export(format, export.Roadmap{
+		Title:       roadmap.Title,
+		Description: roadmap.Description,
+		Snapshot:    *snapshot,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al exportar el roadmap"})
+		return
+	}
+
+	filename := "roadmap-" + roadmap.ID.String() + "." + format.Extension()
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, format.ContentType(), data)
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"Gin/internal/lint"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ForkRoadmap crea una copia de un roadmap existente para el usuario autenticado
+func (h *RoadmapHandler) ForkRoadmap(c *gin.Context) {
+	type forkRoadmapRequest struct {
+		Title    *string `json:"title"`
+		IsPublic bool    `json:"is_public"`
+	}
+
+	roadmapID, ok := parseIDParam(c, "id", "ID de roadmap inválido")
+	if !ok {
+		return
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	// El cuerpo es opcional
+	var req forkRoadmapRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+			return
+		}
+	}
+	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El título no puede estar vacío"})
+		return
+	}
+
+	source, err := h.store.Roadmaps().GetByID(roadmapID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !canViewRoadmap(c, h.store, source)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	fork := models.Roadmap{
+		Title:       source.Title,
+		Description: source.Description,
+		Category:    source.Category,
+		AuthorID:    userID,
+		IsPublic:    req.IsPublic,
+	}
+	if req.Title != nil {
+		fork.Title = *req.Title
+	}
+
+	var blockers []lint.Diagnostic
+	err = h.store.Transaction(func(tx repository.Store) error {
+		if err := forkRoadmap(tx, source.ID, &fork, time.Now()); err != nil {
+			return err
+		}
+		// Un fork público debe cumplir lo mismo que cualquier roadmap publicado
+		if fork.IsPublic {
+			var err error
+			if blockers, err = publishBlockers(tx, fork.ID); err != nil {
+				return err
+			}
+			if blockers != nil {
+				return errNotPublishable
+			}
+		}
+		return recordRevision(tx, c, &models.Revision{RoadmapID: fork.ID, Action: models.RevisionActionFork})
+	})
+	if errors.Is(err, errNotPublishable) {
+		respondNotPublishable(c, blockers)
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear el fork"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, fork)
+}
+
+// forkRoadmap crea fork como copia de sourceID: duplica nodos, conexiones y
+// recursos, reasigna los ids de nodo de las conexiones y registra el origen
+// de cada elemento copiado. El contenido copiado queda como base para
+// sincronizar después el fork con el original.
+func forkRoadmap(tx repository.Store, sourceID models.ID, fork *models.Roadmap, now time.Time) error {
+	fork.ForkedFrom = &sourceID
+	fork.ForkedAt = &now
+	if err := tx.Roadmaps().Create(fork); err != nil {
+		return err
+	}
+
+	source, err := loadSnapshot(tx, sourceID)
+	if err != nil {
+		return err
+	}
+
+	nodeMap := make(map[models.ID]models.ID, len(source.Nodes))
+	for _, node := range source.Nodes {
+		originID := node.ID
+		node.RoadmapID = fork.ID
+		node.OriginID = &originID
+		node.CreatedAt = now
+		node.UpdatedAt = now
+		if err := tx.Nodes().Create(&node); err != nil {
+			return err
+		}
+		nodeMap[originID] = node.ID
+	}
+
+	for _, conn := range source.Connections {
+		originID := conn.ID
+		conn.RoadmapID = fork.ID
+		conn.FromNodeID = nodeMap[conn.FromNodeID]
+		conn.ToNodeID = nodeMap[conn.ToNodeID]
+		conn.OriginID = &originID
+		conn.CreatedAt = now
+		conn.UpdatedAt = now
+		if err := tx.Connections().Create(&conn); err != nil {
+			return err
+		}
+	}
+
+	for _, resource := range source.Resources {
+		originID := resource.ID
+		resource.NodeID = nodeMap[resource.NodeID]
+		resource.OriginID = &originID
+		resource.CreatedAt = now
+		resource.UpdatedAt = now
+		if err := tx.Resources().Create(&resource); err != nil {
+			return err
+		}
+	}
+
+	return tx.Roadmaps().SaveForkBase(fork.ID, source)
+}
+
+// ListForks devuelve los forks de un roadmap visibles para el usuario
+func (h *RoadmapHandler) ListForks(c *gin.Context) {
+	roadmapID, ok := parseIDParam(c, "id", "ID de roadmap inválido")
+	if !ok {
+		return
+	}
+
+	roadmap, err := h.store.Roadmaps().GetByID(roadmapID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !canViewRoadmap(c, h.store, roadmap)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	forks, err := h.store.Roadmaps().ListForks(roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los forks"})
+		return
+	}
+
+	visible := make([]models.RoadmapSummary, 0, len(forks))
+	for _, fork := range forks {
+		if canViewRoadmap(c, h.store, &fork.Roadmap) {
+			visible = append(visible, fork)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"forks": visible})
+}
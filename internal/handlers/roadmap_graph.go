@@ -0,0 +1,515 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"Gin/internal/graph"
+	"Gin/internal/lint"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+var errVisibilityForbidden = errors.New("sin permiso para cambiar la visibilidad")
+
+// clientID es el identificador que envía el editor. Puede ser el ID de un
+// elemento ya guardado o una clave temporal para elementos nuevos.
+type clientID string
+
+// graphNodeRequest es un nodo tal como lo envía el editor
+type graphNodeRequest struct {
+	ID          clientID        `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Type        models.NodeType `json:"type"`
+	Position    models.Position `json:"position"`
+	Status      string          `json:"status"`
+	Color       string          `json:"color"`
+	OrderIndex  *int            `json:"order_index"`
+	Estimate    *float64        `json:"estimated_hours"`
+}
+
+// graphConnectionRequest es una conexión tal como la envía el editor
+type graphConnectionRequest struct {
+	ID             clientID              `json:"id"`
+	FromNodeID     clientID              `json:"from_node_id"`
+	ToNodeID       clientID              `json:"to_node_id"`
+	Label          string                `json:"label"`
+	ConnectionType models.ConnectionType `json:"connection_type"`
+}
+
+// saveGraphRequest es el estado completo del editor
+type saveGraphRequest struct {
+	Title       *string                  `json:"title"`
+	Description *string                  `json:"description"`
+	Category    *string                  `json:"category"`
+	IsPublic    *bool                    `json:"is_public"`
+	Nodes       []graphNodeRequest       `json:"nodes"`
+	Connections []graphConnectionRequest `json:"connections"`
+}
+
+// GetRoadmapGraph devuelve el roadmap con todos sus nodos y conexiones
+func (h *RoadmapHandler) GetRoadmapGraph(c *gin.Context) {
+	graph, err := loadRoadmapGraph(h.store, middleware.GetRoadmapID(c))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	setETag(c, graph.Version)
+	if notModified(c, graph.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, graph)
+}
+
+// ValidateRoadmap comprueba la integridad de los nodos y conexiones del
+// roadmap y devuelve todos los problemas encontrados
+func (h *RoadmapHandler) ValidateRoadmap(c *gin.Context) {
+	current, err := loadRoadmapGraph(h.store, middleware.GetRoadmapID(c))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	issues := graph.Validate(current.Nodes, current.Connections)
+	c.JSON(http.StatusOK, gin.H{
+		"valid":  len(issues) == 0,
+		"issues": issues,
+	})
+}
+
+// SaveRoadmapGraph aplica el estado completo del editor en una sola transacción,
+// creando, actualizando y eliminando únicamente lo que ha cambiado. Con
+// If-Match, solo se guarda si el roadmap sigue en la versión indicada; si no,
+// responde 409 con el estado actual para que el editor lo concilie.
+func (h *RoadmapHandler) SaveRoadmapGraph(c *gin.Context) {
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req saveGraphRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	if msg := req.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if cycle := req.requirementCycle(); cycle != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Las conexiones forman un ciclo de requisitos",
+			"cycle": cycle,
+		})
+		return
+	}
+
+	roadmapID := middleware.GetRoadmapID(c)
+	now := time.Now()
+
+	var saved *models.RoadmapGraph
+	var idMap map[string]models.ID
+	var blockers []lint.Diagnostic
+	var failure string
+	err := h.hub.Commit(roadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			version, err := tx.Roadmaps().Lock(roadmapID)
+			if err != nil {
+				failure = "Error al obtener el roadmap"
+				return err
+			}
+			if expected != 0 && version != expected {
+				return repository.ErrVersionConflict
+			}
+
+			current, err := loadRoadmapGraph(tx, roadmapID)
+			if err != nil {
+				failure = "Error al obtener el roadmap"
+				return err
+			}
+			if req.IsPublic != nil && *req.IsPublic != current.IsPublic && !canChangeVisibility(c) {
+				return errVisibilityForbidden
+			}
+			publishing := req.IsPublic != nil && *req.IsPublic && !current.IsPublic
+
+			if err := applyRoadmapMetadata(tx, &current.Roadmap, req, now); err != nil {
+				failure = "Error al actualizar el roadmap"
+				return err
+			}
+
+			if idMap, err = applyGraphNodes(tx, roadmapID, current.Nodes, req.Nodes, now); err != nil {
+				failure = "Error al guardar los nodos"
+				return err
+			}
+
+			if err := applyGraphConnections(tx, roadmapID, current.Connections, req.Connections, idMap, now); err != nil {
+				failure = "Error al guardar las conexiones"
+				return err
+			}
+
+			// Antes de publicarlo el contenido guardado no puede tener errores
+			if publishing {
+				if blockers, err = publishBlockers(tx, roadmapID); err != nil {
+					failure = "Error al revisar el roadmap"
+					return err
+				}
+				if blockers != nil {
+					return errNotPublishable
+				}
+			}
+
+			revision := models.Revision{RoadmapID: roadmapID, Action: models.RevisionActionGraphSave}
+			if err := recordRevision(tx, c, &revision); err != nil {
+				failure = "Error al guardar la revisión"
+				return err
+			}
+
+			if saved, err = loadRoadmapGraph(tx, roadmapID); err != nil {
+				failure = "Error al obtener el roadmap"
+				return err
+			}
+			if saved.Version != version {
+				emit(realtime.EventGraphReplaced, gin.H{"action": models.RevisionActionGraphSave, "revision": revision.Number})
+			}
+			return nil
+		})
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if errors.Is(err, repository.ErrVersionConflict) {
+		h.respondGraphConflict(c, roadmapID)
+		return
+	} else if errors.Is(err, errVisibilityForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Solo los administradores pueden cambiar la visibilidad del roadmap"})
+		return
+	} else if errors.Is(err, errNotPublishable) {
+		respondNotPublishable(c, blockers)
+		return
+	} else if err != nil {
+		if failure == "" {
+			failure = "Error al confirmar transacción"
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure})
+		return
+	}
+
+	setETag(c, saved.Version)
+	c.JSON(http.StatusOK, gin.H{
+		"roadmap": saved,
+		"id_map":  idMap,
+	})
+}
+
+// respondGraphConflict responde 409 con el estado actual del roadmap
+func (h *RoadmapHandler) respondGraphConflict(c *gin.Context, roadmapID models.ID) {
+	current, err := loadRoadmapGraph(h.store, roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	setETag(c, current.Version)
+	c.JSON(http.StatusConflict, gin.H{
+		"error":   "El roadmap fue modificado por otra sesión",
+		"current": current,
+	})
+}
+
+// validate comprueba la coherencia del estado enviado por el editor y
+// completa los valores por defecto
+func (req *saveGraphRequest) validate() string {
+	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
+		return "El título no puede estar vacío"
+	}
+
+	nodeIDs := make(map[clientID]bool, len(req.Nodes))
+	for i := range req.Nodes {
+		node := &req.Nodes[i]
+		if node.ID == "" {
+			return "Todos los nodos deben tener un id"
+		}
+		if nodeIDs[node.ID] {
+			return fmt.Sprintf("Nodo duplicado: %s", node.ID)
+		}
+		nodeIDs[node.ID] = true
+
+		if strings.TrimSpace(node.Title) == "" {
+			return fmt.Sprintf("El nodo %s no tiene título", node.ID)
+		}
+		if node.Type == "" {
+			node.Type = models.NodeTypeTopic
+		}
+		if !node.Type.Valid() {
+			return fmt.Sprintf("Tipo de nodo inválido: %s", node.Type)
+		}
+		if node.Estimate != nil && *node.Estimate <= 0 {
+			return fmt.Sprintf("El nodo %s tiene una estimación inválida", node.ID)
+		}
+		if node.Status == "" {
+			node.Status = "not_started"
+		}
+	}
+
+	edges := make(map[[2]clientID]bool, len(req.Connections))
+	for i := range req.Connections {
+		conn := &req.Connections[i]
+		if !nodeIDs[conn.FromNodeID] || !nodeIDs[conn.ToNodeID] {
+			return "Las conexiones deben referenciar nodos del roadmap"
+		}
+		if conn.FromNodeID == conn.ToNodeID {
+			return "Un nodo no puede conectarse consigo mismo"
+		}
+		key := [2]clientID{conn.FromNodeID, conn.ToNodeID}
+		if edges[key] {
+			return "Ya existe una conexión entre estos nodos"
+		}
+		edges[key] = true
+
+		if conn.ConnectionType == "" {
+			conn.ConnectionType = models.ConnectionTypeDefault
+		}
+		if !conn.ConnectionType.Valid() {
+			return fmt.Sprintf("Tipo de conexión inválido: %s", conn.ConnectionType)
+		}
+	}
+
+	return ""
+}
+
+// requirementCycle devuelve, con los ids del editor, el primer ciclo que
+// forman las conexiones de requisito enviadas, o nil si no hay ninguno
+func (req *saveGraphRequest) requirementCycle() []clientID {
+	nodes := make([]models.Node, len(req.Nodes))
+	for i, node := range req.Nodes {
+		nodes[i] = models.Node{ID: models.ID(node.ID)}
+	}
+	connections := make([]models.Connection, len(req.Connections))
+	for i, conn := range req.Connections {
+		connections[i] = models.Connection{
+			FromNodeID:     models.ID(conn.FromNodeID),
+			ToNodeID:       models.ID(conn.ToNodeID),
+			ConnectionType: conn.ConnectionType,
+		}
+	}
+
+	cycles := graph.New(nodes, connections).Cycles()
+	if len(cycles) == 0 {
+		return nil
+	}
+	cycle := make([]clientID, len(cycles[0]))
+	for i, id := range cycles[0] {
+		cycle[i] = clientID(id)
+	}
+	return cycle
+}
+
+// applyRoadmapMetadata actualiza los datos generales del roadmap si han cambiado
+func applyRoadmapMetadata(tx repository.Store, roadmap *models.Roadmap, req saveGraphRequest, now time.Time) error {
+	updated := *roadmap
+	if req.Title != nil {
+		updated.Title = *req.Title
+	}
+	if req.Description != nil {
+		updated.Description = *req.Description
+	}
+	if req.Category != nil {
+		updated.Category = *req.Category
+	}
+	if req.IsPublic != nil {
+		updated.IsPublic = *req.IsPublic
+	}
+
+	if updated.Title == roadmap.Title && updated.Description == roadmap.Description &&
+		updated.Category == roadmap.Category && updated.IsPublic == roadmap.IsPublic {
+		return nil
+	}
+
+	updated.UpdatedAt = now
+	if err := tx.Roadmaps().Update(&updated); err != nil {
+		return err
+	}
+
+	*roadmap = updated
+	return nil
+}
+
+// applyGraphNodes sincroniza los nodos guardados con los del editor y devuelve
+// la correspondencia entre los ids del editor y los ids definitivos
+func applyGraphNodes(tx repository.Store, roadmapID models.ID, current []models.Node, nodes []graphNodeRequest, now time.Time) (map[string]models.ID, error) {
+	existing := make(map[string]models.Node, len(current))
+	for _, node := range current {
+		existing[node.ID.String()] = node
+	}
+
+	idMap := make(map[string]models.ID, len(nodes))
+	for _, req := range nodes {
+		if node, ok := existing[string(req.ID)]; ok {
+			idMap[string(req.ID)] = node.ID
+			delete(existing, string(req.ID))
+
+			if node.Title == req.Title && node.Description == req.Description && node.Type == req.Type &&
+				node.Position == req.Position && node.Status == req.Status && node.Color == req.Color &&
+				equalOptional(node.OrderIndex, req.OrderIndex) && equalOptional(node.Estimate, req.Estimate) {
+				continue
+			}
+
+			req.apply(&node)
+			node.UpdatedAt = now
+			if err := tx.Nodes().Update(&node); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		node := models.Node{RoadmapID: roadmapID, CreatedAt: now, UpdatedAt: now}
+		req.apply(&node)
+		if err := tx.Nodes().Create(&node); err != nil {
+			return nil, err
+		}
+		idMap[string(req.ID)] = node.ID
+	}
+
+	// Los nodos que ya no están en el editor se eliminan junto con sus dependencias
+	for _, node := range existing {
+		if err := tx.Nodes().Delete(roadmapID, node.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return idMap, nil
+}
+
+// apply copia en node los campos editables del nodo enviado por el editor
+func (req graphNodeRequest) apply(node *models.Node) {
+	node.Title = req.Title
+	node.Description = req.Description
+	node.Type = req.Type
+	node.Position = req.Position
+	node.Status = req.Status
+	node.Color = req.Color
+	node.OrderIndex = req.OrderIndex
+	node.Estimate = req.Estimate
+}
+
+// equalOptional compara dos valores opcionales por su valor
+func equalOptional[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// applyGraphConnections sincroniza las conexiones guardadas con las del editor
+func applyGraphConnections(tx repository.Store, roadmapID models.ID, current []models.Connection, connections []graphConnectionRequest, idMap map[string]models.ID, now time.Time) error {
+	kept := make(map[models.ID]bool, len(idMap))
+	for _, id := range idMap {
+		kept[id] = true
+	}
+
+	// Las conexiones de nodos eliminados ya se borraron junto con sus nodos
+	existing := make(map[string]models.Connection, len(current))
+	for _, conn := range current {
+		if kept[conn.FromNodeID] && kept[conn.ToNodeID] {
+			existing[conn.ID.String()] = conn
+		}
+	}
+
+	var updates []models.Connection
+	var inserts []models.Connection
+	for _, req := range connections {
+		next := models.Connection{
+			RoadmapID:      roadmapID,
+			FromNodeID:     idMap[string(req.FromNodeID)],
+			ToNodeID:       idMap[string(req.ToNodeID)],
+			Label:          req.Label,
+			ConnectionType: req.ConnectionType,
+		}
+
+		conn, ok := existing[string(req.ID)]
+		if !ok {
+			inserts = append(inserts, next)
+			continue
+		}
+		delete(existing, string(req.ID))
+
+		if conn.FromNodeID != next.FromNodeID || conn.ToNodeID != next.ToNodeID ||
+			conn.Label != next.Label || conn.ConnectionType != next.ConnectionType {
+			next.ID = conn.ID
+			updates = append(updates, next)
+		}
+	}
+
+	// Eliminar primero para no chocar con conexiones que se reemplazan
+	for _, conn := range existing {
+		if err := tx.Connections().Delete(roadmapID, conn.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, conn := range updates {
+		conn.UpdatedAt = now
+		if err := tx.Connections().Update(&conn); err != nil {
+			return err
+		}
+	}
+
+	for _, conn := range inserts {
+		conn.CreatedAt = now
+		conn.UpdatedAt = now
+		if err := tx.Connections().Create(&conn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadRoadmapGraph obtiene un roadmap con sus nodos y conexiones
+func loadRoadmapGraph(store repository.Store, roadmapID models.ID) (*models.RoadmapGraph, error) {
+	roadmap, err := store.Roadmaps().GetByID(roadmapID)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := models.RoadmapGraph{Roadmap: *roadmap}
+	if graph.Nodes, err = store.Nodes().ListByRoadmap(roadmapID); err != nil {
+		return nil, err
+	}
+	if graph.Connections, err = store.Connections().ListByRoadmap(roadmapID); err != nil {
+		return nil, err
+	}
+
+	return &graph, nil
+}
+
+// loadSnapshot obtiene todos los nodos, conexiones y recursos de un roadmap
+func loadSnapshot(store repository.Store, roadmapID models.ID) (*models.Snapshot, error) {
+	var snapshot models.Snapshot
+	var err error
+	if snapshot.Nodes, err = store.Nodes().ListByRoadmap(roadmapID); err != nil {
+		return nil, err
+	}
+	if snapshot.Connections, err = store.Connections().ListByRoadmap(roadmapID); err != nil {
+		return nil, err
+	}
+	if snapshot.Resources, err = store.Resources().ListByRoadmap(roadmapID); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
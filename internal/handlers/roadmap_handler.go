@@ -1,24 +1,34 @@
 package handlers
 
 import (
+	"Gin/internal/graph"
+	"Gin/internal/middleware"
 	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
 	"Gin/views/components"
 	"Gin/views/layouts"
 	"Gin/views/pages"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/a-h/templ"
 	"github.com/gin-gonic/gin"
 )
 
 type RoadmapHandler struct {
+	store repository.Store
+	hub   *realtime.Hub
 }
 
 // NewRoadmapHandler crea una nueva instancia de RoadmapHandler
-func NewRoadmapHandler() *RoadmapHandler {
-	return &RoadmapHandler{}
+func NewRoadmapHandler(store repository.Store, hub *realtime.Hub) *RoadmapHandler {
+	return &RoadmapHandler{store: store, hub: hub}
 }
 
 // ListRoadmaps muestra la página principal con los roadmaps destacados
@@ -32,126 +42,389 @@ func (h *RoadmapHandler) ListRoadmaps(c *gin.Context) {
 
 // ViewRoadmap muestra un roadmap específico
 func (h *RoadmapHandler) ViewRoadmap(c *gin.Context) {
-	roadmapID := c.Param("id")
-
-	// TODO: Obtener datos del roadmap desde la base de datos
-	roadmap := &models.RoadmapDetailProps{
-		ID:          roadmapID,
-		Title:       "Aprendiendo Go desde Cero",
-		Description: "Una guía completa para aprender Go, desde los conceptos básicos hasta temas avanzados.",
-		Author: struct {
-			ID        string
-			Name      string
-			AvatarURL string
-		}{
-			ID:        "1",
-			Name:      "John Doe",
-			AvatarURL: "https://api.dicebear.com/7.x/avataaars/svg?seed=john",
-		},
-		Stats: struct {
-			Views     int
-			Forks     int
-			Favorites int
-		}{
-			Views:     1234,
-			Forks:     7,
-			Favorites: 56,
-		},
-		Nodes: []models.RoadmapNodeProps{
-			{
-				ID:          "1",
-				Title:       "Introducción a Go",
-				Description: "Conceptos básicos del lenguaje Go",
-				Type:        "topic",
-				PositionX:   100,
-				PositionY:   100,
-				Status:      "completed",
-				Connections: []struct {
-					TargetID string
-					Type     string
-				}{
-					{TargetID: "2", Type: "required"},
-				},
-			},
-			{
-				ID:          "2",
-				Title:       "Estructuras de Control",
-				Description: "If, switch, for y más",
-				Type:        "topic",
-				PositionX:   300,
-				PositionY:   100,
-				Status:      "in_progress",
-				Connections: []struct {
-					TargetID string
-					Type     string
-				}{},
-			},
-		},
+	roadmapID, err := models.ParseID(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	stored, err := h.store.Roadmaps().GetByID(roadmapID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if !canViewRoadmap(c, h.store, stored) {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	author, err := h.store.Users().GetByID(stored.AuthorID)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	roadmap := models.RoadmapDetailProps{
+		ID:          stored.ID.String(),
+		Title:       stored.Title,
+		Description: stored.Description,
+	}
+	roadmap.Author.ID = author.ID.String()
+	roadmap.Author.Name = author.Username
+	roadmap.Author.AvatarURL = avatarURL(author)
+
+	stats, err := h.store.Roadmaps().GetStats(roadmapID)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	roadmap.Stats.Views = stats.Views
+	roadmap.Stats.Forks = stats.Forks
+	roadmap.Stats.Favorites = stats.Favorites
+
+	// El estado de cada nodo es el del progreso del usuario que lo visita
+	var statuses map[models.ID]models.ProgressStatus
+	if userID, ok := middleware.GetUserID(c); ok {
+		summary, err := progressSummary(h.store, roadmapID, userID)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		statuses = nodeStatuses(summary.Nodes)
+		roadmap.Progress = summary
+	}
+	if roadmap.Nodes, err = h.getNodeProps(roadmapID, statuses); err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	if roadmap.Resources, err = h.getResourceProps(roadmapID); err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	if roadmap.Reviews, err = h.getReviewProps(roadmapID); err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
 	}
 
-	content := pages.RoadmapDetail(*roadmap)
+	content := pages.RoadmapDetail(roadmap)
 	component := layouts.Base(roadmap.Title, content)
 	component.Render(c.Request.Context(), c.Writer)
 }
 
+// canChangeVisibility indica si el usuario puede hacer público o privado el roadmap
+func canChangeVisibility(c *gin.Context) bool {
+	return middleware.GetRoadmapRole(c).Includes(models.RoleAdmin)
+}
+
+// canViewRoadmap indica si el usuario de la petición puede ver el roadmap.
+// Los roadmaps privados solo son visibles para su autor, sus colaboradores y
+// los miembros de su organización.
+func canViewRoadmap(c *gin.Context, store repository.Store, roadmap *models.Roadmap) bool {
+	if roadmap.IsPublic {
+		return true
+	}
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return false
+	}
+	role, err := middleware.RoadmapRole(store, roadmap, userID)
+	return err == nil && role != ""
+}
+
+// getNodeProps obtiene los nodos del roadmap junto con sus conexiones
+// salientes. Los nodos sin estado en statuses aparecen como no empezados y,
+// si hay estados, se bloquean los que tienen requisitos sin completar.
+func (h *RoadmapHandler) getNodeProps(roadmapID models.ID, statuses map[models.ID]models.ProgressStatus) ([]models.RoadmapNodeProps, error) {
+	nodes, err := h.store.Nodes().ListByRoadmap(roadmapID)
+	if err != nil {
+		return nil, err
+	}
+	connections, err := h.store.Connections().ListByRoadmap(roadmapID)
+	if err != nil {
+		return nil, err
+	}
+
+	g := graph.New(nodes, connections)
+	props := make([]models.RoadmapNodeProps, len(nodes))
+	index := make(map[models.ID]int, len(nodes))
+	for i, node := range nodes {
+		index[node.ID] = i
+		status, ok := statuses[node.ID]
+		if !ok {
+			status = models.ProgressNotStarted
+		}
+		props[i] = models.RoadmapNodeProps{
+			ID:          node.ID.String(),
+			Title:       node.Title,
+			Description: node.Description,
+			Type:        string(node.Type),
+			PositionX:   node.Position.X,
+			PositionY:   node.Position.Y,
+			Status:      string(status),
+			Locked:      statuses != nil && status != models.ProgressCompleted && len(g.Missing(node.ID, statuses)) > 0,
+		}
+	}
+
+	for _, conn := range connections {
+		i, ok := index[conn.FromNodeID]
+		if !ok {
+			continue
+		}
+		props[i].Connections = append(props[i].Connections, struct {
+			TargetID string
+			Type     string
+		}{TargetID: conn.ToNodeID.String(), Type: string(conn.ConnectionType)})
+	}
+
+	return props, nil
+}
+
+// getResourceProps obtiene todos los recursos de los nodos del roadmap
+func (h *RoadmapHandler) getResourceProps(roadmapID models.ID) ([]models.ResourceProps, error) {
+	resources, err := h.store.Resources().ListByRoadmap(roadmapID)
+	if err != nil {
+		return nil, err
+	}
+
+	props := make([]models.ResourceProps, len(resources))
+	for i, resource := range resources {
+		props[i] = models.ResourceProps{
+			ID:          resource.ID.String(),
+			Title:       resource.Title,
+			Type:        resource.Type,
+			URL:         resource.URL,
+			Description: resource.Description,
+		}
+	}
+
+	return props, nil
+}
+
+// getReviewProps obtiene las reseñas del roadmap con los datos de sus autores
+func (h *RoadmapHandler) getReviewProps(roadmapID models.ID) ([]models.ReviewProps, error) {
+	reviews, err := h.store.Reviews().ListByRoadmap(roadmapID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[models.ID]*models.User)
+	props := make([]models.ReviewProps, 0, len(reviews))
+	for _, review := range reviews {
+		user, ok := users[review.UserID]
+		if !ok {
+			if user, err = h.store.Users().GetByID(review.UserID); err != nil {
+				return nil, err
+			}
+			users[review.UserID] = user
+		}
+
+		props = append(props, models.ReviewProps{
+			ID:        review.ID.String(),
+			UserName:  user.Username,
+			AvatarURL: avatarURL(user),
+			Rating:    review.Rating,
+			Comment:   review.Comment,
+			CreatedAt: timeAgo(review.CreatedAt),
+		})
+	}
+
+	return props, nil
+}
+
+// avatarURL devuelve el avatar del usuario o uno generado a partir de su nombre
+func avatarURL(user *models.User) string {
+	if user.AvatarURL != "" {
+		return user.AvatarURL
+	}
+	return "https://api.dicebear.com/7.x/avataaars/svg?seed=" + user.Username
+}
+
 // CreateRoadmap maneja la creación de un nuevo roadmap
 func (h *RoadmapHandler) CreateRoadmap(c *gin.Context) {
-	// TODO: Implementar creación de roadmap
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Creación de roadmap implementada próximamente",
+	type createRoadmapRequest struct {
+		Title       string `json:"title" binding:"required"`
+		Description string `json:"description"`
+		Category    string `json:"category"`
+		IsPublic    bool   `json:"is_public"`
+		// OrganizationID es opcional; el autor debe ser miembro de la organización
+		OrganizationID *models.ID `json:"organization_id"`
+	}
+
+	var req createRoadmapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if req.OrganizationID != nil {
+		role, err := middleware.OrganizationRole(h.store, *req.OrganizationID, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar la organización"})
+			return
+		}
+		if role == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Organización no encontrada"})
+			return
+		}
+	}
+
+	roadmap := models.Roadmap{
+		Title:          req.Title,
+		Description:    req.Description,
+		Category:       req.Category,
+		AuthorID:       userID,
+		IsPublic:       req.IsPublic,
+		OrganizationID: req.OrganizationID,
+	}
+
+	// La primera revisión es el roadmap vacío, al que también se puede volver
+	err := h.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Roadmaps().Create(&roadmap); err != nil {
+			return err
+		}
+		return recordRevision(tx, c, &models.Revision{RoadmapID: roadmap.ID, Action: models.RevisionActionCreate})
 	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear el roadmap"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, roadmap)
 }
 
 // UpdateRoadmap maneja la actualización de un roadmap
 func (h *RoadmapHandler) UpdateRoadmap(c *gin.Context) {
-	id := c.Param("id")
-	// TODO: Implementar actualización de roadmap
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Actualización de roadmap implementada próximamente",
-		"id":      id,
+	type updateRoadmapRequest struct {
+		Title       *string `json:"title"`
+		Description *string `json:"description"`
+		Category    *string `json:"category"`
+		IsPublic    *bool   `json:"is_public"`
+	}
+
+	var req updateRoadmapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	if req.Title != nil && *req.Title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El título no puede estar vacío"})
+		return
+	}
+
+	// Obtener el roadmap actual
+	roadmap, err := h.store.Roadmaps().GetByID(middleware.GetRoadmapID(c))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	// Actualizar solo los campos proporcionados
+	if req.Title != nil {
+		roadmap.Title = *req.Title
+	}
+	if req.Description != nil {
+		roadmap.Description = *req.Description
+	}
+	if req.Category != nil {
+		roadmap.Category = *req.Category
+	}
+	if req.IsPublic != nil {
+		if *req.IsPublic != roadmap.IsPublic && !canChangeVisibility(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Solo los administradores pueden cambiar la visibilidad del roadmap"})
+			return
+		}
+		// Antes de publicarlo el contenido no puede tener errores
+		if *req.IsPublic && !roadmap.IsPublic {
+			blockers, err := publishBlockers(h.store, roadmap.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al revisar el roadmap"})
+				return
+			}
+			if blockers != nil {
+				respondNotPublishable(c, blockers)
+				return
+			}
+		}
+		roadmap.IsPublic = *req.IsPublic
+	}
+	roadmap.UpdatedAt = time.Now()
+
+	// Actualizar el roadmap y registrar el cambio en el historial
+	revision := models.Revision{RoadmapID: roadmap.ID, Action: models.RevisionActionRoadmapUpdate}
+	err = h.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Roadmaps().Update(roadmap); err != nil {
+			return err
+		}
+		return recordRevision(tx, c, &revision)
 	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el roadmap"})
+		return
+	}
+
+	c.JSON(http.StatusOK, roadmap)
 }
 
 // DeleteRoadmap maneja la eliminación de un roadmap
 func (h *RoadmapHandler) DeleteRoadmap(c *gin.Context) {
-	id := c.Param("id")
-	// TODO: Implementar eliminación de roadmap
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Eliminación de roadmap implementada próximamente",
-		"id":      id,
-	})
-}
+	// Los nodos, conexiones y recursos se eliminan en cascada
+	err := h.store.Roadmaps().Delete(middleware.GetRoadmapID(c))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar el roadmap"})
+		return
+	}
 
-// ForkRoadmap crea una copia de un roadmap existente
-func (h *RoadmapHandler) ForkRoadmap(c *gin.Context) {
-	id := c.Param("id")
-	// TODO: Implementar fork de roadmap
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Fork de roadmap implementado próximamente",
-		"id":      id,
-	})
+	c.JSON(http.StatusOK, gin.H{"message": "Roadmap eliminado correctamente"})
 }
 
-// AddReview añade una reseña a un roadmap
+// AddReview añade la reseña del usuario autenticado al roadmap. Cada usuario
+// puede reseñar un roadmap una sola vez.
 func (h *RoadmapHandler) AddReview(c *gin.Context) {
-	id := c.Param("id")
-	// TODO: Implementar añadir reseña
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Añadir reseña implementado próximamente",
-		"id":      id,
-	})
-}
+	type addReviewRequest struct {
+		Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+		Comment string `json:"comment"`
+	}
 
-// UpdateProgress actualiza el progreso en un nodo del roadmap
-func (h *RoadmapHandler) UpdateProgress(c *gin.Context) {
-	roadmapID := c.Param("roadmap_id")
-	nodeID := c.Param("node_id")
-	// TODO: Implementar actualización de progreso
-	c.JSON(http.StatusOK, gin.H{
-		"message":    "Actualización de progreso implementada próximamente",
-		"roadmap_id": roadmapID,
-		"node_id":    nodeID,
-	})
+	var req addReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "La valoración debe estar entre 1 y 5"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	review := models.Review{
+		RoadmapID: middleware.GetRoadmapID(c),
+		UserID:    userID,
+		Rating:    req.Rating,
+		Comment:   strings.TrimSpace(req.Comment),
+	}
+	err := h.store.Reviews().Create(&review)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Ya has reseñado este roadmap"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al guardar la reseña"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
 }
 
 // GetNodeResources obtiene los recursos de un nodo específico
@@ -203,17 +476,6 @@ func (h *RoadmapHandler) GetRoadmapReviews(c *gin.Context) {
 	component.Render(c.Request.Context(), c.Writer)
 }
 
-func (h *RoadmapHandler) CompleteNode(c *gin.Context) {
-	// TODO: Actualizar el estado del nodo en la base de datos
-	success := true
-
-	if success {
-		c.JSON(http.StatusOK, gin.H{"status": "completed"})
-	} else {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo marcar el nodo como completado"})
-	}
-}
-
 // Helper functions para renderizar componentes
 func renderResources(resources []models.ResourceProps) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
@@ -237,4 +499,30 @@ func renderReviews(reviews []models.ReviewProps) templ.Component {
 		}
 		return nil
 	})
-}
\ No newline at end of file
+}
+
+// timeAgo formatea una fecha de forma relativa ("hace 2 días")
+func timeAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "hace un momento"
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minuto", "minutos")
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hora", "horas")
+	case d < 30*24*time.Hour:
+		return plural(int(d.Hours()/24), "día", "días")
+	case d < 365*24*time.Hour:
+		return plural(int(d.Hours()/(24*30)), "mes", "meses")
+	default:
+		return plural(int(d.Hours()/(24*365)), "año", "años")
+	}
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return "hace 1 " + singular
+	}
+	return fmt.Sprintf("hace %d %s", n, pluralForm)
+}
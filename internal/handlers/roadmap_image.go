@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"Gin/internal/export"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// RoadmapImage dibuja el roadmap como imagen SVG en una URL estable, pensada
+// para las tarjetas de exploración y para incrustarla en un README. La imagen
+// se cachea por la versión del roadmap. Con ?user=<id> se dibuja encima el
+// progreso de ese usuario, que solo pueden ver él mismo y los
+// administradores del roadmap; esa imagen no se comparte entre clientes.
+func (h *RoadmapHandler) RoadmapImage(c *gin.Context) {
+	roadmapID, ok := parseIDParam(c, "id", "ID de roadmap inválido")
+	if !ok {
+		return
+	}
+	roadmap, err := h.store.Roadmaps().GetByID(roadmapID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !canViewRoadmap(c, h.store, roadmap)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	var progress map[models.ID]models.ProgressStatus
+	if user := c.Query("user"); user != "" {
+		userID, err := models.ParseID(user)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+			return
+		}
+		if !canViewProgress(c, h.store, roadmap, userID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "No tienes permiso para ver el progreso de este usuario"})
+			return
+		}
+		entries, err := h.store.Progress().ListByRoadmap(userID, roadmapID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso"})
+			return
+		}
+		progress = nodeStatuses(entries)
+		c.Header("Cache-Control", "private, no-cache")
+	} else {
+		if roadmap.IsPublic {
+			c.Header("Cache-Control", "public, max-age=300")
+		} else {
+			c.Header("Cache-Control", "private, no-cache")
+		}
+		setETag(c, roadmap.Version)
+		if notModified(c, roadmap.Version) {
+			return
+		}
+	}
+
+	snapshot, err := loadSnapshot(h.store, roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+	data, err := export.Export(export.FormatSVG, export.Roadmap{
+		Title:       roadmap.Title,
+		Description: roadmap.Description,
+		Snapshot:    *snapshot,
+		Progress:    progress,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al dibujar el roadmap"})
+		return
+	}
+	c.Data(http.StatusOK, export.FormatSVG.ContentType(), data)
+}
+
+// canViewProgress indica si el usuario autenticado puede ver el progreso de
+// userID en el roadmap: el suyo propio siempre, el de otros solo si administra
+// el roadmap
+func canViewProgress(c *gin.Context, store repository.Store, roadmap *models.Roadmap, userID models.ID) bool {
+	viewerID, ok := middleware.GetUserID(c)
+	if !ok {
+		return false
+	}
+	if viewerID == userID {
+		return true
+	}
+	role, err := middleware.RoadmapRole(store, roadmap, viewerID)
+	return err == nil && role.Includes(models.RoleAdmin)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"Gin/internal/interchange"
+	"Gin/internal/lint"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize es el tamaño máximo de un documento importado
+const maxImportSize = 10 << 20
+
+// exportDocument descarga el roadmap en el formato de intercambio JSON. El
+// documento no lleva nada propio de la instalación, así que lo puede obtener
+// cualquiera que pueda ver el roadmap.
+func (h *RoadmapHandler) exportDocument(c *gin.Context, roadmap *models.Roadmap, snapshot *models.Snapshot) {
+	filename := "roadmap-" + roadmap.ID.String() + ".json"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.JSON(http.StatusOK, interchange.Export(*roadmap, *snapshot, time.Now().UTC()))
+}
+
+// ImportRoadmap crea un roadmap nuevo del usuario a partir de un documento en
+// el formato de intercambio. Los problemas del documento se devuelven todos
+// juntos con la ruta JSON de cada uno; si hay alguno no se crea nada.
+func (h *RoadmapHandler) ImportRoadmap(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	doc, problems := interchange.Decode(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize))
+	if problems == nil {
+		problems = doc.Validate()
+	}
+	if len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    "El documento no es válido",
+			"problems": problems,
+		})
+		return
+	}
+
+	snapshot := doc.Snapshot()
+	if doc.Roadmap.IsPublic {
+		diagnostics := lint.Run(snapshot)
+		if lint.Count(diagnostics, lint.SeverityError) > 0 {
+			respondNotPublishable(c, diagnostics)
+			return
+		}
+	}
+
+	roadmap := models.Roadmap{
+		Title:       doc.Roadmap.Title,
+		Description: doc.Roadmap.Description,
+		Category:    doc.Roadmap.Category,
+		AuthorID:    userID,
+		IsPublic:    doc.Roadmap.IsPublic,
+	}
+	err := h.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Roadmaps().Create(&roadmap); err != nil {
+			return err
+		}
+		if err := createContent(tx, roadmap.ID, snapshot, time.Now()); err != nil {
+			return err
+		}
+		return recordRevision(tx, c, &models.Revision{RoadmapID: roadmap.ID, Action: models.RevisionActionImport})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al importar el roadmap"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"roadmap":     roadmap,
+		"nodes":       len(snapshot.Nodes),
+		"connections": len(snapshot.Connections),
+		"resources":   len(snapshot.Resources),
+	})
+}
+
+// createContent crea en el roadmap los nodos, conexiones y recursos de
+// snapshot con IDs nuevos y reasigna las referencias entre ellos
+func createContent(tx repository.Store, roadmapID models.ID, snapshot models.Snapshot, now time.Time) error {
+	nodeMap := make(map[models.ID]models.ID, len(snapshot.Nodes))
+	for _, node := range snapshot.Nodes {
+		key := node.ID
+		node.RoadmapID = roadmapID
+		node.CreatedAt = now
+		node.UpdatedAt = now
+		if err := tx.Nodes().Create(&node); err != nil {
+			return err
+		}
+		nodeMap[key] = node.ID
+	}
+
+	for _, conn := range snapshot.Connections {
+		conn.RoadmapID = roadmapID
+		conn.FromNodeID = nodeMap[conn.FromNodeID]
+		conn.ToNodeID = nodeMap[conn.ToNodeID]
+		conn.CreatedAt = now
+		conn.UpdatedAt = now
+		if err := tx.Connections().Create(&conn); err != nil {
+			return err
+		}
+	}
+
+	for _, resource := range snapshot.Resources {
+		resource.NodeID = nodeMap[resource.NodeID]
+		resource.CreatedAt = now
+		resource.UpdatedAt = now
+		if err := tx.Resources().Create(&resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
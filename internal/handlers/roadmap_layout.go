@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"Gin/internal/layout"
+	"github.com/gin-gonic/gin"
+)
+
+// GetLayout calcula una colocación automática de los nodos del roadmap sin
+// guardarla. Las posiciones tienen el formato de PUT /nodes/positions, con la
+// versión de cada nodo, para poder aplicarlas tal cual tras previsualizarlas.
+func (h *RoadmapHandler) GetLayout(c *gin.Context) {
+	direction := layout.Direction(c.DefaultQuery("direction", string(layout.DirectionTopDown)))
+	if !direction.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dirección inválida"})
+		return
+	}
+
+	roadmapID, ok := h.visibleRoadmapID(c)
+	if !ok {
+		return
+	}
+	nodes, err := h.store.Nodes().ListByRoadmap(roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los nodos"})
+		return
+	}
+	connections, err := h.store.Connections().ListByRoadmap(roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las conexiones"})
+		return
+	}
+
+	opts := layout.DefaultOptions
+	opts.Direction = direction
+	result := layout.Layout(nodes, connections, opts)
+
+	positions := make([]gin.H, len(nodes))
+	for i, node := range nodes {
+		position := result.Positions[node.ID]
+		positions[i] = gin.H{
+			"node_id":    node.ID,
+			"position_x": position.X,
+			"position_y": position.Y,
+			"version":    node.Version,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"direction": direction,
+		"layers":    result.Layers,
+		"crossings": result.Crossings,
+		"positions": positions,
+	})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"Gin/internal/lint"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+var errNotPublishable = errors.New("el roadmap tiene errores que impiden publicarlo")
+
+// LintRoadmap revisa la calidad del contenido del roadmap y devuelve los
+// diagnósticos de todas las reglas
+func (h *RoadmapHandler) LintRoadmap(c *gin.Context) {
+	snapshot, err := loadSnapshot(h.store, middleware.GetRoadmapID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	diagnostics := lint.Run(*snapshot)
+	errorCount := lint.Count(diagnostics, lint.SeverityError)
+	c.JSON(http.StatusOK, gin.H{
+		"diagnostics": diagnostics,
+		"errors":      errorCount,
+		"warnings":    lint.Count(diagnostics, lint.SeverityWarning),
+		"infos":       lint.Count(diagnostics, lint.SeverityInfo),
+		"publishable": errorCount == 0,
+	})
+}
+
+// publishBlockers revisa el roadmap antes de hacerlo público. Si hay errores
+// devuelve todos los diagnósticos para mostrarlos; si no, nil.
+func publishBlockers(store repository.Store, roadmapID models.ID) ([]lint.Diagnostic, error) {
+	snapshot, err := loadSnapshot(store, roadmapID)
+	if err != nil {
+		return nil, err
+	}
+	diagnostics := lint.Run(*snapshot)
+	if lint.Count(diagnostics, lint.SeverityError) == 0 {
+		return nil, nil
+	}
+	return diagnostics, nil
+}
+
+// respondNotPublishable responde 422 con los diagnósticos que impiden publicar
+func respondNotPublishable(c *gin.Context, diagnostics []lint.Diagnostic) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":       "Corrige los errores del roadmap antes de hacerlo público",
+		"diagnostics": diagnostics,
+	})
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"Gin/internal/graph"
+	"Gin/internal/merge"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errNotFork          = errors.New("el roadmap no es un fork")
+	errUpstreamNotFound = errors.New("roadmap original no encontrado")
+)
+
+// syncForkRequest contiene las resoluciones de los conflictos, indexadas por
+// el id del conflicto
+type syncForkRequest struct {
+	Resolutions map[string]merge.Choice `json:"resolutions"`
+}
+
+// PreviewForkSync muestra, sin aplicarlos, los cambios que se traerían del
+// roadmap original y los conflictos pendientes
+func (h *RoadmapHandler) PreviewForkSync(c *gin.Context) {
+	result, upstreamID, err := h.syncFork(c, h.store, nil)
+	h.respondSync(c, result, upstreamID, err)
+}
+
+// SyncFork fusiona en el fork los cambios del roadmap original. Los cambios
+// sin conflicto se aplican; los conflictos sin resolución se devuelven para
+// que el propietario los resuelva en una nueva llamada. Si los cambios
+// cerrarían un ciclo de requisitos en el fork no se aplica ninguno.
+func (h *RoadmapHandler) SyncFork(c *gin.Context) {
+	var req syncForkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+			return
+		}
+	}
+	for id, choice := range req.Resolutions {
+		if !choice.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Resolución inválida para " + id})
+			return
+		}
+	}
+
+	var result *merge.Result
+	var upstreamID models.ID
+	var cycle []models.ID
+	roadmapID := middleware.GetRoadmapID(c)
+	err := h.hub.Commit(roadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			var err error
+			result, upstreamID, err = h.syncFork(c, tx, req.Resolutions)
+			if err != nil {
+				return err
+			}
+			if cycle, err = mergeCycle(tx, roadmapID, result); err != nil {
+				return err
+			}
+
+			if err := applyMerge(tx, roadmapID, result, time.Now(), true); err != nil {
+				return err
+			}
+			if err := tx.Roadmaps().SaveForkBase(roadmapID, &result.Base); err != nil {
+				return err
+			}
+			revision := models.Revision{RoadmapID: roadmapID, Action: models.RevisionActionSync}
+			if err := recordRevision(tx, c, &revision); err != nil {
+				return err
+			}
+			if len(result.Changes) > 0 {
+				emit(realtime.EventGraphReplaced, gin.H{"action": models.RevisionActionSync, "revision": revision.Number})
+			}
+			return nil
+		})
+	})
+	if errors.Is(err, errRequirementCycle) {
+		respondMergeCycle(c, cycle)
+		return
+	}
+	h.respondSync(c, result, upstreamID, err)
+}
+
+// syncFork calcula la fusión del fork con su roadmap original
+func (h *RoadmapHandler) syncFork(c *gin.Context, store repository.Store, resolutions map[string]merge.Choice) (*merge.Result, models.ID, error) {
+	fork, err := store.Roadmaps().GetByID(middleware.GetRoadmapID(c))
+	if err != nil {
+		return nil, "", err
+	}
+	if fork.ForkedFrom == nil {
+		return nil, "", errNotFork
+	}
+
+	upstream, err := store.Roadmaps().GetByID(*fork.ForkedFrom)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !canViewRoadmap(c, store, upstream)) {
+		return nil, "", errUpstreamNotFound
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	// Los forks anteriores a las bases no tienen una; sin base común, toda
+	// diferencia entre ambos se trata como conflicto
+	base, err := store.Roadmaps().GetForkBase(fork.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		base = &models.Snapshot{}
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	upstreamContent, err := loadSnapshot(store, upstream.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	forkContent, err := loadSnapshot(store, fork.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := merge.Merge(*base, *upstreamContent, *forkContent, resolutions)
+	return &result, upstream.ID, nil
+}
+
+func (h *RoadmapHandler) respondSync(c *gin.Context, result *merge.Result, upstreamID models.ID, err error) {
+	switch {
+	case errors.Is(err, errNotFork):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El roadmap no es un fork"})
+	case errors.Is(err, errUpstreamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap original no encontrado"})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+	case errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "Los cambios del original chocan con el contenido del fork"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al sincronizar con el original"})
+	default:
+		c.JSON(http.StatusOK, gin.H{
+			"upstream_id": upstreamID,
+			"changes":     result.Changes,
+			"conflicts":   result.Conflicts,
+			"up_to_date":  len(result.Conflicts) == 0,
+		})
+	}
+}
+
+// mergeCycle devuelve errRequirementCycle junto con el primer ciclo de
+// requisitos que tendría el roadmap tras aplicar la fusión. Los nodos que la
+// fusión crea aparecen en el ciclo con su clave.
+func mergeCycle(tx repository.Store, roadmapID models.ID, result *merge.Result) ([]models.ID, error) {
+	current, err := loadSnapshot(tx, roadmapID)
+	if err != nil {
+		return nil, err
+	}
+	merged := result.Apply(*current)
+	if cycles := graph.New(merged.Nodes, merged.Connections).Cycles(); len(cycles) > 0 {
+		return cycles[0], errRequirementCycle
+	}
+	return nil, nil
+}
+
+// respondMergeCycle responde que los cambios de una fusión cierran un ciclo
+// de requisitos y no se aplicaron
+func respondMergeCycle(c *gin.Context, cycle []models.ID) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error": "Los cambios cierran un ciclo de requisitos",
+		"cycle": cycle,
+	})
+}
+
+// applyMerge aplica sobre el fork los cambios de una fusión, traduciendo las
+// claves de los nodos a sus ids en el fork. Con linkOrigin, los elementos
+// creados guardan su clave como origen; si no, conservan el que traigan.
+func applyMerge(tx repository.Store, forkID models.ID, result *merge.Result, now time.Time, linkOrigin bool) error {
+	nodeIDs := make(map[models.ID]models.ID, len(result.NodeIDs))
+	for key, id := range result.NodeIDs {
+		nodeIDs[key] = id
+	}
+
+	for _, change := range result.Changes {
+		var err error
+		switch change.Kind {
+		case merge.KindNode:
+			if change.Op == merge.OpDelete {
+				err = tx.Nodes().Delete(forkID, change.ID)
+				break
+			}
+			node := *change.Node
+			node.ID, node.RoadmapID, node.UpdatedAt = change.ID, forkID, now
+			if change.Op == merge.OpCreate {
+				node.CreatedAt = now
+				if linkOrigin {
+					node.OriginID = &change.Key
+				}
+				if err = tx.Nodes().Create(&node); err == nil {
+					nodeIDs[change.Key] = node.ID
+				}
+			} else {
+				err = tx.Nodes().Update(&node)
+			}
+
+		case merge.KindConnection:
+			if change.Op == merge.OpDelete {
+				err = tx.Connections().Delete(forkID, change.ID)
+				break
+			}
+			conn := *change.Connection
+			conn.ID, conn.RoadmapID, conn.UpdatedAt = change.ID, forkID, now
+			conn.FromNodeID, conn.ToNodeID = nodeIDs[conn.FromNodeID], nodeIDs[conn.ToNodeID]
+			if change.Op == merge.OpCreate {
+				conn.CreatedAt = now
+				if linkOrigin {
+					conn.OriginID = &change.Key
+				}
+				err = tx.Connections().Create(&conn)
+			} else {
+				err = tx.Connections().Update(&conn)
+			}
+
+		case merge.KindResource:
+			if change.Op == merge.OpDelete {
+				err = tx.Resources().Delete(change.ID)
+				break
+			}
+			resource := *change.Resource
+			resource.ID, resource.NodeID, resource.UpdatedAt = change.ID, nodeIDs[resource.NodeID], now
+			if change.Op == merge.OpCreate {
+				resource.CreatedAt = now
+				if linkOrigin {
+					resource.OriginID = &change.Key
+				}
+				err = tx.Resources().Create(&resource)
+			} else {
+				err = tx.Resources().Update(&resource)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"Gin/internal/graph"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetLearningPath devuelve los nodos que le faltan al usuario para llegar al
+// nodo indicado, en orden de estudio, con las horas estimadas en total. Los
+// nodos sin estimación cuentan como graph.DefaultEstimate horas.
+func (h *RoadmapHandler) GetLearningPath(c *gin.Context) {
+	roadmapID, ok := h.visibleRoadmapID(c)
+	if !ok {
+		return
+	}
+	targetID, ok := parseIDParam(c, "node_id", "ID de nodo inválido")
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	g, statuses, err := progressGraph(h.store, roadmapID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al calcular el camino"})
+		return
+	}
+	target, ok := g.Node(targetID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nodo no encontrado"})
+		return
+	}
+
+	path, err := g.PathTo(targetID, statuses)
+	if errors.Is(err, graph.ErrCycle) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Los requisitos del nodo forman un ciclo"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al calcular el camino"})
+		return
+	}
+
+	nodes := make([]models.NodeProgress, len(path))
+	hours := 0.0
+	unestimated := 0
+	for i, node := range path {
+		status, ok := statuses[node.ID]
+		if !ok {
+			status = models.ProgressNotStarted
+		}
+		nodes[i] = models.NodeProgress{Node: node, Progress: status}
+		hours += graph.Effort(node)
+		if node.Estimate == nil {
+			unestimated++
+		}
+	}
+
+	targetStatus, ok := statuses[targetID]
+	if !ok {
+		targetStatus = models.ProgressNotStarted
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"target":          models.NodeProgress{Node: target, Progress: targetStatus},
+		"nodes":           nodes,
+		"estimated_hours": hours,
+		"unestimated":     unestimated,
+	})
+}
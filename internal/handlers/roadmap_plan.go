@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"Gin/internal/graph"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/repository"
+	"Gin/views/layouts"
+	"Gin/views/pages"
+	"github.com/gin-gonic/gin"
+)
+
+// studyPlan ordena los nodos del roadmap en niveles de estudio junto con el
+// estado de cada uno para el usuario; sin usuario todos quedan sin empezar
+func studyPlan(store repository.Store, roadmapID models.ID, userID models.ID) ([][]models.NodeProgress, error) {
+	g, statuses, err := progressGraph(store, roadmapID, userID)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := g.Levels()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make([][]models.NodeProgress, len(levels))
+	for i, level := range levels {
+		plan[i] = make([]models.NodeProgress, len(level))
+		for j, node := range level {
+			status, ok := statuses[node.ID]
+			if !ok {
+				status = models.ProgressNotStarted
+			}
+			plan[i][j] = models.NodeProgress{Node: node, Progress: status}
+		}
+	}
+	return plan, nil
+}
+
+// GetStudyPlan devuelve el orden de estudio del roadmap: los niveles de nodos
+// que pueden estudiarse en paralelo y el orden completo
+func (h *RoadmapHandler) GetStudyPlan(c *gin.Context) {
+	roadmapID, ok := h.visibleRoadmapID(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	levels, err := studyPlan(h.store, roadmapID, userID)
+	if errors.Is(err, graph.ErrCycle) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Los requisitos del roadmap forman un ciclo"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al calcular el plan de estudio"})
+		return
+	}
+
+	order := []models.ID{}
+	for _, level := range levels {
+		for _, node := range level {
+			order = append(order, node.ID)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"levels": levels,
+		"order":  order,
+	})
+}
+
+// ViewStudyPlan muestra el plan de estudio del roadmap como una lista
+// imprimible
+func (h *RoadmapHandler) ViewStudyPlan(c *gin.Context) {
+	roadmapID, err := models.ParseID(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	roadmap, err := h.store.Roadmaps().GetByID(roadmapID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !canViewRoadmap(c, h.store, roadmap)) {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	userID, tracked := middleware.GetUserID(c)
+	levels, err := studyPlan(h.store, roadmapID, userID)
+	if errors.Is(err, graph.ErrCycle) {
+		c.Status(http.StatusConflict)
+		return
+	} else if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	props := models.StudyPlanProps{
+		ID:          roadmap.ID.String(),
+		Title:       roadmap.Title,
+		Description: roadmap.Description,
+		Levels:      make([][]models.StudyPlanStep, len(levels)),
+		Tracked:     tracked,
+	}
+	number := 0
+	for i, level := range levels {
+		for _, node := range level {
+			number++
+			props.Levels[i] = append(props.Levels[i], models.StudyPlanStep{
+				Number:      number,
+				Title:       node.Title,
+				Description: node.Description,
+				Type:        string(node.Type),
+				Status:      string(node.Progress),
+			})
+		}
+	}
+
+	component := layouts.Base(roadmap.Title, pages.StudyPlan(props))
+	component.Render(c.Request.Context(), c.Writer)
+}
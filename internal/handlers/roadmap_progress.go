@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"Gin/internal/graph"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// progressSummary calcula el progreso del usuario en el roadmap. Los nodos
+// sin progreso guardado cuentan como no empezados.
+func progressSummary(store repository.Store, roadmapID, userID models.ID) (*models.ProgressSummary, error) {
+	nodes, err := store.Nodes().ListByRoadmap(roadmapID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := store.Progress().ListByRoadmap(userID, roadmapID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.ProgressSummary{
+		RoadmapID:  roadmapID,
+		TotalNodes: len(nodes),
+		NotStarted: len(nodes),
+		Nodes:      progress,
+	}
+	for _, p := range progress {
+		switch p.Status {
+		case models.ProgressCompleted:
+			summary.Completed++
+			summary.NotStarted--
+		case models.ProgressInProgress:
+			summary.InProgress++
+			summary.NotStarted--
+		}
+		if summary.LastActivityAt == nil || p.UpdatedAt.After(*summary.LastActivityAt) {
+			updatedAt := p.UpdatedAt
+			summary.LastActivityAt = &updatedAt
+		}
+	}
+	summary.Completion = percentage(summary.Completed, summary.TotalNodes)
+	return summary, nil
+}
+
+// nodeStatuses devuelve el estado de cada nodo según el progreso guardado
+func nodeStatuses(progress []models.Progress) map[models.ID]models.ProgressStatus {
+	statuses := make(map[models.ID]models.ProgressStatus, len(progress))
+	for _, p := range progress {
+		statuses[p.NodeID] = p.Status
+	}
+	return statuses
+}
+
+// progressGraph construye el grafo del roadmap junto con el estado del usuario
+// en cada nodo
+func progressGraph(store repository.Store, roadmapID, userID models.ID) (*graph.Graph, map[models.ID]models.ProgressStatus, error) {
+	nodes, err := store.Nodes().ListByRoadmap(roadmapID)
+	if err != nil {
+		return nil, nil, err
+	}
+	connections, err := store.Connections().ListByRoadmap(roadmapID)
+	if err != nil {
+		return nil, nil, err
+	}
+	progress, err := store.Progress().ListByRoadmap(userID, roadmapID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return graph.New(nodes, connections), nodeStatuses(progress), nil
+}
+
+// visibleRoadmapID lee el roadmap de la ruta. Cualquier usuario que pueda ver
+// el roadmap sigue su propio progreso, aunque no sea colaborador; si no puede
+// verlo responde 404 y devuelve false.
+func (h *RoadmapHandler) visibleRoadmapID(c *gin.Context) (models.ID, bool) {
+	roadmapID, ok := parseIDParam(c, "id", "ID de roadmap inválido")
+	if !ok {
+		return "", false
+	}
+
+	roadmap, err := h.store.Roadmaps().GetByID(roadmapID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !canViewRoadmap(c, h.store, roadmap)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+		return "", false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return "", false
+	}
+	return roadmapID, true
+}
+
+// GetProgress devuelve el progreso del usuario autenticado en el roadmap
+func (h *RoadmapHandler) GetProgress(c *gin.Context) {
+	roadmapID, ok := h.visibleRoadmapID(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	summary, err := progressSummary(h.store, roadmapID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetUnlockedNodes devuelve los nodos que el usuario autenticado puede
+// empezar: los que no ha completado y cuyos requisitos ya completó
+func (h *RoadmapHandler) GetUnlockedNodes(c *gin.Context) {
+	roadmapID, ok := h.visibleRoadmapID(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	g, statuses, err := progressGraph(h.store, roadmapID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso"})
+		return
+	}
+
+	nodes := []models.NodeProgress{}
+	for _, node := range g.Unlocked(statuses) {
+		status, ok := statuses[node.ID]
+		if !ok {
+			status = models.ProgressNotStarted
+		}
+		nodes = append(nodes, models.NodeProgress{Node: node, Progress: status})
+	}
+	c.JSON(http.StatusOK, gin.H{"nodes": nodes})
+}
+
+// UpdateProgress actualiza el progreso del usuario autenticado en un nodo del
+// roadmap. Si no se indican notas se conservan las guardadas.
+func (h *RoadmapHandler) UpdateProgress(c *gin.Context) {
+	type updateProgressRequest struct {
+		Status models.ProgressStatus `json:"status" binding:"required"`
+		Notes  *string               `json:"notes"`
+	}
+
+	var req updateProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+	if !req.Status.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Estado de progreso inválido"})
+		return
+	}
+
+	h.saveProgress(c, req.Status, req.Notes)
+}
+
+// CompleteNode marca un nodo del roadmap como completado por el usuario
+// autenticado
+func (h *RoadmapHandler) CompleteNode(c *gin.Context) {
+	h.saveProgress(c, models.ProgressCompleted, nil)
+}
+
+// saveProgress guarda el estado del usuario autenticado en el nodo de la ruta.
+// Las conexiones strong son requisitos: no se puede empezar ni completar un
+// nodo hasta completar los nodos de los que parten. La fecha de finalización
+// se fija al completar el nodo, se conserva mientras siga completado y se
+// borra si vuelve a otro estado.
+func (h *RoadmapHandler) saveProgress(c *gin.Context, status models.ProgressStatus, notes *string) {
+	roadmapID, ok := h.visibleRoadmapID(c)
+	if !ok {
+		return
+	}
+	nodeID, ok := parseIDParam(c, "node_id", "ID de nodo inválido")
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	g, statuses, err := progressGraph(h.store, roadmapID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso"})
+		return
+	}
+	if !g.Has(nodeID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nodo no encontrado"})
+		return
+	}
+	if status != models.ProgressNotStarted {
+		if missing := g.Missing(nodeID, statuses); len(missing) > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Debes completar antes los requisitos del nodo",
+				"missing": missing,
+			})
+			return
+		}
+	}
+
+	progress, err := h.store.Progress().Get(userID, nodeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		progress = &models.Progress{UserID: userID, NodeID: nodeID}
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso"})
+		return
+	}
+
+	now := time.Now()
+	switch {
+	case status != models.ProgressCompleted:
+		progress.CompletedAt = nil
+	case progress.Status != models.ProgressCompleted || progress.CompletedAt == nil:
+		progress.CompletedAt = &now
+	}
+	progress.Status = status
+	if notes != nil {
+		progress.Notes = *notes
+	}
+	progress.UpdatedAt = now
+
+	if err := h.store.Progress().Upsert(progress); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al guardar el progreso"})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"Gin/internal/lint"
+	"Gin/internal/merge"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+var errRestoreConflicts = errors.New("la restauración tiene conflictos")
+
+// recordRevision guarda el contenido y los datos generales actuales del
+// roadmap como una nueva revisión con la acción indicada y el usuario
+// autenticado como autor, y avanza la versión del roadmap. Si nada cambió
+// desde la última revisión no se crea ninguna y revision pasa a ser la última.
+func recordRevision(tx repository.Store, c *gin.Context, revision *models.Revision) error {
+	roadmap, err := tx.Roadmaps().GetByID(revision.RoadmapID)
+	if err != nil {
+		return err
+	}
+	details := revisionDetails(roadmap)
+	snapshot, err := loadSnapshot(tx, revision.RoadmapID)
+	if err != nil {
+		return err
+	}
+
+	latest, err := tx.Revisions().GetLatest(revision.RoadmapID)
+	if err == nil && latest.Details != nil && *latest.Details == details && len(merge.Diff(latest.Snapshot, *snapshot)) == 0 {
+		*revision = *latest
+		return nil
+	} else if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	switch revision.Action {
+	case models.RevisionActionCreate, models.RevisionActionFork, models.RevisionActionImport:
+		// Un roadmap recién creado, copiado o importado conserva su primera versión
+	case models.RevisionActionRoadmapUpdate:
+		// Roadmaps().Update ya avanzó la versión
+	default:
+		if _, err := tx.Roadmaps().Touch(revision.RoadmapID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	revision.Details = &details
+	revision.Snapshot = *snapshot
+	if userID, ok := middleware.GetUserID(c); ok {
+		revision.AuthorID = &userID
+	}
+	return tx.Revisions().Create(revision)
+}
+
+// revisionDetails devuelve los datos generales del roadmap que se guardan en
+// cada revisión
+func revisionDetails(roadmap *models.Roadmap) models.RevisionDetails {
+	return models.RevisionDetails{
+		Title:       roadmap.Title,
+		Description: roadmap.Description,
+		Category:    roadmap.Category,
+		IsPublic:    roadmap.IsPublic,
+	}
+}
+
+// ListRevisions devuelve el historial de revisiones del roadmap
+func (h *RoadmapHandler) ListRevisions(c *gin.Context) {
+	revisions, err := h.store.Revisions().ListByRoadmap(middleware.GetRoadmapID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las revisiones"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// GetRevision devuelve una revisión con el contenido del roadmap en ese momento
+func (h *RoadmapHandler) GetRevision(c *gin.Context) {
+	number, ok := parseRevisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+
+	revision, err := h.store.Revisions().GetByNumber(middleware.GetRoadmapID(c), number)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revisión no encontrada"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener la revisión"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revision": revision, "content": revision.Snapshot})
+}
+
+// DiffRevisions compara dos revisiones (?from=N&to=M). Sin to, compara con
+// el contenido actual del roadmap. Los cambios en los datos generales aparecen
+// primero como una modificación de tipo roadmap, salvo que alguna de las
+// revisiones sea anterior a guardarlos.
+func (h *RoadmapHandler) DiffRevisions(c *gin.Context) {
+	roadmapID := middleware.GetRoadmapID(c)
+
+	from, ok := parseRevisionNumber(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	fromRevision, err := h.store.Revisions().GetByNumber(roadmapID, from)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revisión no encontrada"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener la revisión"})
+		return
+	}
+
+	var to *models.Snapshot
+	var toDetails *models.RevisionDetails
+	if c.Query("to") == "" {
+		roadmap, err := h.store.Roadmaps().GetByID(roadmapID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+			return
+		}
+		details := revisionDetails(roadmap)
+		toDetails = &details
+		if to, err = loadSnapshot(h.store, roadmapID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+			return
+		}
+	} else {
+		number, ok := parseRevisionNumber(c, c.Query("to"))
+		if !ok {
+			return
+		}
+		toRevision, err := h.store.Revisions().GetByNumber(roadmapID, number)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Revisión no encontrada"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener la revisión"})
+			return
+		}
+		to, toDetails = &toRevision.Snapshot, toRevision.Details
+	}
+
+	changes := []merge.Difference{}
+	if fromRevision.Details != nil && toDetails != nil {
+		changes = merge.DiffDetails(roadmapID, *fromRevision.Details, *toDetails)
+	}
+	changes = append(changes, merge.Diff(fromRevision.Snapshot, *to)...)
+	c.JSON(http.StatusOK, gin.H{"changes": changes})
+}
+
+// RestoreRevision devuelve el contenido y los datos generales del roadmap a
+// los de una revisión anterior. Los elementos eliminados desde entonces se
+// vuelven a crear con ids nuevos. La visibilidad solo se restaura si el
+// usuario puede cambiarla y, para volver a hacerlo público, el contenido no
+// puede tener errores. La restauración queda registrada como una nueva
+// revisión, de modo que también se puede deshacer.
+func (h *RoadmapHandler) RestoreRevision(c *gin.Context) {
+	number, ok := parseRevisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+
+	roadmapID := middleware.GetRoadmapID(c)
+	revision := models.Revision{RoadmapID: roadmapID, Action: models.RevisionActionRestore, RestoredFrom: &number}
+
+	var result merge.Result
+	var blockers []lint.Diagnostic
+	err := h.hub.Commit(roadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			target, err := tx.Revisions().GetByNumber(roadmapID, number)
+			if err != nil {
+				return err
+			}
+			current, err := currentContent(tx, roadmapID)
+			if err != nil {
+				return err
+			}
+
+			// Fusionar el contenido de la revisión sobre el actual tomando este
+			// como base produce exactamente los cambios que los separan
+			result = merge.Merge(*current, target.Snapshot, *current, nil)
+			if len(result.Conflicts) > 0 {
+				return errRestoreConflicts
+			}
+			if err := applyMerge(tx, roadmapID, &result, time.Now(), false); err != nil {
+				return err
+			}
+			if target.Details != nil {
+				if blockers, err = restoreDetails(tx, c, roadmapID, *target.Details); err != nil {
+					return err
+				}
+			}
+			if err := recordRevision(tx, c, &revision); err != nil {
+				return err
+			}
+			if len(result.Changes) > 0 {
+				emit(realtime.EventGraphReplaced, gin.H{"action": models.RevisionActionRestore, "revision": revision.Number})
+			}
+			return nil
+		})
+	})
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revisión no encontrada"})
+	case errors.Is(err, errRestoreConflicts):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     "No se pudo restaurar la revisión automáticamente",
+			"conflicts": result.Conflicts,
+		})
+	case errors.Is(err, errNotPublishable):
+		respondNotPublishable(c, blockers)
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al restaurar la revisión"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"revision": revision, "applied": result.Changes})
+	}
+}
+
+// restoreDetails devuelve los datos generales del roadmap a los de una
+// revisión. Si la restauración lo haría público y el contenido tiene errores
+// devuelve errNotPublishable junto con los diagnósticos.
+func restoreDetails(tx repository.Store, c *gin.Context, roadmapID models.ID, details models.RevisionDetails) ([]lint.Diagnostic, error) {
+	roadmap, err := tx.Roadmaps().GetByID(roadmapID)
+	if err != nil {
+		return nil, err
+	}
+	if !canChangeVisibility(c) {
+		details.IsPublic = roadmap.IsPublic
+	}
+	if details == revisionDetails(roadmap) {
+		return nil, nil
+	}
+
+	if details.IsPublic && !roadmap.IsPublic {
+		blockers, err := publishBlockers(tx, roadmapID)
+		if err != nil {
+			return nil, err
+		}
+		if blockers != nil {
+			return blockers, errNotPublishable
+		}
+	}
+
+	roadmap.Title, roadmap.Description = details.Title, details.Description
+	roadmap.Category, roadmap.IsPublic = details.Category, details.IsPublic
+	roadmap.UpdatedAt = time.Now()
+	return nil, tx.Roadmaps().Update(roadmap)
+}
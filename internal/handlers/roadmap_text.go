@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"Gin/internal/dsl"
+	"Gin/internal/merge"
+	"Gin/internal/middleware"
+	"Gin/internal/models"
+	"Gin/internal/realtime"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// GetRoadmapText devuelve el contenido del roadmap escrito en el lenguaje de
+// texto del paquete dsl, con la versión del roadmap en el ETag
+func (h *RoadmapHandler) GetRoadmapText(c *gin.Context) {
+	roadmapID := middleware.GetRoadmapID(c)
+	roadmap, err := h.store.Roadmaps().GetByID(roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+	snapshot, err := loadSnapshot(h.store, roadmapID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el roadmap"})
+		return
+	}
+
+	setETag(c, roadmap.Version)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(dsl.Print(*snapshot)))
+}
+
+// ApplyRoadmapText sustituye el contenido del roadmap por el del texto
+// recibido. Los nodos cuyo título ya existe se actualizan sin perder su ID,
+// posición ni el progreso de los usuarios; los que faltan en el texto se
+// eliminan. Con If-Match, solo se aplica si el roadmap sigue en la versión
+// indicada.
+func (h *RoadmapHandler) ApplyRoadmapText(c *gin.Context) {
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	text, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer el texto"})
+		return
+	}
+	parsed, problems := dsl.Parse(string(text))
+	if len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    "El texto no es válido",
+			"problems": problems,
+		})
+		return
+	}
+
+	roadmapID := middleware.GetRoadmapID(c)
+	revision := models.Revision{RoadmapID: roadmapID, Action: models.RevisionActionTextEdit}
+
+	var result merge.Result
+	err = h.hub.Commit(roadmapID, liveOrigin(c), func(emit func(realtime.EventType, any)) error {
+		return h.store.Transaction(func(tx repository.Store) error {
+			version, err := tx.Roadmaps().Lock(roadmapID)
+			if err != nil {
+				return err
+			}
+			if expected != 0 && version != expected {
+				return repository.ErrVersionConflict
+			}
+			current, err := currentContent(tx, roadmapID)
+			if err != nil {
+				return err
+			}
+
+			// Con el contenido actual como base la fusión produce exactamente
+			// los cambios que pide el texto
+			result = merge.Merge(*current, parsed.Apply(*current), *current, nil)
+			if err := applyMerge(tx, roadmapID, &result, time.Now(), false); err != nil {
+				return err
+			}
+			if err := recordRevision(tx, c, &revision); err != nil {
+				return err
+			}
+			if len(result.Changes) > 0 {
+				emit(realtime.EventGraphReplaced, gin.H{"action": models.RevisionActionTextEdit, "revision": revision.Number})
+			}
+			return nil
+		})
+	})
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
+	case errors.Is(err, repository.ErrVersionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "El roadmap ha cambiado desde que se obtuvo el texto"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al aplicar el texto"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"revision": revision, "applied": result.Changes})
+	}
+}
@@ -0,0 +1,263 @@
+package interchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"Gin/internal/graph"
+	"Gin/internal/models"
+)
+
+// Error es un problema del documento en el campo indicado por Path, una ruta
+// JSON como $.nodes[2].type
+type Error struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Decode lee un documento. Si el JSON está mal formado o algún campo tiene un
+// tipo incorrecto devuelve el error con la ruta del campo.
+func Decode(r io.Reader) (*Document, []Error) {
+	var doc Document
+	err := json.NewDecoder(r).Decode(&doc)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == nil:
+		return &doc, nil
+	case errors.As(err, &syntaxErr):
+		return nil, []Error{{"$", fmt.Sprintf("JSON mal formado en el byte %d", syntaxErr.Offset)}}
+	case errors.As(err, &typeErr):
+		return nil, []Error{{fieldPath(typeErr.Field), "Se esperaba " + kindName(typeErr.Type)}}
+	case errors.Is(err, io.EOF):
+		return nil, []Error{{"$", "El documento está vacío"}}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return nil, []Error{{"$", "El documento está incompleto"}}
+	}
+	return nil, []Error{{"$", "No se pudo leer el documento"}}
+}
+
+// fieldPath convierte la ruta de encoding/json, como nodes.0.position.x, en
+// una ruta JSON como $.nodes[0].position.x
+func fieldPath(field string) string {
+	path := "$"
+	for _, part := range strings.Split(field, ".") {
+		if part == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(part); err == nil {
+			path += "[" + part + "]"
+		} else {
+			path += "." + part
+		}
+	}
+	return path
+}
+
+// kindName describe el tipo de valor JSON que corresponde a un tipo de Go
+func kindName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "un texto"
+	case reflect.Bool:
+		return "un booleano"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "un número entero"
+	case reflect.Float32, reflect.Float64:
+		return "un número"
+	case reflect.Slice, reflect.Array:
+		return "una lista"
+	}
+	return "un objeto"
+}
+
+// validator acumula los problemas encontrados al revisar un documento
+type validator struct {
+	errors []Error
+}
+
+func (v *validator) add(path, format string, args ...any) {
+	v.errors = append(v.errors, Error{path, fmt.Sprintf(format, args...)})
+}
+
+// text revisa un texto opcional con longitud máxima
+func (v *validator) text(path, value string, limit int) {
+	if utf8.RuneCountInString(value) > limit {
+		v.add(path, "Supera los %d caracteres", limit)
+	}
+}
+
+// required revisa un texto obligatorio con longitud máxima
+func (v *validator) required(path, value string, limit int) {
+	if strings.TrimSpace(value) == "" {
+		v.add(path, "Es obligatorio")
+		return
+	}
+	v.text(path, value, limit)
+}
+
+// Validate revisa el documento y devuelve todos los problemas encontrados.
+// Un documento sin problemas puede importarse con Snapshot.
+func (doc *Document) Validate() []Error {
+	v := &validator{}
+	if doc.Format != FormatName {
+		v.add("$.format", "Formato desconocido: se esperaba %q", FormatName)
+	}
+	if doc.Version != Version {
+		v.add("$.version", "Versión no soportada: %d", doc.Version)
+		return v.errors
+	}
+
+	v.required("$.roadmap.title", doc.Roadmap.Title, 255)
+	v.text("$.roadmap.category", doc.Roadmap.Category, 50)
+
+	keys := make(map[string]bool, len(doc.Nodes))
+	for i, node := range doc.Nodes {
+		path := fmt.Sprintf("$.nodes[%d]", i)
+		switch {
+		case node.ID == "":
+			v.add(path+".id", "Es obligatorio")
+		case keys[node.ID]:
+			v.add(path+".id", "Clave de nodo duplicada: %s", node.ID)
+		}
+		keys[node.ID] = true
+
+		v.required(path+".title", node.Title, 255)
+		if node.Type != "" && !node.Type.Valid() {
+			v.add(path+".type", "Tipo de nodo inválido: %s", node.Type)
+		}
+		v.text(path+".status", node.Status, 20)
+		v.text(path+".color", node.Color, 7)
+		if node.Estimate != nil && *node.Estimate <= 0 {
+			v.add(path+".estimated_hours", "Debe ser mayor que cero")
+		}
+	}
+
+	pairs := make(map[[2]string]bool, len(doc.Connections))
+	for i, conn := range doc.Connections {
+		path := fmt.Sprintf("$.connections[%d]", i)
+		if !keys[conn.From] {
+			v.add(path+".from", "Nodo desconocido: %s", conn.From)
+		}
+		if !keys[conn.To] {
+			v.add(path+".to", "Nodo desconocido: %s", conn.To)
+		}
+		switch {
+		case conn.From == conn.To:
+			v.add(path, "Un nodo no puede conectarse consigo mismo")
+		case pairs[[2]string{conn.From, conn.To}]:
+			v.add(path, "Conexión duplicada de %s a %s", conn.From, conn.To)
+		}
+		pairs[[2]string{conn.From, conn.To}] = true
+
+		if conn.Type != "" && !conn.Type.Valid() {
+			v.add(path+".type", "Tipo de conexión inválido: %s", conn.Type)
+		}
+		v.text(path+".label", conn.Label, 255)
+	}
+
+	for i, resource := range doc.Resources {
+		path := fmt.Sprintf("$.resources[%d]", i)
+		if !keys[resource.Node] {
+			v.add(path+".node", "Nodo desconocido: %s", resource.Node)
+		}
+		v.required(path+".title", resource.Title, 255)
+		v.text(path+".type", resource.Type, 50)
+		if strings.TrimSpace(resource.URL) == "" {
+			v.add(path+".url", "Es obligatorio")
+		}
+	}
+
+	// Los ciclos solo se buscan en documentos sin otros problemas
+	if len(v.errors) == 0 {
+		snapshot, keyOf := doc.snapshot()
+		for _, cycle := range graph.New(snapshot.Nodes, snapshot.Connections).Cycles() {
+			names := make([]string, len(cycle))
+			for i, id := range cycle {
+				names[i] = keyOf[id]
+			}
+			v.add("$.connections", "Las conexiones forman un ciclo de requisitos: %s", strings.Join(names, " → "))
+		}
+	}
+	return v.errors
+}
+
+// Snapshot devuelve el contenido del documento con IDs nuevos para los nodos.
+// Los tipos y estados vacíos toman su valor por defecto. El documento debe ser
+// válido.
+func (doc *Document) Snapshot() models.Snapshot {
+	snapshot, _ := doc.snapshot()
+	return snapshot
+}
+
+// snapshot construye el contenido del documento y devuelve también la clave
+// de cada nodo por su ID nuevo
+func (doc *Document) snapshot() (models.Snapshot, map[models.ID]string) {
+	ids := make(map[string]models.ID, len(doc.Nodes))
+	keyOf := make(map[models.ID]string, len(doc.Nodes))
+	snapshot := models.Snapshot{
+		Nodes:       make([]models.Node, 0, len(doc.Nodes)),
+		Connections: make([]models.Connection, 0, len(doc.Connections)),
+		Resources:   make([]models.Resource, 0, len(doc.Resources)),
+	}
+
+	for _, n := range doc.Nodes {
+		node := models.Node{
+			ID:          models.NewID(),
+			Title:       n.Title,
+			Description: n.Description,
+			Type:        n.Type,
+			Position:    n.Position,
+			Status:      n.Status,
+			Color:       n.Color,
+			OrderIndex:  n.OrderIndex,
+			Estimate:    n.Estimate,
+		}
+		if node.Type == "" {
+			node.Type = models.NodeTypeTopic
+		}
+		if node.Status == "" {
+			node.Status = "not_started"
+		}
+		ids[n.ID] = node.ID
+		keyOf[node.ID] = n.ID
+		snapshot.Nodes = append(snapshot.Nodes, node)
+	}
+
+	for _, c := range doc.Connections {
+		conn := models.Connection{
+			ID:             models.NewID(),
+			FromNodeID:     ids[c.From],
+			ToNodeID:       ids[c.To],
+			Label:          c.Label,
+			ConnectionType: c.Type,
+		}
+		if conn.ConnectionType == "" {
+			conn.ConnectionType = models.ConnectionTypeDefault
+		}
+		snapshot.Connections = append(snapshot.Connections, conn)
+	}
+
+	for _, r := range doc.Resources {
+		resource := models.Resource{
+			ID:          models.NewID(),
+			NodeID:      ids[r.Node],
+			Title:       r.Title,
+			Type:        r.Type,
+			URL:         r.URL,
+			Description: r.Description,
+		}
+		if resource.Type == "" {
+			resource.Type = "link"
+		}
+		snapshot.Resources = append(snapshot.Resources, resource)
+	}
+	return snapshot, keyOf
+}
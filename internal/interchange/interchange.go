@@ -0,0 +1,157 @@
+// Package interchange define el formato JSON para mover roadmaps entre
+// entornos y hacer copias de seguridad. El documento contiene todo el
+// contenido editable del roadmap y nada propio de una instalación: ni autor,
+// ni organización, ni progreso de los usuarios.
+//
+// Versión 1 del formato, con los campos comentados:
+//
+//	{
+//	  "format": "roadmap",
+//	  "version": 1,
+//	  "exported_at": "2024-01-01T00:00:00Z",
+//	  "roadmap": {
+//	    "title": "Go",                 // obligatorio, hasta 255 caracteres
+//	    "description": "...",
+//	    "category": "backend",         // hasta 50 caracteres
+//	    "is_public": false
+//	  },
+//	  "nodes": [{
+//	    "id": "n1",                    // clave única dentro del documento
+//	    "title": "Sintaxis",           // obligatorio, hasta 255 caracteres
+//	    "description": "...",
+//	    "type": "topic",               // topic, resource, challenge o milestone
+//	    "position": {"x": 40, "y": 40},
+//	    "status": "not_started",       // hasta 20 caracteres
+//	    "color": "#3b82f6",            // hasta 7 caracteres
+//	    "order_index": 1,              // opcional
+//	    "estimated_hours": 2.5         // opcional, mayor que cero
+//	  }],
+//	  "connections": [{
+//	    "from": "n1",                  // claves de nodos del documento
+//	    "to": "n2",
+//	    "type": "strong",              // default, strong, weak o dashed
+//	    "label": "..."                 // hasta 255 caracteres
+//	  }],
+//	  "resources": [{
+//	    "node": "n1",
+//	    "title": "Tour de Go",         // obligatorio, hasta 255 caracteres
+//	    "type": "link",                // hasta 50 caracteres
+//	    "url": "https://go.dev/tour",
+//	    "description": "..."
+//	  }]
+//	}
+//
+// Al exportar, las claves de los nodos son sus IDs. Al importar se generan IDs
+// nuevos, así que el mismo documento puede importarse varias veces.
+package interchange
+
+import (
+	"time"
+
+	"Gin/internal/models"
+)
+
+const (
+	// FormatName identifica los documentos de este formato
+	FormatName = "roadmap"
+	// Version es la versión del formato que se genera y la única que se acepta
+	Version = 1
+)
+
+// Document es un roadmap en el formato de intercambio
+type Document struct {
+	Format      string       `json:"format"`
+	Version     int          `json:"version"`
+	ExportedAt  *time.Time   `json:"exported_at,omitempty"`
+	Roadmap     Roadmap      `json:"roadmap"`
+	Nodes       []Node       `json:"nodes"`
+	Connections []Connection `json:"connections"`
+	Resources   []Resource   `json:"resources"`
+}
+
+// Roadmap son los datos generales del roadmap
+type Roadmap struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+// Node es un nodo identificado por su clave en el documento
+type Node struct {
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Type        models.NodeType `json:"type"`
+	Position    models.Position `json:"position"`
+	Status      string          `json:"status"`
+	Color       string          `json:"color"`
+	OrderIndex  *int            `json:"order_index,omitempty"`
+	Estimate    *float64        `json:"estimated_hours,omitempty"`
+}
+
+// Connection une dos nodos por sus claves
+type Connection struct {
+	From  string                `json:"from"`
+	To    string                `json:"to"`
+	Type  models.ConnectionType `json:"type"`
+	Label string                `json:"label,omitempty"`
+}
+
+// Resource es un recurso del nodo con la clave indicada
+type Resource struct {
+	Node        string `json:"node"`
+	Title       string `json:"title"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Export genera el documento de un roadmap
+func Export(roadmap models.Roadmap, snapshot models.Snapshot, exportedAt time.Time) Document {
+	doc := Document{
+		Format:     FormatName,
+		Version:    Version,
+		ExportedAt: &exportedAt,
+		Roadmap: Roadmap{
+			Title:       roadmap.Title,
+			Description: roadmap.Description,
+			Category:    roadmap.Category,
+			IsPublic:    roadmap.IsPublic,
+		},
+		Nodes:       make([]Node, 0, len(snapshot.Nodes)),
+		Connections: make([]Connection, 0, len(snapshot.Connections)),
+		Resources:   make([]Resource, 0, len(snapshot.Resources)),
+	}
+	for _, node := range snapshot.Nodes {
+		doc.Nodes = append(doc.Nodes, Node{
+			ID:          node.ID.String(),
+			Title:       node.Title,
+			Description: node.Description,
+			Type:        node.Type,
+			Position:    node.Position,
+			Status:      node.Status,
+			Color:       node.Color,
+			OrderIndex:  node.OrderIndex,
+			Estimate:    node.Estimate,
+		})
+	}
+	for _, conn := range snapshot.Connections {
+		doc.Connections = append(doc.Connections, Connection{
+			From:  conn.FromNodeID.String(),
+			To:    conn.ToNodeID.String(),
+			Type:  conn.ConnectionType,
+			Label: conn.Label,
+		})
+	}
+	for _, resource := range snapshot.Resources {
+		doc.Resources = append(doc.Resources, Resource{
+			Node:        resource.NodeID.String(),
+			Title:       resource.Title,
+			Type:        resource.Type,
+			URL:         resource.URL,
+			Description: resource.Description,
+		})
+	}
+	return doc
+}
@@ -0,0 +1,172 @@
+package interchange
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"Gin/internal/models"
+)
+
+// sample devuelve un roadmap con dos nodos conectados y un recurso
+func sample() (models.Roadmap, models.Snapshot) {
+	hours := 2.5
+	order := 1
+	a := models.Node{ID: models.NewID(), Title: "Sintaxis", Type: models.NodeTypeTopic, Status: "not_started",
+		Color: "#3b82f6", Position: models.Position{X: 40, Y: 40}, Estimate: &hours, OrderIndex: &order}
+	b := models.Node{ID: models.NewID(), Title: "Concurrencia", Type: models.NodeTypeMilestone, Status: "not_started",
+		Position: models.Position{X: 40, Y: 200}}
+	roadmap := models.Roadmap{Title: "Go", Description: "Aprender Go", Category: "backend", IsPublic: true}
+	return roadmap, models.Snapshot{
+		Nodes: []models.Node{a, b},
+		Connections: []models.Connection{
+			{ID: models.NewID(), FromNodeID: a.ID, ToNodeID: b.ID, ConnectionType: models.ConnectionTypeStrong, Label: "antes"},
+		},
+		Resources: []models.Resource{
+			{ID: models.NewID(), NodeID: a.ID, Title: "Tour de Go", Type: "link", URL: "https://go.dev/tour"},
+		},
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	roadmap, snapshot := sample()
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(Export(roadmap, snapshot, time.Now().UTC())); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, problems := Decode(&buf)
+	if problems != nil {
+		t.Fatalf("Decode: %v", problems)
+	}
+	if problems := doc.Validate(); problems != nil {
+		t.Fatalf("Validate: %v", problems)
+	}
+	if doc.Roadmap.Title != roadmap.Title || doc.Roadmap.Category != roadmap.Category || !doc.Roadmap.IsPublic {
+		t.Errorf("roadmap = %+v", doc.Roadmap)
+	}
+
+	imported := doc.Snapshot()
+	if len(imported.Nodes) != 2 || len(imported.Connections) != 1 || len(imported.Resources) != 1 {
+		t.Fatalf("contenido importado = %+v", imported)
+	}
+	byTitle := map[string]models.Node{}
+	for i, node := range imported.Nodes {
+		if node.ID == snapshot.Nodes[i].ID {
+			t.Errorf("el nodo %s conserva su ID", node.Title)
+		}
+		original := snapshot.Nodes[i]
+		if node.Title != original.Title || node.Type != original.Type || node.Color != original.Color ||
+			node.Position != original.Position {
+			t.Errorf("nodo %d = %+v, se esperaba %+v", i, node, original)
+		}
+		byTitle[node.Title] = node
+	}
+	if estimate := byTitle["Sintaxis"].Estimate; estimate == nil || *estimate != 2.5 {
+		t.Errorf("horas estimadas = %v", estimate)
+	}
+	conn := imported.Connections[0]
+	if conn.FromNodeID != byTitle["Sintaxis"].ID || conn.ToNodeID != byTitle["Concurrencia"].ID ||
+		conn.ConnectionType != models.ConnectionTypeStrong || conn.Label != "antes" {
+		t.Errorf("conexión = %+v", conn)
+	}
+	if resource := imported.Resources[0]; resource.NodeID != byTitle["Sintaxis"].ID || resource.URL != "https://go.dev/tour" {
+		t.Errorf("recurso = %+v", resource)
+	}
+}
+
+func TestSnapshotDefaults(t *testing.T) {
+	doc := Document{
+		Format: FormatName, Version: Version,
+		Roadmap:     Roadmap{Title: "Go"},
+		Nodes:       []Node{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}},
+		Connections: []Connection{{From: "a", To: "b"}},
+		Resources:   []Resource{{Node: "a", Title: "Docs", URL: "https://go.dev"}},
+	}
+	snapshot := doc.Snapshot()
+	if node := snapshot.Nodes[0]; node.Type != models.NodeTypeTopic || node.Status != "not_started" {
+		t.Errorf("nodo = %+v", node)
+	}
+	if conn := snapshot.Connections[0]; conn.ConnectionType != models.ConnectionTypeDefault {
+		t.Errorf("tipo de conexión = %q", conn.ConnectionType)
+	}
+	if resource := snapshot.Resources[0]; resource.Type != "link" {
+		t.Errorf("tipo de recurso = %q", resource.Type)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		path  string
+	}{
+		{"vacío", "", "$"},
+		{"incompleto", `{"format": "roadmap"`, "$"},
+		{"mal formado", `{"format": }`, "$"},
+		{"tipo incorrecto", `{"nodes": [{"position": {"x": "a"}}]}`, "$.nodes[0].position.x"},
+		{"lista esperada", `{"connections": {}}`, "$.connections"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, problems := Decode(strings.NewReader(tt.input))
+			if doc != nil || len(problems) != 1 {
+				t.Fatalf("Decode = %v, %v", doc, problems)
+			}
+			if problems[0].Path != tt.path {
+				t.Errorf("ruta = %q, se esperaba %q", problems[0].Path, tt.path)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Document {
+		return Document{
+			Format: FormatName, Version: Version,
+			Roadmap:     Roadmap{Title: "Go"},
+			Nodes:       []Node{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}},
+			Connections: []Connection{{From: "a", To: "b"}},
+			Resources:   []Resource{{Node: "a", Title: "Docs", URL: "https://go.dev"}},
+		}
+	}
+	tests := []struct {
+		name   string
+		change func(*Document)
+		paths  []string
+	}{
+		{"válido", func(*Document) {}, nil},
+		{"formato", func(d *Document) { d.Format = "otro" }, []string{"$.format"}},
+		{"versión", func(d *Document) { d.Version = 2; d.Roadmap.Title = "" }, []string{"$.version"}},
+		{"sin título", func(d *Document) { d.Roadmap.Title = " " }, []string{"$.roadmap.title"}},
+		{"categoría larga", func(d *Document) { d.Roadmap.Category = strings.Repeat("x", 51) }, []string{"$.roadmap.category"}},
+		{"clave duplicada", func(d *Document) { d.Nodes[1].ID = "a"; d.Connections = nil }, []string{"$.nodes[1].id"}},
+		{"tipo de nodo", func(d *Document) { d.Nodes[0].Type = "otro" }, []string{"$.nodes[0].type"}},
+		{"horas", func(d *Document) { h := 0.0; d.Nodes[1].Estimate = &h }, []string{"$.nodes[1].estimated_hours"}},
+		{"nodo desconocido", func(d *Document) { d.Connections[0].To = "z" }, []string{"$.connections[0].to"}},
+		{"consigo mismo", func(d *Document) { d.Connections[0].To = "a" }, []string{"$.connections[0]"}},
+		{"conexión duplicada", func(d *Document) { d.Connections = append(d.Connections, d.Connections[0]) }, []string{"$.connections[1]"}},
+		{"recurso sin url", func(d *Document) { d.Resources[0].URL = "" }, []string{"$.resources[0].url"}},
+		{"vuelta sin requisitos", func(d *Document) { d.Connections = append(d.Connections, Connection{From: "b", To: "a"}) }, nil},
+		{"ciclo", func(d *Document) {
+			d.Connections[0].Type = models.ConnectionTypeStrong
+			d.Connections = append(d.Connections, Connection{From: "b", To: "a", Type: models.ConnectionTypeStrong})
+		}, []string{"$.connections"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := valid()
+			tt.change(&doc)
+			problems := doc.Validate()
+			var paths []string
+			for _, problem := range problems {
+				paths = append(paths, problem.Path)
+			}
+			if strings.Join(paths, ",") != strings.Join(tt.paths, ",") {
+				t.Errorf("problemas = %v, se esperaban en %v", problems, tt.paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package layout
+
+// passes es el número de pasadas que acercan cada vértice a sus vecinos
+const passes = 8
+
+// coordinates calcula la coordenada de cada vértice a lo largo de su capa, en
+// unidades de separación entre nodos y empezando en cero. Cada pasada coloca
+// los vértices de una capa lo más cerca posible de la media de sus vecinos en
+// la capa anterior o la siguiente, sin cambiar su orden.
+func (l *layered) coordinates() []float64 {
+	coords := make([]float64, len(l.layer))
+	for v := range coords {
+		coords[v] = float64(l.pos[v])
+	}
+
+	for pass := 0; pass < passes; pass++ {
+		neighbors, layers := l.up, l.layers
+		if pass%2 == 1 {
+			neighbors = l.down
+			layers = make([][]int, len(l.layers))
+			for i, vertices := range l.layers {
+				layers[len(l.layers)-1-i] = vertices
+			}
+		}
+		for _, vertices := range layers {
+			desired := make([]float64, len(vertices))
+			for i, v := range vertices {
+				desired[i] = coords[v]
+				if len(neighbors[v]) > 0 {
+					sum := 0.0
+					for _, w := range neighbors[v] {
+						sum += coords[w]
+					}
+					desired[i] = sum / float64(len(neighbors[v]))
+				}
+			}
+			for i, c := range place(desired) {
+				coords[vertices[i]] = c
+			}
+		}
+	}
+
+	lowest := coords[0]
+	for _, c := range coords {
+		lowest = min(lowest, c)
+	}
+	for v := range coords {
+		coords[v] -= lowest
+	}
+	return coords
+}
+
+// place devuelve las coordenadas más cercanas a las deseadas, en mínimos
+// cuadrados, que mantienen el orden con al menos una unidad entre vértices
+// contiguos. Restando a cada vértice su posición el problema se reduce a una
+// regresión isotónica, que se resuelve uniendo bloques contiguos desordenados.
+func place(desired []float64) []float64 {
+	type block struct {
+		sum   float64
+		count int
+	}
+	mean := func(b block) float64 { return b.sum / float64(b.count) }
+
+	var blocks []block
+	for i, d := range desired {
+		blocks = append(blocks, block{d - float64(i), 1})
+		for len(blocks) > 1 && mean(blocks[len(blocks)-2]) > mean(blocks[len(blocks)-1]) {
+			last := blocks[len(blocks)-1]
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1].sum += last.sum
+			blocks[len(blocks)-1].count += last.count
+		}
+	}
+
+	coords := make([]float64, 0, len(desired))
+	for _, b := range blocks {
+		for range b.count {
+			coords = append(coords, mean(b)+float64(len(coords)))
+		}
+	}
+	return coords
+}
@@ -0,0 +1,120 @@
+package layout
+
+import (
+	"sort"
+)
+
+// sweeps es el número de pasadas de baricentro que se prueban para reducir
+// los cruces
+const sweeps = 24
+
+// reduceCrossings reordena las capas para reducir los cruces entre
+// conexiones. Alterna pasadas hacia abajo y hacia arriba ordenando cada capa
+// por el baricentro de sus vecinos en la capa ya fijada, mejora el resultado
+// intercambiando vértices contiguos y se queda con el mejor orden encontrado.
+func (l *layered) reduceCrossings() {
+	l.transpose()
+	best := l.snapshot()
+	bestCrossings := l.totalCrossings()
+	for i := 0; i < sweeps && bestCrossings > 0; i++ {
+		if i%2 == 0 {
+			for layer := 1; layer < len(l.layers); layer++ {
+				l.sortByBarycenter(layer, l.up)
+			}
+		} else {
+			for layer := len(l.layers) - 2; layer >= 0; layer-- {
+				l.sortByBarycenter(layer, l.down)
+			}
+		}
+		l.transpose()
+
+		if crossings := l.totalCrossings(); crossings < bestCrossings {
+			best, bestCrossings = l.snapshot(), crossings
+		}
+	}
+	l.layers = best
+	l.reindex()
+}
+
+// sortByBarycenter ordena la capa por la posición media de los vecinos de
+// cada vértice. Los vértices sin vecinos conservan su posición.
+func (l *layered) sortByBarycenter(layer int, neighbors [][]int) {
+	vertices := l.layers[layer]
+	barycenter := make(map[int]float64, len(vertices))
+	for _, v := range vertices {
+		if len(neighbors[v]) == 0 {
+			barycenter[v] = float64(l.pos[v])
+			continue
+		}
+		sum := 0
+		for _, w := range neighbors[v] {
+			sum += l.pos[w]
+		}
+		barycenter[v] = float64(sum) / float64(len(neighbors[v]))
+	}
+	sort.SliceStable(vertices, func(i, j int) bool { return barycenter[vertices[i]] < barycenter[vertices[j]] })
+	l.reindex()
+}
+
+// transpose intercambia vértices contiguos mientras eso reduzca los cruces
+func (l *layered) transpose() {
+	for improved := true; improved; {
+		improved = false
+		for _, vertices := range l.layers {
+			for i := 0; i+1 < len(vertices); i++ {
+				v, w := vertices[i], vertices[i+1]
+				if l.pairCrossings(w, v) < l.pairCrossings(v, w) {
+					vertices[i], vertices[i+1] = w, v
+					l.pos[v], l.pos[w] = i+1, i
+					improved = true
+				}
+			}
+		}
+	}
+}
+
+// pairCrossings cuenta los cruces entre las aristas de v y las de w si v
+// queda a la izquierda de w
+func (l *layered) pairCrossings(v, w int) int {
+	crossings := 0
+	for _, neighbors := range [][][]int{l.up, l.down} {
+		for _, a := range neighbors[v] {
+			for _, b := range neighbors[w] {
+				if l.pos[a] > l.pos[b] {
+					crossings++
+				}
+			}
+		}
+	}
+	return crossings
+}
+
+// totalCrossings cuenta los cruces entre todas las capas consecutivas
+func (l *layered) totalCrossings() int {
+	crossings := 0
+	for layer := 0; layer+1 < len(l.layers); layer++ {
+		var edges [][2]int
+		for _, v := range l.layers[layer] {
+			for _, w := range l.down[v] {
+				edges = append(edges, [2]int{l.pos[v], l.pos[w]})
+			}
+		}
+		for i, a := range edges {
+			for _, b := range edges[i+1:] {
+				if (a[0] < b[0] && a[1] > b[1]) || (a[0] > b[0] && a[1] < b[1]) {
+					crossings++
+				}
+			}
+		}
+	}
+	return crossings
+}
+
+// snapshot copia el orden actual de las capas
+func (l *layered) snapshot() [][]int {
+	layers := make([][]int, len(l.layers))
+	for i, vertices := range l.layers {
+		layers[i] = append([]int(nil), vertices...)
+	}
+	return layers
+}
@@ -0,0 +1,170 @@
+package layout
+
+import (
+	"sort"
+
+	"Gin/internal/graph"
+	"Gin/internal/models"
+)
+
+// layered es el grafo repartido en capas. Los vértices 0..n-1 son los nodos
+// del roadmap en su orden original; los siguientes son vértices ficticios que
+// parten las conexiones que saltan varias capas, de modo que cada arista une
+// dos capas consecutivas.
+type layered struct {
+	n      int
+	layer  []int   // capa de cada vértice
+	up     [][]int // vecinos de cada vértice en la capa anterior
+	down   [][]int // vecinos de cada vértice en la capa siguiente
+	layers [][]int // vértices de cada capa en su orden actual
+	pos    []int   // posición de cada vértice dentro de su capa
+}
+
+// build reparte los nodos en capas. Primero invierte las conexiones que
+// cierran ciclos para obtener un grafo acíclico y después coloca cada nodo
+// en la capa siguiente a la más profunda de sus predecesores.
+func build(nodes []models.Node, connections []models.Connection) *layered {
+	n := len(nodes)
+	index := make(map[models.ID]int, n)
+	for i, node := range nodes {
+		index[node.ID] = i
+	}
+
+	// Los recorridos empiezan por los nodos en el orden del plan de estudio
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return graph.Less(nodes[order[a]], nodes[order[b]]) })
+
+	out := make([][]int, n)
+	seen := make(map[[2]int]bool)
+	for _, conn := range connections {
+		from, okFrom := index[conn.FromNodeID]
+		to, okTo := index[conn.ToNodeID]
+		if !okFrom || !okTo || from == to || seen[[2]int{from, to}] {
+			continue
+		}
+		seen[[2]int{from, to}] = true
+		out[from] = append(out[from], to)
+	}
+
+	edges := acyclicEdges(order, out)
+
+	// Capa más profunda a la que obliga cada arista, en orden topológico
+	l := &layered{n: n, layer: make([]int, n)}
+	indegree := make([]int, n)
+	next := make([][]int, n)
+	for _, e := range edges {
+		next[e[0]] = append(next[e[0]], e[1])
+		indegree[e[1]]++
+	}
+	queue := []int{}
+	for _, v := range order {
+		if indegree[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range next[v] {
+			if l.layer[v]+1 > l.layer[w] {
+				l.layer[w] = l.layer[v] + 1
+			}
+			indegree[w]--
+			if indegree[w] == 0 {
+				queue = append(queue, w)
+			}
+		}
+	}
+
+	l.up = make([][]int, n)
+	l.down = make([][]int, n)
+	for _, e := range edges {
+		from := e[0]
+		for layer := l.layer[from] + 1; layer < l.layer[e[1]]; layer++ {
+			dummy := len(l.layer)
+			l.layer = append(l.layer, layer)
+			l.up = append(l.up, nil)
+			l.down = append(l.down, nil)
+			l.link(from, dummy)
+			from = dummy
+		}
+		l.link(from, e[1])
+	}
+
+	depth := 0
+	for _, layer := range l.layer {
+		depth = max(depth, layer+1)
+	}
+	l.layers = make([][]int, depth)
+	for _, v := range order {
+		l.layers[l.layer[v]] = append(l.layers[l.layer[v]], v)
+	}
+	for v := n; v < len(l.layer); v++ {
+		l.layers[l.layer[v]] = append(l.layers[l.layer[v]], v)
+	}
+	l.reindex()
+	return l
+}
+
+// acyclicEdges recorre el grafo en profundidad siguiendo order e invierte las
+// aristas que vuelven a un vértice de la pila, que son las que cierran ciclos
+func acyclicEdges(order []int, out [][]int) [][2]int {
+	const (
+		unvisited = iota
+		active
+		finished
+	)
+	state := make([]int, len(out))
+	seen := make(map[[2]int]bool)
+	var edges [][2]int
+	add := func(from, to int) {
+		if !seen[[2]int{from, to}] {
+			seen[[2]int{from, to}] = true
+			edges = append(edges, [2]int{from, to})
+		}
+	}
+
+	var visit func(v int)
+	visit = func(v int) {
+		state[v] = active
+		for _, w := range out[v] {
+			switch state[w] {
+			case active:
+				add(w, v)
+			case unvisited:
+				add(v, w)
+				visit(w)
+			default:
+				add(v, w)
+			}
+		}
+		state[v] = finished
+	}
+	for _, v := range order {
+		if state[v] == unvisited {
+			visit(v)
+		}
+	}
+	return edges
+}
+
+// link une dos vértices de capas consecutivas
+func (l *layered) link(from, to int) {
+	l.down[from] = append(l.down[from], to)
+	l.up[to] = append(l.up[to], from)
+}
+
+// reindex recalcula la posición de cada vértice dentro de su capa
+func (l *layered) reindex() {
+	if len(l.pos) != len(l.layer) {
+		l.pos = make([]int, len(l.layer))
+	}
+	for _, layer := range l.layers {
+		for i, v := range layer {
+			l.pos[v] = i
+		}
+	}
+}
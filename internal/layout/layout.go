@@ -0,0 +1,98 @@
+// Package layout coloca automáticamente los nodos de un roadmap por capas
+// siguiendo el método de Sugiyama: rompe los ciclos, reparte los nodos en
+// capas según el sentido de las conexiones, ordena cada capa para reducir los
+// cruces y asigna las coordenadas finales.
+package layout
+
+import (
+	"math"
+
+	"Gin/internal/models"
+)
+
+// Direction es el sentido en el que avanzan las capas
+type Direction string
+
+const (
+	DirectionTopDown   Direction = "top_down"
+	DirectionLeftRight Direction = "left_right"
+)
+
+// Valid indica si la dirección es una de las conocidas
+func (d Direction) Valid() bool {
+	return d == DirectionTopDown || d == DirectionLeftRight
+}
+
+// Options configura la colocación. Los campos a cero toman el valor de
+// DefaultOptions.
+type Options struct {
+	Direction    Direction
+	NodeSpacing  float64 // separación entre nodos de una misma capa
+	LayerSpacing float64 // separación entre capas consecutivas
+	Margin       float64 // distancia del primer nodo al origen del canvas
+}
+
+// DefaultOptions es la colocación de arriba abajo con las separaciones del
+// editor. El margen deja todas las coordenadas por encima de cero.
+var DefaultOptions = Options{
+	Direction:    DirectionTopDown,
+	NodeSpacing:  60,
+	LayerSpacing: 80,
+	Margin:       40,
+}
+
+// Result es la colocación calculada
+type Result struct {
+	Positions map[models.ID]models.Position
+	Layers    int
+	Crossings int
+}
+
+// Layout calcula la posición de cada nodo. Las conexiones de cualquier tipo
+// marcan el sentido; las que no unen dos de los nodos se ignoran.
+func Layout(nodes []models.Node, connections []models.Connection, opts Options) Result {
+	opts = withDefaults(opts)
+	result := Result{Positions: make(map[models.ID]models.Position, len(nodes))}
+	if len(nodes) == 0 {
+		return result
+	}
+
+	l := build(nodes, connections)
+	l.reduceCrossings()
+	coords := l.coordinates()
+
+	// Tamaño de los nodos a lo largo de la capa y entre capas
+	across, along := float64(models.NodeWidth), float64(models.NodeHeight)
+	if opts.Direction == DirectionLeftRight {
+		across, along = float64(models.NodeHeight), float64(models.NodeWidth)
+	}
+	for i, node := range nodes {
+		offset := opts.Margin + math.Round(coords[i]*(across+opts.NodeSpacing))
+		depth := opts.Margin + float64(l.layer[i])*(along+opts.LayerSpacing)
+		if opts.Direction == DirectionLeftRight {
+			result.Positions[node.ID] = models.Position{X: depth, Y: offset}
+		} else {
+			result.Positions[node.ID] = models.Position{X: offset, Y: depth}
+		}
+	}
+	result.Layers = len(l.layers)
+	result.Crossings = l.totalCrossings()
+	return result
+}
+
+// withDefaults completa las opciones sin valor
+func withDefaults(opts Options) Options {
+	if !opts.Direction.Valid() {
+		opts.Direction = DefaultOptions.Direction
+	}
+	if opts.NodeSpacing <= 0 {
+		opts.NodeSpacing = DefaultOptions.NodeSpacing
+	}
+	if opts.LayerSpacing <= 0 {
+		opts.LayerSpacing = DefaultOptions.LayerSpacing
+	}
+	if opts.Margin <= 0 {
+		opts.Margin = DefaultOptions.Margin
+	}
+	return opts
+}
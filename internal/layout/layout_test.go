@@ -0,0 +1,126 @@
+package layout
+
+import (
+	"testing"
+
+	"Gin/internal/models"
+)
+
+// sample construye n nodos y las conexiones indicadas entre sus índices
+func sample(n int, edges ...[2]int) ([]models.Node, []models.Connection) {
+	nodes := make([]models.Node, n)
+	for i := range nodes {
+		nodes[i] = models.Node{ID: models.NewID()}
+	}
+	connections := make([]models.Connection, len(edges))
+	for i, e := range edges {
+		connections[i] = models.Connection{ID: models.NewID(), FromNodeID: nodes[e[0]].ID, ToNodeID: nodes[e[1]].ID}
+	}
+	return nodes, connections
+}
+
+func TestLayoutLayers(t *testing.T) {
+	tests := []struct {
+		name   string
+		n      int
+		edges  [][2]int
+		layers []int // capa esperada de cada nodo
+	}{
+		{"sin conexiones", 3, nil, []int{0, 0, 0}},
+		{"cadena", 3, [][2]int{{0, 1}, {1, 2}}, []int{0, 1, 2}},
+		{"salto de capas", 3, [][2]int{{0, 1}, {1, 2}, {0, 2}}, []int{0, 1, 2}},
+		{"ciclo", 2, [][2]int{{0, 1}, {1, 0}}, []int{0, 1}},
+		{"autoconexión", 2, [][2]int{{0, 0}, {0, 1}}, []int{0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, connections := sample(tt.n, tt.edges...)
+			result := Layout(nodes, connections, DefaultOptions)
+			if len(result.Positions) != tt.n {
+				t.Fatalf("posiciones = %v", result.Positions)
+			}
+			step := float64(models.NodeHeight) + DefaultOptions.LayerSpacing
+			for i, node := range nodes {
+				want := DefaultOptions.Margin + float64(tt.layers[i])*step
+				if y := result.Positions[node.ID].Y; y != want {
+					t.Errorf("nodo %d en y = %v, se esperaba %v", i, y, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLayoutNoOverlap(t *testing.T) {
+	nodes, connections := sample(6, [2]int{0, 1}, [2]int{0, 2}, [2]int{0, 3}, [2]int{0, 4}, [2]int{5, 4})
+	result := Layout(nodes, connections, DefaultOptions)
+
+	for i, a := range nodes {
+		pa := result.Positions[a.ID]
+		if pa.X < DefaultOptions.Margin || pa.Y < DefaultOptions.Margin {
+			t.Errorf("nodo %d fuera del margen: %+v", i, pa)
+		}
+		for _, b := range nodes[i+1:] {
+			pb := result.Positions[b.ID]
+			if pa.Y == pb.Y && abs(pa.X-pb.X) < models.NodeWidth+DefaultOptions.NodeSpacing {
+				t.Errorf("nodos solapados en %+v y %+v", pa, pb)
+			}
+		}
+	}
+}
+
+func TestLayoutReducesCrossings(t *testing.T) {
+	// Con el orden original las dos conexiones se cruzan
+	nodes, connections := sample(4, [2]int{0, 3}, [2]int{1, 2})
+	result := Layout(nodes, connections, DefaultOptions)
+	if result.Layers != 2 || result.Crossings != 0 {
+		t.Errorf("capas = %d, cruces = %d", result.Layers, result.Crossings)
+	}
+}
+
+func TestLayoutDirection(t *testing.T) {
+	nodes, connections := sample(2, [2]int{0, 1})
+	result := Layout(nodes, connections, Options{Direction: DirectionLeftRight})
+	a, b := result.Positions[nodes[0].ID], result.Positions[nodes[1].ID]
+	if a.Y != b.Y || b.X != a.X+models.NodeWidth+DefaultOptions.LayerSpacing {
+		t.Errorf("posiciones de izquierda a derecha = %+v, %+v", a, b)
+	}
+}
+
+func TestLayoutIgnoresDanglingConnections(t *testing.T) {
+	nodes, connections := sample(2)
+	connections = append(connections, models.Connection{ID: models.NewID(), FromNodeID: nodes[0].ID, ToNodeID: models.NewID()})
+	if result := Layout(nodes, connections, DefaultOptions); result.Layers != 1 || len(result.Positions) != 2 {
+		t.Errorf("resultado = %+v", result)
+	}
+	if result := Layout(nil, nil, DefaultOptions); len(result.Positions) != 0 || result.Layers != 0 {
+		t.Errorf("resultado sin nodos = %+v", result)
+	}
+}
+
+func TestPlace(t *testing.T) {
+	tests := []struct {
+		name            string
+		desired, placed []float64
+	}{
+		{"ya separados", []float64{0, 2, 5}, []float64{0, 2, 5}},
+		{"en el mismo punto", []float64{1, 1, 1}, []float64{0, 1, 2}},
+		{"desordenados", []float64{3, 0}, []float64{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placed := place(tt.desired)
+			for i := range placed {
+				if abs(placed[i]-tt.placed[i]) > 1e-9 {
+					t.Fatalf("place(%v) = %v, se esperaba %v", tt.desired, placed, tt.placed)
+				}
+			}
+		})
+	}
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
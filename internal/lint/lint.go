@@ -0,0 +1,92 @@
+// Package lint revisa la calidad del contenido de un roadmap. Cada regla
+// produce diagnósticos con una gravedad y los nodos o recursos afectados; los
+// errores impiden publicar el roadmap.
+package lint
+
+import (
+	"Gin/internal/graph"
+	"Gin/internal/models"
+)
+
+// Severity es la gravedad de un diagnóstico
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Diagnostic es un problema encontrado por una regla
+type Diagnostic struct {
+	Rule        string      `json:"rule"`
+	Severity    Severity    `json:"severity"`
+	Message     string      `json:"message"`
+	Nodes       []models.ID `json:"nodes,omitempty"`
+	Connections []models.ID `json:"connections,omitempty"`
+	Resources   []models.ID `json:"resources,omitempty"`
+}
+
+// Rule es una regla del linter. Check solo rellena el mensaje y los elementos
+// afectados; Run completa el nombre y la gravedad de la regla.
+type Rule struct {
+	Name     string
+	Severity Severity
+	Check    func(r *Roadmap) []Diagnostic
+}
+
+// Roadmap es el contenido que analizan las reglas
+type Roadmap struct {
+	models.Snapshot
+	Graph *graph.Graph
+	// resources agrupa los recursos por nodo
+	resources map[models.ID][]models.Resource
+}
+
+// newRoadmap prepara el contenido del roadmap para las reglas
+func newRoadmap(snapshot models.Snapshot) *Roadmap {
+	r := &Roadmap{
+		Snapshot:  snapshot,
+		Graph:     graph.New(snapshot.Nodes, snapshot.Connections),
+		resources: make(map[models.ID][]models.Resource),
+	}
+	for _, resource := range snapshot.Resources {
+		r.resources[resource.NodeID] = append(r.resources[resource.NodeID], resource)
+	}
+	return r
+}
+
+// NodeResources devuelve los recursos del nodo
+func (r *Roadmap) NodeResources(nodeID models.ID) []models.Resource {
+	return r.resources[nodeID]
+}
+
+// Run aplica las reglas al contenido del roadmap y devuelve los diagnósticos
+// en el orden de las reglas. Sin reglas aplica DefaultRules.
+func Run(snapshot models.Snapshot, rules ...Rule) []Diagnostic {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+
+	r := newRoadmap(snapshot)
+	diagnostics := []Diagnostic{}
+	for _, rule := range rules {
+		for _, d := range rule.Check(r) {
+			d.Rule = rule.Name
+			d.Severity = rule.Severity
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return diagnostics
+}
+
+// Count devuelve cuántos diagnósticos tienen la gravedad indicada
+func Count(diagnostics []Diagnostic, severity Severity) int {
+	n := 0
+	for _, d := range diagnostics {
+		if d.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,159 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"Gin/internal/models"
+)
+
+// roadmap construye un roadmap sin problemas: A → B → C, separados en el
+// canvas y con descripción. Devuelve también el id de cada título.
+func roadmap() (models.Snapshot, map[string]models.ID) {
+	ids := make(map[string]models.ID)
+	var s models.Snapshot
+	for i, title := range []string{"A", "B", "C"} {
+		s.Nodes = append(s.Nodes, models.Node{ID: models.NewID(), Title: title, Description: "Descripción de " + title,
+			Type: models.NodeTypeTopic, Position: models.Position{Y: float64(i * 200)}})
+		ids[title] = s.Nodes[i].ID
+	}
+	s.Connections = []models.Connection{connect(ids["A"], ids["B"]), connect(ids["B"], ids["C"])}
+	return s, ids
+}
+
+func connect(from, to models.ID) models.Connection {
+	return models.Connection{ID: models.NewID(), FromNodeID: from, ToNodeID: to, ConnectionType: models.ConnectionTypeDefault}
+}
+
+// addNode añade un nodo separado del resto en el canvas
+func addNode(s *models.Snapshot, ids map[string]models.ID, title string) *models.Node {
+	s.Nodes = append(s.Nodes, models.Node{ID: models.NewID(), Title: title, Description: "Descripción de " + title,
+		Type: models.NodeTypeTopic, Position: models.Position{X: 500, Y: float64(len(s.Nodes) * 200)}})
+	ids[title] = s.Nodes[len(s.Nodes)-1].ID
+	return &s.Nodes[len(s.Nodes)-1]
+}
+
+func TestRun(t *testing.T) {
+	type diagnostic struct {
+		rule     string
+		severity Severity
+		nodes    string // títulos de los nodos afectados separados por comas
+	}
+	tests := []struct {
+		name   string
+		change func(s *models.Snapshot, ids map[string]models.ID)
+		want   []diagnostic
+	}{
+		{"sin problemas", func(s *models.Snapshot, ids map[string]models.ID) {}, nil},
+		{"ciclo de requisitos", func(s *models.Snapshot, ids map[string]models.ID) {
+			s.Connections = append(s.Connections, connect(ids["C"], ids["B"]))
+			s.Connections[1].ConnectionType = models.ConnectionTypeStrong
+			s.Connections[2].ConnectionType = models.ConnectionTypeStrong
+		}, []diagnostic{{"integrity", SeverityError, "B,C,B"}}},
+		{"nodo huérfano", func(s *models.Snapshot, ids map[string]models.ID) {
+			addNode(s, ids, "D")
+		}, []diagnostic{{"orphan_node", SeverityWarning, "D"}}},
+		{"nodos inalcanzables", func(s *models.Snapshot, ids map[string]models.ID) {
+			addNode(s, ids, "D")
+			addNode(s, ids, "E")
+			s.Connections = append(s.Connections, connect(ids["D"], ids["E"]), connect(ids["E"], ids["D"]))
+		}, []diagnostic{{"unreachable_node", SeverityWarning, "D"}, {"unreachable_node", SeverityWarning, "E"}}},
+		{"nodo de recurso sin recursos", func(s *models.Snapshot, ids map[string]models.ID) {
+			s.Nodes[1].Type = models.NodeTypeResource
+			s.Nodes[2].Type = models.NodeTypeResource
+			s.Resources = []models.Resource{{ID: models.NewID(), NodeID: ids["C"], Title: "Docs", URL: "https://go.dev/doc"}}
+		}, []diagnostic{{"resource_without_resources", SeverityWarning, "B"}}},
+		{"hito sin conexiones de entrada", func(s *models.Snapshot, ids map[string]models.ID) {
+			s.Nodes[0].Type = models.NodeTypeMilestone
+			s.Nodes[2].Type = models.NodeTypeMilestone
+		}, []diagnostic{{"milestone_without_incoming", SeverityWarning, "A"}}},
+		{"títulos repetidos", func(s *models.Snapshot, ids map[string]models.ID) {
+			s.Nodes[0].Title = " Go  básico"
+			s.Nodes[2].Title = "go básico"
+		}, []diagnostic{{"duplicate_title", SeverityWarning, "A,C"}}},
+		{"nodos solapados", func(s *models.Snapshot, ids map[string]models.ID) {
+			s.Nodes[2].Position = models.Position{X: models.NodeWidth - 1, Y: 200 + models.NodeHeight - 1}
+		}, []diagnostic{{"overlapping_nodes", SeverityWarning, "B,C"}}},
+		{"urls repetidas", func(s *models.Snapshot, ids map[string]models.ID) {
+			s.Resources = []models.Resource{
+				{ID: models.NewID(), NodeID: ids["A"], Title: "Docs", URL: "https://go.dev/doc"},
+				{ID: models.NewID(), NodeID: ids["C"], Title: "Documentación", URL: "HTTPS://go.dev/doc/"},
+				{ID: models.NewID(), NodeID: ids["C"], Title: "Otra vez", URL: "https://go.dev/doc"},
+				{ID: models.NewID(), NodeID: ids["B"], Title: "Sin url"},
+				{ID: models.NewID(), NodeID: ids["B"], Title: "Sin url"},
+			}
+		}, []diagnostic{{"duplicate_resource_url", SeverityWarning, "A,C"}}},
+		{"descripción vacía", func(s *models.Snapshot, ids map[string]models.ID) {
+			s.Nodes[1].Description = "  "
+		}, []diagnostic{{"empty_description", SeverityInfo, "B"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ids := roadmap()
+			tt.change(&s, ids)
+			titles := make(map[models.ID]string)
+			for title, id := range ids {
+				titles[id] = title
+			}
+
+			diagnostics := Run(s)
+			if len(diagnostics) != len(tt.want) {
+				t.Fatalf("diagnósticos = %+v", diagnostics)
+			}
+			for i, d := range diagnostics {
+				names := make([]string, len(d.Nodes))
+				for j, id := range d.Nodes {
+					names[j] = titles[id]
+				}
+				got := diagnostic{d.Rule, d.Severity, strings.Join(names, ",")}
+				if got != tt.want[i] {
+					t.Errorf("diagnóstico %d = %+v, se esperaba %+v", i, got, tt.want[i])
+				}
+				if d.Message == "" {
+					t.Errorf("diagnóstico %d sin mensaje", i)
+				}
+			}
+		})
+	}
+}
+
+func TestRunDuplicateURLResources(t *testing.T) {
+	s, ids := roadmap()
+	s.Resources = []models.Resource{
+		{ID: models.NewID(), NodeID: ids["A"], URL: "https://go.dev/doc"},
+		{ID: models.NewID(), NodeID: ids["B"], URL: "https://go.dev/tour"},
+		{ID: models.NewID(), NodeID: ids["A"], URL: "https://go.dev/doc/"},
+	}
+	diagnostics := Run(s)
+	if len(diagnostics) != 1 {
+		t.Fatalf("diagnósticos = %+v", diagnostics)
+	}
+	if got := diagnostics[0].Resources; len(got) != 2 || got[0] != s.Resources[0].ID || got[1] != s.Resources[2].ID {
+		t.Errorf("recursos = %v", got)
+	}
+	if got := diagnostics[0].Nodes; len(got) != 1 || got[0] != ids["A"] {
+		t.Errorf("nodos = %v", got)
+	}
+}
+
+func TestRunRules(t *testing.T) {
+	s, ids := roadmap()
+	addNode(&s, ids, "D").Description = ""
+
+	always := Rule{"custom", SeverityError, func(r *Roadmap) []Diagnostic {
+		return []Diagnostic{{Message: "siempre", Rule: "otra", Severity: SeverityInfo}}
+	}}
+	diagnostics := Run(s, always, Rule{"orphan", SeverityInfo, checkOrphanNodes})
+	if len(diagnostics) != 2 {
+		t.Fatalf("diagnósticos = %+v", diagnostics)
+	}
+	if d := diagnostics[0]; d.Rule != "custom" || d.Severity != SeverityError {
+		t.Errorf("Run no completa la regla y la gravedad: %+v", d)
+	}
+	if d := diagnostics[1]; d.Rule != "orphan" || d.Severity != SeverityInfo || d.Nodes[0] != ids["D"] {
+		t.Errorf("diagnóstico = %+v", d)
+	}
+	if Count(diagnostics, SeverityError) != 1 || Count(Run(s), SeverityInfo) != 1 {
+		t.Errorf("Count = %d, %d", Count(diagnostics, SeverityError), Count(Run(s), SeverityInfo))
+	}
+}
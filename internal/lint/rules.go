@@ -0,0 +1,214 @@
+package lint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"Gin/internal/graph"
+	"Gin/internal/models"
+)
+
+// DefaultRules son las reglas que aplica Run si no se indican otras
+var DefaultRules = []Rule{
+	{"integrity", SeverityError, checkIntegrity},
+	{"orphan_node", SeverityWarning, checkOrphanNodes},
+	{"unreachable_node", SeverityWarning, checkUnreachableNodes},
+	{"resource_without_resources", SeverityWarning, checkResourceNodes},
+	{"milestone_without_incoming", SeverityWarning, checkMilestones},
+	{"duplicate_title", SeverityWarning, checkDuplicateTitles},
+	{"overlapping_nodes", SeverityWarning, checkOverlappingNodes},
+	{"duplicate_resource_url", SeverityWarning, checkDuplicateURLs},
+	{"empty_description", SeverityInfo, checkEmptyDescriptions},
+}
+
+// checkIntegrity incluye los problemas de integridad del grafo
+func checkIntegrity(r *Roadmap) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, issue := range graph.Validate(r.Nodes, r.Connections) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Message:     issue.Message,
+			Nodes:       issue.Nodes,
+			Connections: issue.Connections,
+		})
+	}
+	return diagnostics
+}
+
+// checkOrphanNodes señala los nodos sin ninguna conexión cuando el roadmap
+// tiene más de uno
+func checkOrphanNodes(r *Roadmap) []Diagnostic {
+	if len(r.Nodes) < 2 {
+		return nil
+	}
+	var diagnostics []Diagnostic
+	for _, node := range r.Nodes {
+		if len(r.Graph.Incoming(node.ID)) == 0 && len(r.Graph.Outgoing(node.ID)) == 0 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Message: fmt.Sprintf("El nodo «%s» no tiene conexiones", node.Title),
+				Nodes:   []models.ID{node.ID},
+			})
+		}
+	}
+	return diagnostics
+}
+
+// checkUnreachableNodes señala los nodos a los que no se llega desde ningún
+// nodo inicial, es decir, sin conexiones de entrada
+func checkUnreachableNodes(r *Roadmap) []Diagnostic {
+	var roots []models.ID
+	for _, node := range r.Nodes {
+		if len(r.Graph.Incoming(node.ID)) == 0 {
+			roots = append(roots, node.ID)
+		}
+	}
+
+	reached := r.Graph.Reachable(roots...)
+	var diagnostics []Diagnostic
+	for _, node := range r.Nodes {
+		if !reached[node.ID] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Message: fmt.Sprintf("No se puede llegar al nodo «%s» desde ningún nodo inicial", node.Title),
+				Nodes:   []models.ID{node.ID},
+			})
+		}
+	}
+	return diagnostics
+}
+
+// checkResourceNodes señala los nodos de tipo recurso sin recursos
+func checkResourceNodes(r *Roadmap) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, node := range r.Nodes {
+		if node.Type == models.NodeTypeResource && len(r.NodeResources(node.ID)) == 0 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Message: fmt.Sprintf("El nodo de recurso «%s» no tiene recursos", node.Title),
+				Nodes:   []models.ID{node.ID},
+			})
+		}
+	}
+	return diagnostics
+}
+
+// checkMilestones señala los hitos a los que no llega ninguna conexión
+func checkMilestones(r *Roadmap) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, node := range r.Nodes {
+		if node.Type == models.NodeTypeMilestone && len(r.Graph.Incoming(node.ID)) == 0 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Message: fmt.Sprintf("El hito «%s» no tiene conexiones de entrada", node.Title),
+				Nodes:   []models.ID{node.ID},
+			})
+		}
+	}
+	return diagnostics
+}
+
+// checkDuplicateTitles agrupa los nodos con el mismo título, sin distinguir
+// mayúsculas ni espacios
+func checkDuplicateTitles(r *Roadmap) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, group := range groupBy(r.Nodes, func(n models.Node) string { return normalize(n.Title) }) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Message: fmt.Sprintf("Hay %d nodos con el título «%s»", len(group), strings.TrimSpace(group[0].Title)),
+			Nodes:   nodeIDs(group),
+		})
+	}
+	return diagnostics
+}
+
+// checkOverlappingNodes señala los pares de nodos que se solapan en el canvas
+func checkOverlappingNodes(r *Roadmap) []Diagnostic {
+	var diagnostics []Diagnostic
+	for i, a := range r.Nodes {
+		for _, b := range r.Nodes[i+1:] {
+			if math.Abs(a.Position.X-b.Position.X) < models.NodeWidth && math.Abs(a.Position.Y-b.Position.Y) < models.NodeHeight {
+				diagnostics = append(diagnostics, Diagnostic{
+					Message: fmt.Sprintf("Los nodos «%s» y «%s» se solapan", a.Title, b.Title),
+					Nodes:   []models.ID{a.ID, b.ID},
+				})
+			}
+		}
+	}
+	return diagnostics
+}
+
+// checkDuplicateURLs agrupa los recursos que enlazan a la misma URL
+func checkDuplicateURLs(r *Roadmap) []Diagnostic {
+	var resources []models.Resource
+	for _, resource := range r.Resources {
+		if strings.TrimSpace(resource.URL) != "" {
+			resources = append(resources, resource)
+		}
+	}
+
+	var diagnostics []Diagnostic
+	for _, group := range groupBy(resources, func(res models.Resource) string {
+		return strings.TrimSuffix(normalize(res.URL), "/")
+	}) {
+		d := Diagnostic{Message: fmt.Sprintf("Hay %d recursos que enlazan a %s", len(group), strings.TrimSpace(group[0].URL))}
+		seen := make(map[models.ID]bool)
+		for _, resource := range group {
+			d.Resources = append(d.Resources, resource.ID)
+			if !seen[resource.NodeID] {
+				seen[resource.NodeID] = true
+				d.Nodes = append(d.Nodes, resource.NodeID)
+			}
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics
+}
+
+// checkEmptyDescriptions señala los nodos sin descripción
+func checkEmptyDescriptions(r *Roadmap) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, node := range r.Nodes {
+		if strings.TrimSpace(node.Description) == "" {
+			diagnostics = append(diagnostics, Diagnostic{
+				Message: fmt.Sprintf("El nodo «%s» no tiene descripción", node.Title),
+				Nodes:   []models.ID{node.ID},
+			})
+		}
+	}
+	return diagnostics
+}
+
+// groupBy agrupa los elementos con la misma clave no vacía y devuelve los
+// grupos de más de uno, en el orden de su primer elemento
+func groupBy[T any](items []T, key func(T) string) [][]T {
+	groups := make(map[string][]T)
+	var order []string
+	for _, item := range items {
+		k := key(item)
+		if k == "" {
+			continue
+		}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], item)
+	}
+
+	var duplicated [][]T
+	for _, k := range order {
+		if len(groups[k]) > 1 {
+			duplicated = append(duplicated, groups[k])
+		}
+	}
+	return duplicated
+}
+
+// normalize compara textos sin distinguir mayúsculas ni espacios repetidos
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// nodeIDs devuelve los ids de los nodos
+func nodeIDs(nodes []models.Node) []models.ID {
+	ids := make([]models.ID, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.ID
+	}
+	return ids
+}
@@ -0,0 +1,88 @@
+package merge
+
+import (
+	"Gin/internal/models"
+)
+
+// Apply devuelve el contenido que tendrá fork tras aplicar los cambios de la
+// fusión, sin guardar nada, para validarlo antes de aplicarlo. Los elementos
+// creados usan su clave como id y, al eliminar un nodo, se eliminan también
+// sus conexiones y recursos.
+func (r *Result) Apply(fork models.Snapshot) models.Snapshot {
+	nodeIDs := make(map[models.ID]models.ID, len(r.NodeIDs))
+	for key, id := range r.NodeIDs {
+		nodeIDs[key] = id
+	}
+	for _, change := range r.Changes {
+		if change.Kind == KindNode && change.Op == OpCreate {
+			nodeIDs[change.Key] = change.Key
+		}
+	}
+
+	elements := newSet(fork)
+	for _, change := range r.Changes {
+		switch change.Kind {
+		case KindNode:
+			applyChange(elements.nodes, change, change.Node, func(n *models.Node, id models.ID) { n.ID = id })
+		case KindConnection:
+			applyChange(elements.connections, change, change.Connection, func(c *models.Connection, id models.ID) {
+				c.ID, c.FromNodeID, c.ToNodeID = id, nodeIDs[c.FromNodeID], nodeIDs[c.ToNodeID]
+			})
+		case KindResource:
+			applyChange(elements.resources, change, change.Resource, func(res *models.Resource, id models.ID) {
+				res.ID, res.NodeID = id, nodeIDs[res.NodeID]
+			})
+		}
+	}
+
+	var merged models.Snapshot
+	merged.Nodes = ordered(fork.Nodes, r.Changes, KindNode, elements.nodes, func(n models.Node) models.ID { return n.ID })
+	for _, c := range ordered(fork.Connections, r.Changes, KindConnection, elements.connections, func(c models.Connection) models.ID { return c.ID }) {
+		if _, ok := elements.nodes[c.FromNodeID]; ok {
+			if _, ok := elements.nodes[c.ToNodeID]; ok {
+				merged.Connections = append(merged.Connections, c)
+			}
+		}
+	}
+	for _, res := range ordered(fork.Resources, r.Changes, KindResource, elements.resources, func(res models.Resource) models.ID { return res.ID }) {
+		if _, ok := elements.nodes[res.NodeID]; ok {
+			merged.Resources = append(merged.Resources, res)
+		}
+	}
+	return merged
+}
+
+// applyChange aplica un cambio sobre los elementos de un tipo indexados por
+// id. fix asigna el id al elemento y traduce sus referencias a nodos.
+func applyChange[T any](elements map[models.ID]T, change Change, value *T, fix func(*T, models.ID)) {
+	id := change.ID
+	if change.Op == OpCreate {
+		id = change.Key
+	}
+	if change.Op == OpDelete {
+		delete(elements, id)
+		return
+	}
+	element := *value
+	fix(&element, id)
+	elements[id] = element
+}
+
+// ordered devuelve los elementos que quedan en el orden del fork, seguidos de
+// los creados en el orden de los cambios
+func ordered[T any](original []T, changes []Change, kind Kind, elements map[models.ID]T, idOf func(T) models.ID) []T {
+	out := make([]T, 0, len(elements))
+	for _, element := range original {
+		if e, ok := elements[idOf(element)]; ok {
+			out = append(out, e)
+		}
+	}
+	for _, change := range changes {
+		if change.Kind == kind && change.Op == OpCreate {
+			if e, ok := elements[change.Key]; ok {
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package merge
+
+import (
+	"testing"
+
+	"Gin/internal/graph"
+	"Gin/internal/models"
+)
+
+func TestApply(t *testing.T) {
+	base := original()
+	upstream, fork := clone(base), forkOf(base)
+	upstream.Nodes[0].Title = "A del original"
+	c := models.Node{ID: models.NewID(), Title: "C", Type: models.NodeTypeTopic}
+	upstream.Nodes = append(upstream.Nodes, c)
+	upstream.Connections = append(upstream.Connections, models.Connection{ID: models.NewID(), FromNodeID: base.Nodes[1].ID, ToNodeID: c.ID})
+	upstream.Resources = append(upstream.Resources, models.Resource{ID: models.NewID(), NodeID: c.ID, Title: "Blog", URL: "https://go.dev/blog"})
+
+	result := Merge(base, upstream, fork, nil)
+	merged := result.Apply(fork)
+	if len(merged.Nodes) != 3 || merged.Nodes[0].ID != fork.Nodes[0].ID || merged.Nodes[0].Title != "A del original" || merged.Nodes[2].ID != c.ID {
+		t.Errorf("nodos = %+v", merged.Nodes)
+	}
+	if len(merged.Connections) != 2 {
+		t.Fatalf("conexiones = %+v", merged.Connections)
+	}
+	if conn := merged.Connections[1]; conn.FromNodeID != fork.Nodes[1].ID || conn.ToNodeID != c.ID {
+		t.Errorf("la conexión creada no usa los ids del fork: %+v", conn)
+	}
+	if len(merged.Resources) != 2 || merged.Resources[1].NodeID != c.ID {
+		t.Errorf("recursos = %+v", merged.Resources)
+	}
+
+	// Al eliminar un nodo desaparecen también sus conexiones y recursos
+	upstream = clone(base)
+	upstream.Nodes = upstream.Nodes[1:]
+	upstream.Connections, upstream.Resources = nil, nil
+	result = Merge(base, upstream, fork, nil)
+	merged = result.Apply(fork)
+	if len(merged.Nodes) != 1 || len(merged.Connections) != 0 || len(merged.Resources) != 0 {
+		t.Errorf("contenido tras eliminar A = %+v", merged)
+	}
+	if len(fork.Nodes) != 2 || len(fork.Connections) != 1 {
+		t.Errorf("Apply modifica el fork: %+v", fork)
+	}
+}
+
+func TestApplyRequirementCycle(t *testing.T) {
+	strong := func(from, to models.ID) models.Connection {
+		return models.Connection{ID: models.NewID(), FromNodeID: from, ToNodeID: to, ConnectionType: models.ConnectionTypeStrong}
+	}
+	tests := []struct {
+		name string
+		// current es el contenido actual: un fork o, en las propuestas, el
+		// propio roadmap
+		current func(base models.Snapshot) models.Snapshot
+	}{
+		{"sincronizar un fork", forkOf},
+		{"aceptar una propuesta", clone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := original()
+			base.Connections = nil
+			a, b := base.Nodes[0].ID, base.Nodes[1].ID
+
+			// El original o la propuesta añade A → B, y el contenido actual ya
+			// tiene B → A
+			upstream, current := clone(base), tt.current(base)
+			upstream.Connections = append(upstream.Connections, strong(a, b))
+			current.Connections = append(current.Connections, strong(current.Nodes[1].ID, current.Nodes[0].ID))
+
+			result := Merge(base, upstream, current, nil)
+			if len(result.Conflicts) != 0 || len(result.Changes) != 1 {
+				t.Fatalf("resultado = %+v", result)
+			}
+			if cycles := graph.New(current.Nodes, current.Connections).Cycles(); len(cycles) != 0 {
+				t.Fatalf("el contenido actual ya tiene ciclos: %v", cycles)
+			}
+			merged := result.Apply(current)
+			cycles := graph.New(merged.Nodes, merged.Connections).Cycles()
+			if len(cycles) != 1 || len(cycles[0]) != 3 {
+				t.Errorf("ciclos tras la fusión = %v", cycles)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package merge
+
+import (
+	"Gin/internal/models"
+)
+
+// Difference es un cambio entre dos versiones del contenido de un roadmap.
+// En las modificaciones, Fields son los campos que cambiaron.
+type Difference struct {
+	Kind   Kind      `json:"kind"`
+	Op     Op        `json:"op"`
+	ID     models.ID `json:"id"`
+	Fields []string  `json:"fields,omitempty"`
+	Before any       `json:"before,omitempty"`
+	After  any       `json:"after,omitempty"`
+}
+
+// Diff compara dos versiones del contenido de un roadmap, identificando cada
+// elemento por su id
+func Diff(from, to models.Snapshot) []Difference {
+	a, b := newSet(from), newSet(to)
+	diffs := []Difference{}
+	diffs = diffKind(diffs, KindNode, nodeFields, a.nodes, b.nodes)
+	diffs = diffKind(diffs, KindConnection, connectionFields, a.connections, b.connections)
+	diffs = diffKind(diffs, KindResource, resourceFields, a.resources, b.resources)
+	return diffs
+}
+
+// DiffDetails compara los datos generales de un roadmap en dos revisiones. Si
+// cambiaron devuelve una única modificación de tipo KindRoadmap con el id del
+// roadmap.
+func DiffDetails(roadmapID models.ID, from, to models.RevisionDetails) []Difference {
+	return diffKind([]Difference{}, KindRoadmap, detailFields,
+		map[models.ID]models.RevisionDetails{roadmapID: from},
+		map[models.ID]models.RevisionDetails{roadmapID: to})
+}
+
+var detailFields = []field[models.RevisionDetails]{
+	{"title", func(d models.RevisionDetails) any { return d.Title }, nil},
+	{"description", func(d models.RevisionDetails) any { return d.Description }, nil},
+	{"category", func(d models.RevisionDetails) any { return d.Category }, nil},
+	{"is_public", func(d models.RevisionDetails) any { return d.IsPublic }, nil},
+}
+
+func diffKind[T any](diffs []Difference, kind Kind, fields []field[T], from, to map[models.ID]T) []Difference {
+	for _, id := range sortedKeys(from, to) {
+		before, inFrom := from[id]
+		after, inTo := to[id]
+		switch {
+		case !inTo:
+			diffs = append(diffs, Difference{Kind: kind, Op: OpDelete, ID: id, Before: before})
+		case !inFrom:
+			diffs = append(diffs, Difference{Kind: kind, Op: OpCreate, ID: id, After: after})
+		default:
+			var changed []string
+			for _, f := range fields {
+				if f.get(before) != f.get(after) {
+					changed = append(changed, f.name)
+				}
+			}
+			if len(changed) > 0 {
+				diffs = append(diffs, Difference{Kind: kind, Op: OpUpdate, ID: id, Fields: changed, Before: before, After: after})
+			}
+		}
+	}
+	return diffs
+}
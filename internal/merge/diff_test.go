@@ -0,0 +1,84 @@
+package merge
+
+import (
+	"strings"
+	"testing"
+
+	"Gin/internal/models"
+)
+
+func TestDiff(t *testing.T) {
+	from := original()
+	to := clone(from)
+	to.Nodes[0].Title = "A renombrado"
+	to.Nodes[0].Position = models.Position{X: 100, Y: 0}
+	to.Nodes = append(to.Nodes, models.Node{ID: models.NewID(), Title: "C"})
+	to.Connections = nil
+	hours := 2.0
+	to.Nodes[1].Estimate = &hours
+
+	diffs := Diff(from, to)
+	got := map[models.ID]Difference{}
+	for _, diff := range diffs {
+		got[diff.ID] = diff
+	}
+	if len(diffs) != 4 {
+		t.Fatalf("diferencias = %+v", diffs)
+	}
+
+	tests := []struct {
+		name   string
+		id     models.ID
+		kind   Kind
+		op     Op
+		fields string
+	}{
+		{"campos del nodo", from.Nodes[0].ID, KindNode, OpUpdate, "title,position"},
+		{"campo opcional", from.Nodes[1].ID, KindNode, OpUpdate, "estimated_hours"},
+		{"nodo nuevo", to.Nodes[2].ID, KindNode, OpCreate, ""},
+		{"conexión eliminada", from.Connections[0].ID, KindConnection, OpDelete, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, ok := got[tt.id]
+			if !ok {
+				t.Fatalf("falta la diferencia de %s", tt.id)
+			}
+			if diff.Kind != tt.kind || diff.Op != tt.op || strings.Join(diff.Fields, ",") != tt.fields {
+				t.Errorf("diferencia = %s %s %v, se esperaba %s %s %s", diff.Op, diff.Kind, diff.Fields, tt.op, tt.kind, tt.fields)
+			}
+			if (diff.Before == nil) != (tt.op == OpCreate) || (diff.After == nil) != (tt.op == OpDelete) {
+				t.Errorf("before = %v, after = %v", diff.Before, diff.After)
+			}
+		})
+	}
+}
+
+func TestDiffIgnoresUnmergedFields(t *testing.T) {
+	from := original()
+	to := clone(from)
+	to.Nodes[0].Version++
+	to.Nodes[0].RoadmapID = models.NewID()
+
+	if diffs := Diff(from, to); len(diffs) != 0 {
+		t.Errorf("diferencias = %+v", diffs)
+	}
+}
+
+func TestDiffDetails(t *testing.T) {
+	id := models.NewID()
+	from := models.RevisionDetails{Title: "Go", Description: "Desde cero"}
+	to := from
+	if diffs := DiffDetails(id, from, to); len(diffs) != 0 {
+		t.Errorf("diferencias sin cambios = %+v", diffs)
+	}
+
+	to.Title, to.IsPublic = "Go avanzado", true
+	diffs := DiffDetails(id, from, to)
+	if len(diffs) != 1 {
+		t.Fatalf("diferencias = %+v", diffs)
+	}
+	if diff := diffs[0]; diff.Kind != KindRoadmap || diff.Op != OpUpdate || diff.ID != id || strings.Join(diff.Fields, ",") != "title,is_public" {
+		t.Errorf("diferencia = %+v", diff)
+	}
+}
@@ -0,0 +1,248 @@
+package merge
+
+import (
+	"sort"
+
+	"Gin/internal/models"
+)
+
+// field es un campo fusionable de un elemento
+type field[T any] struct {
+	name string
+	get  func(T) any
+	set  func(dst *T, src T)
+}
+
+var nodeFields = []field[models.Node]{
+	{"title", func(n models.Node) any { return n.Title }, func(d *models.Node, s models.Node) { d.Title = s.Title }},
+	{"description", func(n models.Node) any { return n.Description }, func(d *models.Node, s models.Node) { d.Description = s.Description }},
+	{"type", func(n models.Node) any { return n.Type }, func(d *models.Node, s models.Node) { d.Type = s.Type }},
+	{"position", func(n models.Node) any { return n.Position }, func(d *models.Node, s models.Node) { d.Position = s.Position }},
+	{"status", func(n models.Node) any { return n.Status }, func(d *models.Node, s models.Node) { d.Status = s.Status }},
+	{"color", func(n models.Node) any { return n.Color }, func(d *models.Node, s models.Node) { d.Color = s.Color }},
+	{"order_index", func(n models.Node) any { return optional(n.OrderIndex) }, func(d *models.Node, s models.Node) { d.OrderIndex = s.OrderIndex }},
+	{"estimated_hours", func(n models.Node) any { return optional(n.Estimate) }, func(d *models.Node, s models.Node) { d.Estimate = s.Estimate }},
+}
+
+var connectionFields = []field[models.Connection]{
+	{"from_node_id", func(c models.Connection) any { return c.FromNodeID }, func(d *models.Connection, s models.Connection) { d.FromNodeID = s.FromNodeID }},
+	{"to_node_id", func(c models.Connection) any { return c.ToNodeID }, func(d *models.Connection, s models.Connection) { d.ToNodeID = s.ToNodeID }},
+	{"label", func(c models.Connection) any { return c.Label }, func(d *models.Connection, s models.Connection) { d.Label = s.Label }},
+	{"connection_type", func(c models.Connection) any { return c.ConnectionType }, func(d *models.Connection, s models.Connection) { d.ConnectionType = s.ConnectionType }},
+}
+
+var resourceFields = []field[models.Resource]{
+	{"node_id", func(r models.Resource) any { return r.NodeID }, func(d *models.Resource, s models.Resource) { d.NodeID = s.NodeID }},
+	{"title", func(r models.Resource) any { return r.Title }, func(d *models.Resource, s models.Resource) { d.Title = s.Title }},
+	{"type", func(r models.Resource) any { return r.Type }, func(d *models.Resource, s models.Resource) { d.Type = s.Type }},
+	{"url", func(r models.Resource) any { return r.URL }, func(d *models.Resource, s models.Resource) { d.URL = s.URL }},
+	{"description", func(r models.Resource) any { return r.Description }, func(d *models.Resource, s models.Resource) { d.Description = s.Description }},
+}
+
+// equal indica si a y b coinciden en todos los campos fusionables
+func equal[T any](fields []field[T], a, b T) bool {
+	for _, f := range fields {
+		if f.get(a) != f.get(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// merge3 fusiona campo a campo partiendo de la versión del fork. Sin base
+// común, cualquier diferencia es un conflicto.
+func merge3[T any](fields []field[T], base *T, upstream, fork T) (merged T, changed bool, conflicts []string) {
+	merged = fork
+	for _, f := range fields {
+		u, v := f.get(upstream), f.get(fork)
+		if u == v {
+			continue
+		}
+		if base != nil {
+			b := f.get(*base)
+			if b == u {
+				continue // solo cambió el fork
+			}
+			if b == v {
+				f.set(&merged, upstream) // solo cambió el original
+				changed = true
+				continue
+			}
+		}
+		conflicts = append(conflicts, f.name)
+	}
+	return merged, changed, conflicts
+}
+
+// takeUpstream copia en merged los campos indicados de la versión del original
+func takeUpstream[T any](fields []field[T], names []string, merged *T, upstream T) {
+	for _, f := range fields {
+		for _, name := range names {
+			if f.name == name {
+				f.set(merged, upstream)
+			}
+		}
+	}
+}
+
+// kind describe cómo fusionar un tipo de elemento
+type kind[T any] struct {
+	kind                 Kind
+	fields               []field[T]
+	base, upstream, fork map[models.ID]T
+	newBase              map[models.ID]T
+	ids                  map[models.ID]models.ID
+	changes              *[]Change
+	// attach añade el elemento al cambio
+	attach func(*Change, T)
+	// blocked indica por qué el elemento no puede existir en el fork
+	blocked func(key models.ID, el T) Reason
+	// gone indica si el elemento del fork desaparecerá con el nodo del que depende
+	gone func(el T) bool
+	// dependents indica si el fork tiene cambios propios que dependen del elemento
+	dependents func(key models.ID) bool
+	// applied se llama tras decidir crear o eliminar el elemento
+	applied func(op Op, key models.ID, el T)
+}
+
+func mergeKind[T any](m *merger, k kind[T]) {
+	// Primero los elementos que el original eliminó, para que una conexión
+	// nueva no choque con la que sustituye entre los mismos nodos
+	keys := sortedKeys(k.base, k.upstream, k.fork)
+	sort.SliceStable(keys, func(i, j int) bool {
+		_, ui := k.upstream[keys[i]]
+		_, uj := k.upstream[keys[j]]
+		return !ui && uj
+	})
+
+	for _, key := range keys {
+		b, hasB := k.base[key]
+		u, hasU := k.upstream[key]
+		f, hasF := k.fork[key]
+		var base *T
+		if hasB {
+			base = &b
+		}
+
+		switch {
+		case hasF && k.gone(f):
+			// Se eliminará en cascada con su nodo
+			if hasU {
+				k.newBase[key] = u
+			}
+
+		case !hasU && !hasF, !hasB && !hasU:
+			// Eliminado en ambos lados, o propio del fork
+
+		case !hasU:
+			// El original lo eliminó
+			choice, resolved := m.resolve(k.kind, key)
+			switch {
+			case equal(k.fields, b, f) && !k.dependents(key), resolved && choice == ChoiceUpstream:
+				remove(m, k, key, f)
+			case !resolved:
+				m.conflict(k.kind, key, ReasonUpstreamDeleted, nil, b, nil, f)
+				k.newBase[key] = b
+			}
+
+		case !hasF:
+			// El original lo añadió, o el fork lo eliminó
+			if hasB && equal(k.fields, b, u) {
+				k.newBase[key] = u
+				continue
+			}
+
+			reason := k.blocked(key, u)
+			if !hasB && reason == "" {
+				add(k, key, u)
+				k.newBase[key] = u
+				continue
+			}
+			if hasB && reason == "" {
+				reason = ReasonForkDeleted
+			}
+
+			choice, resolved := m.resolve(k.kind, key)
+			switch {
+			case !resolved:
+				m.conflict(k.kind, key, reason, nil, maybe(base), u, nil)
+				if hasB {
+					k.newBase[key] = b
+				}
+			case choice == ChoiceUpstream && k.blocked(key, u) == "":
+				add(k, key, u)
+				k.newBase[key] = u
+			default:
+				k.newBase[key] = u
+			}
+
+		default:
+			// Ambos lo tienen: fusión campo a campo
+			merged, changed, fields := merge3(k.fields, base, u, f)
+			if len(fields) > 0 {
+				choice, resolved := m.resolve(k.kind, key)
+				if !resolved {
+					m.conflict(k.kind, key, ReasonBothModified, fields, maybe(base), u, f)
+				} else if choice == ChoiceUpstream {
+					takeUpstream(k.fields, fields, &merged, u)
+					changed = true
+				}
+				if !resolved && hasB {
+					k.newBase[key] = b
+				} else if resolved {
+					k.newBase[key] = u
+				}
+			} else {
+				k.newBase[key] = u
+			}
+
+			if !changed {
+				continue
+			}
+			if reason := k.blocked(key, merged); reason != "" {
+				if _, resolved := m.resolve(k.kind, key); !resolved {
+					m.conflict(k.kind, key, reason, nil, maybe(base), u, f)
+					if hasB {
+						k.newBase[key] = b
+					}
+				}
+				continue
+			}
+			change := Change{Kind: k.kind, Op: OpUpdate, ID: k.ids[key], Key: key}
+			k.attach(&change, merged)
+			*k.changes = append(*k.changes, change)
+			k.applied(OpUpdate, key, merged)
+		}
+	}
+}
+
+// maybe convierte un puntero nil en un any nil para que no aparezca en el JSON
+func maybe[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// add registra la creación en el fork de un elemento del original
+func add[T any](k kind[T], key models.ID, el T) {
+	change := Change{Kind: k.kind, Op: OpCreate, Key: key}
+	k.attach(&change, el)
+	*k.changes = append(*k.changes, change)
+	k.applied(OpCreate, key, el)
+}
+
+// remove registra la eliminación de un elemento del fork
+func remove[T any](m *merger, k kind[T], key models.ID, el T) {
+	m.deletes = append(m.deletes, Change{Kind: k.kind, Op: OpDelete, ID: k.ids[key], Key: key})
+	k.applied(OpDelete, key, el)
+}
+
+// optional devuelve un campo opcional del nodo como valor comparable, o nil
+// si no tiene
+func optional[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,375 @@
+// Package merge implementa la fusión a tres bandas entre un fork y el roadmap
+// original. Los elementos del fork se relacionan con los del original por su
+// OriginID; todas las comparaciones se hacen en el espacio de ids del original
+// (las "claves"), en el que los elementos propios del fork conservan su id.
+package merge
+
+import (
+	"sort"
+
+	"Gin/internal/models"
+)
+
+// Kind es el tipo de elemento afectado por un cambio o conflicto
+type Kind string
+
+const (
+	KindNode       Kind = "node"
+	KindConnection Kind = "connection"
+	KindResource   Kind = "resource"
+	KindRoadmap    Kind = "roadmap" // datos generales; solo aparece en DiffDetails
+)
+
+// Op es la operación que hay que aplicar sobre el fork
+type Op string
+
+const (
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+// Reason explica por qué un elemento no se pudo fusionar automáticamente
+type Reason string
+
+const (
+	// ReasonBothModified: ambos cambiaron los mismos campos de forma distinta
+	ReasonBothModified Reason = "both_modified"
+	// ReasonUpstreamDeleted: el original eliminó un elemento que el fork modificó
+	ReasonUpstreamDeleted Reason = "upstream_deleted"
+	// ReasonForkDeleted: el fork eliminó un elemento que el original modificó
+	ReasonForkDeleted Reason = "fork_deleted"
+	// ReasonMissingNode: el original añadió o modificó un elemento que
+	// depende de un nodo que ya no existe en el fork
+	ReasonMissingNode Reason = "missing_node"
+	// ReasonDuplicate: el original añadió una conexión entre dos nodos que
+	// el fork ya conecta
+	ReasonDuplicate Reason = "duplicate_connection"
+)
+
+// Choice es la resolución elegida para un conflicto
+type Choice string
+
+const (
+	ChoiceUpstream Choice = "upstream"
+	ChoiceFork     Choice = "fork"
+)
+
+// Valid indica si la resolución es una de las conocidas
+func (c Choice) Valid() bool {
+	return c == ChoiceUpstream || c == ChoiceFork
+}
+
+// Change es una operación a aplicar sobre el fork. ID es el id del elemento en
+// el fork (vacío en las creaciones) y Key su clave; las referencias a nodos
+// del elemento están expresadas en claves.
+type Change struct {
+	Kind       Kind               `json:"kind"`
+	Op         Op                 `json:"op"`
+	ID         models.ID          `json:"id,omitempty"`
+	Key        models.ID          `json:"key"`
+	Node       *models.Node       `json:"node,omitempty"`
+	Connection *models.Connection `json:"connection,omitempty"`
+	Resource   *models.Resource   `json:"resource,omitempty"`
+}
+
+// Conflict es un elemento que requiere que el propietario del fork elija
+// entre la versión del original y la suya
+type Conflict struct {
+	ID       string    `json:"id"`
+	Kind     Kind      `json:"kind"`
+	Key      models.ID `json:"key"`
+	Reason   Reason    `json:"reason"`
+	Fields   []string  `json:"fields,omitempty"`
+	Base     any       `json:"base,omitempty"`
+	Upstream any       `json:"upstream,omitempty"`
+	Fork     any       `json:"fork,omitempty"`
+}
+
+// Result es el resultado de una fusión
+type Result struct {
+	// Changes son las operaciones a aplicar sobre el fork, en orden
+	Changes []Change `json:"changes"`
+	// Conflicts son los conflictos que siguen sin resolver
+	Conflicts []Conflict `json:"conflicts"`
+	// Base es la nueva base común: el original en los elementos fusionados y
+	// la base anterior en los que siguen en conflicto
+	Base models.Snapshot `json:"-"`
+	// NodeIDs relaciona las claves de los nodos existentes en el fork con su id
+	NodeIDs map[models.ID]models.ID `json:"-"`
+}
+
+// conflictID identifica un conflicto de forma estable entre fusiones
+func conflictID(kind Kind, key models.ID) string {
+	return string(kind) + ":" + key.String()
+}
+
+// Merge fusiona en fork los cambios hechos en upstream desde base. Los
+// conflictos incluidos en resolutions se resuelven con la opción indicada;
+// el resto se devuelven en Result.Conflicts y su base no avanza, de modo que
+// volverán a aparecer en la siguiente fusión.
+func Merge(base, upstream, fork models.Snapshot, resolutions map[string]Choice) Result {
+	m := newMerger(base, upstream, fork, resolutions)
+	m.mergeNodes()
+	m.mergeConnections()
+	m.mergeResources()
+	return m.result()
+}
+
+// set agrupa los elementos de un snapshot por clave
+type set struct {
+	nodes       map[models.ID]models.Node
+	connections map[models.ID]models.Connection
+	resources   map[models.ID]models.Resource
+}
+
+func newSet(s models.Snapshot) set {
+	out := set{
+		nodes:       make(map[models.ID]models.Node, len(s.Nodes)),
+		connections: make(map[models.ID]models.Connection, len(s.Connections)),
+		resources:   make(map[models.ID]models.Resource, len(s.Resources)),
+	}
+	for _, n := range s.Nodes {
+		out.nodes[n.ID] = n
+	}
+	for _, c := range s.Connections {
+		out.connections[c.ID] = c
+	}
+	for _, r := range s.Resources {
+		out.resources[r.ID] = r
+	}
+	return out
+}
+
+type merger struct {
+	base, upstream, fork set
+	resolutions          map[string]Choice
+
+	// ids de los elementos del fork por clave
+	nodeIDs, connIDs, resourceIDs map[models.ID]models.ID
+
+	// nodos que existirán en el fork tras la fusión
+	finalNodes map[models.ID]bool
+
+	nodeChanges, connChanges, resourceChanges []Change
+	deletes                                   []Change
+	conflicts                                 []Conflict
+	newBase                                   set
+}
+
+func newMerger(base, upstream, fork models.Snapshot, resolutions map[string]Choice) *merger {
+	m := &merger{
+		base:        newSet(base),
+		upstream:    newSet(upstream),
+		resolutions: resolutions,
+		nodeIDs:     make(map[models.ID]models.ID),
+		connIDs:     make(map[models.ID]models.ID),
+		resourceIDs: make(map[models.ID]models.ID),
+		finalNodes:  make(map[models.ID]bool),
+		newBase:     newSet(models.Snapshot{}),
+	}
+
+	// Traducir el fork al espacio de claves
+	key := func(id models.ID, origin *models.ID) models.ID {
+		if origin != nil {
+			return *origin
+		}
+		return id
+	}
+	var keyed models.Snapshot
+	for _, n := range fork.Nodes {
+		k := key(n.ID, n.OriginID)
+		m.nodeIDs[k] = n.ID
+		n.ID = k
+		keyed.Nodes = append(keyed.Nodes, n)
+	}
+	nodeKey := make(map[models.ID]models.ID, len(m.nodeIDs))
+	for k, id := range m.nodeIDs {
+		nodeKey[id] = k
+	}
+	for _, c := range fork.Connections {
+		k := key(c.ID, c.OriginID)
+		m.connIDs[k] = c.ID
+		c.ID, c.FromNodeID, c.ToNodeID = k, nodeKey[c.FromNodeID], nodeKey[c.ToNodeID]
+		keyed.Connections = append(keyed.Connections, c)
+	}
+	for _, r := range fork.Resources {
+		k := key(r.ID, r.OriginID)
+		m.resourceIDs[k] = r.ID
+		r.ID, r.NodeID = k, nodeKey[r.NodeID]
+		keyed.Resources = append(keyed.Resources, r)
+	}
+	m.fork = newSet(keyed)
+
+	for k := range m.fork.nodes {
+		m.finalNodes[k] = true
+	}
+	return m
+}
+
+// sortedKeys devuelve la unión ordenada de las claves de los mapas
+func sortedKeys[T any](sets ...map[models.ID]T) []models.ID {
+	seen := make(map[models.ID]bool)
+	var keys []models.ID
+	for _, s := range sets {
+		for k := range s {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// resolve devuelve la resolución indicada para el conflicto, si la hay
+func (m *merger) resolve(kind Kind, key models.ID) (Choice, bool) {
+	choice, ok := m.resolutions[conflictID(kind, key)]
+	return choice, ok && choice.Valid()
+}
+
+func (m *merger) conflict(kind Kind, key models.ID, reason Reason, fields []string, base, upstream, fork any) {
+	m.conflicts = append(m.conflicts, Conflict{
+		ID:       conflictID(kind, key),
+		Kind:     kind,
+		Key:      key,
+		Reason:   reason,
+		Fields:   fields,
+		Base:     base,
+		Upstream: upstream,
+		Fork:     fork,
+	})
+}
+
+func (m *merger) result() Result {
+	var base models.Snapshot
+	for _, k := range sortedKeys(m.newBase.nodes) {
+		base.Nodes = append(base.Nodes, m.newBase.nodes[k])
+	}
+	for _, k := range sortedKeys(m.newBase.connections) {
+		base.Connections = append(base.Connections, m.newBase.connections[k])
+	}
+	for _, k := range sortedKeys(m.newBase.resources) {
+		base.Resources = append(base.Resources, m.newBase.resources[k])
+	}
+
+	// Primero las eliminaciones, para no chocar con las conexiones únicas
+	changes := append([]Change{}, m.deletes...)
+	changes = append(changes, m.nodeChanges...)
+	changes = append(changes, m.connChanges...)
+	changes = append(changes, m.resourceChanges...)
+
+	conflicts := m.conflicts
+	if conflicts == nil {
+		conflicts = []Conflict{}
+	}
+
+	return Result{Changes: changes, Conflicts: conflicts, Base: base, NodeIDs: m.nodeIDs}
+}
+
+func (m *merger) mergeNodes() {
+	mergeKind(m, kind[models.Node]{
+		kind:     KindNode,
+		fields:   nodeFields,
+		base:     m.base.nodes,
+		upstream: m.upstream.nodes,
+		fork:     m.fork.nodes,
+		newBase:  m.newBase.nodes,
+		ids:      m.nodeIDs,
+		changes:  &m.nodeChanges,
+		attach:   func(c *Change, n models.Node) { c.Node = &n },
+		blocked:  func(models.ID, models.Node) Reason { return "" },
+		gone:     func(models.Node) bool { return false },
+		// Un nodo que el original eliminó solo se elimina del fork si nada
+		// de lo que el fork añadió o modificó depende de él
+		dependents: func(key models.ID) bool {
+			for k, c := range m.fork.connections {
+				if c.FromNodeID == key || c.ToNodeID == key {
+					b, ok := m.base.connections[k]
+					if !ok || !equal(connectionFields, b, c) {
+						return true
+					}
+				}
+			}
+			for k, r := range m.fork.resources {
+				if r.NodeID == key {
+					b, ok := m.base.resources[k]
+					if !ok || !equal(resourceFields, b, r) {
+						return true
+					}
+				}
+			}
+			return false
+		},
+		applied: func(op Op, key models.ID, _ models.Node) {
+			m.finalNodes[key] = op != OpDelete
+		},
+	})
+}
+
+func (m *merger) mergeConnections() {
+	// Pares de nodos conectados en el fork, para no duplicar conexiones
+	edges := make(map[[2]models.ID]models.ID, len(m.fork.connections))
+	for k, c := range m.fork.connections {
+		edges[[2]models.ID{c.FromNodeID, c.ToNodeID}] = k
+	}
+
+	mergeKind(m, kind[models.Connection]{
+		kind:     KindConnection,
+		fields:   connectionFields,
+		base:     m.base.connections,
+		upstream: m.upstream.connections,
+		fork:     m.fork.connections,
+		newBase:  m.newBase.connections,
+		ids:      m.connIDs,
+		changes:  &m.connChanges,
+		attach:   func(ch *Change, c models.Connection) { ch.Connection = &c },
+		blocked: func(key models.ID, c models.Connection) Reason {
+			if !m.finalNodes[c.FromNodeID] || !m.finalNodes[c.ToNodeID] {
+				return ReasonMissingNode
+			}
+			if other, ok := edges[[2]models.ID{c.FromNodeID, c.ToNodeID}]; ok && other != key {
+				return ReasonDuplicate
+			}
+			return ""
+		},
+		gone: func(c models.Connection) bool {
+			return !m.finalNodes[c.FromNodeID] || !m.finalNodes[c.ToNodeID]
+		},
+		dependents: func(models.ID) bool { return false },
+		applied: func(op Op, key models.ID, c models.Connection) {
+			for pair, k := range edges {
+				if k == key {
+					delete(edges, pair)
+				}
+			}
+			if op != OpDelete {
+				edges[[2]models.ID{c.FromNodeID, c.ToNodeID}] = key
+			}
+		},
+	})
+}
+
+func (m *merger) mergeResources() {
+	mergeKind(m, kind[models.Resource]{
+		kind:     KindResource,
+		fields:   resourceFields,
+		base:     m.base.resources,
+		upstream: m.upstream.resources,
+		fork:     m.fork.resources,
+		newBase:  m.newBase.resources,
+		ids:      m.resourceIDs,
+		changes:  &m.resourceChanges,
+		attach:   func(c *Change, r models.Resource) { c.Resource = &r },
+		blocked: func(_ models.ID, r models.Resource) Reason {
+			if !m.finalNodes[r.NodeID] {
+				return ReasonMissingNode
+			}
+			return ""
+		},
+		gone:       func(r models.Resource) bool { return !m.finalNodes[r.NodeID] },
+		dependents: func(models.ID) bool { return false },
+		applied:    func(Op, models.ID, models.Resource) {},
+	})
+}
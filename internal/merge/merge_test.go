@@ -0,0 +1,232 @@
+package merge
+
+import (
+	"testing"
+
+	"Gin/internal/models"
+)
+
+// original devuelve un roadmap con dos nodos conectados y un recurso
+func original() models.Snapshot {
+	a := models.Node{ID: models.NewID(), Title: "A", Type: models.NodeTypeTopic, Color: "#4F46E5", Status: "not_started"}
+	b := models.Node{ID: models.NewID(), Title: "B", Type: models.NodeTypeTopic, Color: "#4F46E5", Status: "not_started",
+		Position: models.Position{X: 0, Y: 200}}
+	return models.Snapshot{
+		Nodes:       []models.Node{a, b},
+		Connections: []models.Connection{{ID: models.NewID(), FromNodeID: a.ID, ToNodeID: b.ID, ConnectionType: models.ConnectionTypeDefault}},
+		Resources:   []models.Resource{{ID: models.NewID(), NodeID: a.ID, Title: "Docs", Type: "link", URL: "https://go.dev"}},
+	}
+}
+
+// forkOf copia el contenido con ids nuevos y el origen de cada elemento, como
+// al hacer fork
+func forkOf(s models.Snapshot) models.Snapshot {
+	var fork models.Snapshot
+	ids := make(map[models.ID]models.ID)
+	for _, n := range s.Nodes {
+		origin := n.ID
+		n.ID, n.OriginID = models.NewID(), &origin
+		ids[origin] = n.ID
+		fork.Nodes = append(fork.Nodes, n)
+	}
+	for _, c := range s.Connections {
+		origin := c.ID
+		c.ID, c.OriginID = models.NewID(), &origin
+		c.FromNodeID, c.ToNodeID = ids[c.FromNodeID], ids[c.ToNodeID]
+		fork.Connections = append(fork.Connections, c)
+	}
+	for _, r := range s.Resources {
+		origin := r.ID
+		r.ID, r.OriginID = models.NewID(), &origin
+		r.NodeID = ids[r.NodeID]
+		fork.Resources = append(fork.Resources, r)
+	}
+	return fork
+}
+
+// clone copia el contenido para modificarlo sin tocar el original
+func clone(s models.Snapshot) models.Snapshot {
+	return models.Snapshot{
+		Nodes:       append([]models.Node(nil), s.Nodes...),
+		Connections: append([]models.Connection(nil), s.Connections...),
+		Resources:   append([]models.Resource(nil), s.Resources...),
+	}
+}
+
+func TestMergeWithoutChanges(t *testing.T) {
+	base := original()
+	result := Merge(base, base, forkOf(base), nil)
+	if len(result.Changes) != 0 || len(result.Conflicts) != 0 {
+		t.Fatalf("resultado = %+v", result)
+	}
+	if len(result.Base.Nodes) != 2 || len(result.Base.Connections) != 1 || len(result.Base.Resources) != 1 {
+		t.Errorf("base = %+v", result.Base)
+	}
+}
+
+func TestMergeFields(t *testing.T) {
+	base := original()
+	upstream, fork := clone(base), forkOf(base)
+	upstream.Nodes[0].Title = "A del original"
+	fork.Nodes[0].Color = "#10B981"
+
+	result := Merge(base, upstream, fork, nil)
+	if len(result.Conflicts) != 0 || len(result.Changes) != 1 {
+		t.Fatalf("resultado = %+v", result)
+	}
+	change := result.Changes[0]
+	if change.Op != OpUpdate || change.ID != fork.Nodes[0].ID || change.Key != base.Nodes[0].ID {
+		t.Errorf("cambio = %+v", change)
+	}
+	if change.Node.Title != "A del original" || change.Node.Color != "#10B981" {
+		t.Errorf("nodo fusionado = %+v", change.Node)
+	}
+	if result.Base.Nodes[0].Title != "A del original" && result.Base.Nodes[1].Title != "A del original" {
+		t.Errorf("la base no avanza: %+v", result.Base.Nodes)
+	}
+}
+
+func TestMergeConflictResolutions(t *testing.T) {
+	base := original()
+	upstream, fork := clone(base), forkOf(base)
+	upstream.Nodes[1].Title = "B del original"
+	fork.Nodes[1].Title = "B del fork"
+	key := base.Nodes[1].ID
+
+	tests := []struct {
+		name        string
+		resolutions map[string]Choice
+		conflicts   int
+		title       string // título aplicado al fork; vacío si no hay cambios
+	}{
+		{"sin resolver", nil, 1, ""},
+		{"original", map[string]Choice{conflictID(KindNode, key): ChoiceUpstream}, 0, "B del original"},
+		{"fork", map[string]Choice{conflictID(KindNode, key): ChoiceFork}, 0, ""},
+		{"resolución desconocida", map[string]Choice{conflictID(KindNode, key): "otra"}, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Merge(base, upstream, fork, tt.resolutions)
+			if len(result.Conflicts) != tt.conflicts {
+				t.Fatalf("conflictos = %+v", result.Conflicts)
+			}
+			if tt.conflicts > 0 {
+				conflict := result.Conflicts[0]
+				if conflict.Reason != ReasonBothModified || len(conflict.Fields) != 1 || conflict.Fields[0] != "title" {
+					t.Errorf("conflicto = %+v", conflict)
+				}
+			}
+			var title string
+			if len(result.Changes) > 0 {
+				title = result.Changes[0].Node.Title
+			}
+			if title != tt.title {
+				t.Errorf("título aplicado = %q, se esperaba %q", title, tt.title)
+			}
+		})
+	}
+}
+
+func TestMergeCreatesAndDeletes(t *testing.T) {
+	base := original()
+	upstream, fork := clone(base), forkOf(base)
+
+	// El original añade un nodo conectado y elimina el recurso
+	c := models.Node{ID: models.NewID(), Title: "C", Type: models.NodeTypeTopic}
+	upstream.Nodes = append(upstream.Nodes, c)
+	upstream.Connections = append(upstream.Connections, models.Connection{ID: models.NewID(), FromNodeID: base.Nodes[1].ID, ToNodeID: c.ID})
+	upstream.Resources = nil
+
+	// El fork añade un nodo propio, que se conserva
+	fork.Nodes = append(fork.Nodes, models.Node{ID: models.NewID(), Title: "Propio"})
+
+	result := Merge(base, upstream, fork, nil)
+	if len(result.Conflicts) != 0 {
+		t.Fatalf("conflictos = %+v", result.Conflicts)
+	}
+	want := []struct {
+		kind Kind
+		op   Op
+	}{{KindResource, OpDelete}, {KindNode, OpCreate}, {KindConnection, OpCreate}}
+	if len(result.Changes) != len(want) {
+		t.Fatalf("cambios = %+v", result.Changes)
+	}
+	for i, change := range result.Changes {
+		if change.Kind != want[i].kind || change.Op != want[i].op {
+			t.Errorf("cambio %d = %s %s, se esperaba %s %s", i, change.Op, change.Kind, want[i].op, want[i].kind)
+		}
+	}
+	if id := result.Changes[0].ID; id != fork.Resources[0].ID {
+		t.Errorf("se elimina %s, se esperaba el recurso del fork %s", id, fork.Resources[0].ID)
+	}
+	if conn := result.Changes[2].Connection; conn.FromNodeID != base.Nodes[1].ID || conn.ToNodeID != c.ID {
+		t.Errorf("la conexión creada no usa las claves de los nodos: %+v", conn)
+	}
+	if result.NodeIDs[base.Nodes[1].ID] != fork.Nodes[1].ID {
+		t.Errorf("NodeIDs = %v", result.NodeIDs)
+	}
+}
+
+func TestMergeConflictReasons(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(base, upstream, fork *models.Snapshot)
+		kind   Kind
+		reason Reason
+	}{
+		{"el original elimina lo que el fork modificó", func(base, upstream, fork *models.Snapshot) {
+			upstream.Resources = nil
+			fork.Resources[0].Title = "Documentación"
+		}, KindResource, ReasonUpstreamDeleted},
+		{"el fork elimina lo que el original modificó", func(base, upstream, fork *models.Snapshot) {
+			upstream.Resources[0].URL = "https://go.dev/doc"
+			fork.Resources = nil
+		}, KindResource, ReasonForkDeleted},
+		{"recurso nuevo en un nodo eliminado del fork", func(base, upstream, fork *models.Snapshot) {
+			upstream.Resources = append(upstream.Resources, models.Resource{ID: models.NewID(), NodeID: base.Nodes[1].ID, Title: "Nuevo", URL: "https://go.dev/blog"})
+			fork.Nodes = fork.Nodes[:1]
+			fork.Connections = nil
+		}, KindResource, ReasonMissingNode},
+		{"conexión que el fork ya tiene", func(base, upstream, fork *models.Snapshot) {
+			upstream.Connections = append(upstream.Connections, models.Connection{ID: models.NewID(), FromNodeID: base.Nodes[1].ID, ToNodeID: base.Nodes[0].ID})
+			fork.Connections = append(fork.Connections, models.Connection{ID: models.NewID(), FromNodeID: fork.Nodes[1].ID, ToNodeID: fork.Nodes[0].ID})
+		}, KindConnection, ReasonDuplicate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := original()
+			upstream, fork := clone(base), forkOf(base)
+			tt.change(&base, &upstream, &fork)
+
+			result := Merge(base, upstream, fork, nil)
+			if len(result.Conflicts) != 1 {
+				t.Fatalf("conflictos = %+v, cambios = %+v", result.Conflicts, result.Changes)
+			}
+			if conflict := result.Conflicts[0]; conflict.Kind != tt.kind || conflict.Reason != tt.reason {
+				t.Errorf("conflicto = %s %s, se esperaba %s %s", conflict.Kind, conflict.Reason, tt.kind, tt.reason)
+			}
+			if len(result.Changes) != 0 {
+				t.Errorf("cambios = %+v", result.Changes)
+			}
+		})
+	}
+}
+
+func TestMergeDeletesUnmodifiedNode(t *testing.T) {
+	base := original()
+	upstream, fork := clone(base), forkOf(base)
+	upstream.Nodes = upstream.Nodes[:1]
+	upstream.Connections = nil
+
+	result := Merge(base, upstream, fork, nil)
+	if len(result.Conflicts) != 0 {
+		t.Fatalf("conflictos = %+v", result.Conflicts)
+	}
+	// La conexión se elimina en cascada con su nodo
+	if len(result.Changes) != 1 || result.Changes[0].Op != OpDelete || result.Changes[0].ID != fork.Nodes[1].ID {
+		t.Errorf("cambios = %+v", result.Changes)
+	}
+	if len(result.Base.Nodes) != 1 || len(result.Base.Connections) != 0 {
+		t.Errorf("base = %+v", result.Base)
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"Gin/internal/models"
 	"Gin/internal/services"
 	"net/http"
 	"strings"
@@ -10,7 +11,7 @@ import (
 
 const (
 	AuthorizationHeader = "Authorization"
-	UserIDKey          = "user_id"
+	UserIDKey           = "user_id"
 )
 
 type AuthMiddleware struct {
@@ -65,13 +66,26 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	}
 }
 
+// OptionalAuth identifica al usuario si envía un token válido, sin exigirlo
+func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenParts := strings.Split(c.GetHeader(AuthorizationHeader), " ")
+		if len(tokenParts) == 2 && strings.ToLower(tokenParts[0]) == "bearer" {
+			if userID, err := m.jwtService.ValidateToken(tokenParts[1]); err == nil {
+				c.Set(UserIDKey, userID)
+			}
+		}
+		c.Next()
+	}
+}
+
 // GetUserID obtiene el ID del usuario del contexto
-func GetUserID(c *gin.Context) (int64, bool) {
+func GetUserID(c *gin.Context) (models.ID, bool) {
 	userID, exists := c.Get(UserIDKey)
 	if !exists {
-		return 0, false
+		return "", false
 	}
 
-	id, ok := userID.(int64)
+	id, ok := userID.(models.ID)
 	return id, ok
-}
\ No newline at end of file
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	OrganizationIDKey   = "organization_id"
+	OrganizationRoleKey = "organization_role"
+)
+
+// OrganizationRole devuelve el rol del usuario en la organización, o "" si no
+// es miembro
+func OrganizationRole(store repository.Store, organizationID, userID models.ID) (models.OrgRole, error) {
+	member, err := store.Organizations().GetMember(organizationID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// RequireOrganizationRole verifica que el usuario autenticado tiene al menos
+// el rol indicado en la organización de la ruta
+func RequireOrganizationRole(store repository.Store, role models.OrgRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := GetUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+			c.Abort()
+			return
+		}
+
+		organizationID, err := models.ParseID(c.Param("org_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID de organización inválido"})
+			c.Abort()
+			return
+		}
+
+		current, err := OrganizationRole(store, organizationID, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar permisos sobre la organización"})
+			c.Abort()
+			return
+		}
+		// Para quien no es miembro la organización no existe
+		if current == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Organización no encontrada"})
+			c.Abort()
+			return
+		}
+		if !current.Includes(role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "No tienes permiso para realizar esta acción en la organización"})
+			c.Abort()
+			return
+		}
+
+		c.Set(OrganizationIDKey, organizationID)
+		c.Set(OrganizationRoleKey, current)
+		c.Next()
+	}
+}
+
+// GetOrganizationID obtiene el ID de la organización validado por el middleware
+func GetOrganizationID(c *gin.Context) models.ID {
+	organizationID, _ := c.Get(OrganizationIDKey)
+	id, _ := organizationID.(models.ID)
+	return id
+}
+
+// GetOrganizationRole obtiene el rol del usuario validado por el middleware
+func GetOrganizationRole(c *gin.Context) models.OrgRole {
+	role, _ := c.Get(OrganizationRoleKey)
+	r, _ := role.(models.OrgRole)
+	return r
+}
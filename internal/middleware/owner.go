@@ -1,18 +1,67 @@
 package middleware
 
 import (
-	"database/sql"
+	"errors"
 	"net/http"
-	"strconv"
 
+	"Gin/internal/models"
+	"Gin/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
-// RequireRoadmapOwner verifica que el usuario autenticado es el propietario del roadmap
-func RequireRoadmapOwner(db *sql.DB) gin.HandlerFunc {
+const (
+	RoadmapIDKey   = "roadmap_id"
+	RoadmapRoleKey = "roadmap_role"
+)
+
+// RoadmapRole devuelve el rol del usuario en el roadmap: propietario si es su
+// autor o, si no, el mayor entre el de su invitación, si la aceptó, y el que le
+// da su organización; "" si no tiene ninguno
+func RoadmapRole(store repository.Store, roadmap *models.Roadmap, userID models.ID) (models.Role, error) {
+	if roadmap.AuthorID == userID {
+		return models.RoleOwner, nil
+	}
+
+	var role models.Role
+	collaborator, err := store.Collaborators().Get(roadmap.ID, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return "", err
+	}
+	if err == nil && collaborator.Status == models.CollaboratorStatusAccepted {
+		role = collaborator.Role
+	}
+
+	if roadmap.OrganizationID != nil {
+		orgRole, err := OrganizationRole(store, *roadmap.OrganizationID, userID)
+		if err != nil {
+			return "", err
+		}
+		if inherited := organizationRoadmapRole(orgRole); !role.Includes(inherited) {
+			role = inherited
+		}
+	}
+	return role, nil
+}
+
+// organizationRoadmapRole es el rol que da en los roadmaps de la organización
+// el rol en ella: los administradores los administran y el resto de miembros
+// puede verlos
+func organizationRoadmapRole(role models.OrgRole) models.Role {
+	switch {
+	case role.Includes(models.OrgRoleAdmin):
+		return models.RoleAdmin
+	case role.Includes(models.OrgRoleMember):
+		return models.RoleViewer
+	}
+	return ""
+}
+
+// RequireRoadmapRole verifica que el usuario autenticado tiene al menos el
+// rol indicado en el roadmap
+func RequireRoadmapRole(store repository.Store, role models.Role) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Obtener el ID del usuario autenticado del contexto
-		userID, exists := c.Get("user_id")
+		userID, exists := GetUserID(c)
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
 			c.Abort()
@@ -20,34 +69,63 @@ func RequireRoadmapOwner(db *sql.DB) gin.HandlerFunc {
 		}
 
 		// Obtener el ID del roadmap de los parámetros
-		roadmapID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		roadmapID, err := models.ParseID(c.Param("id"))
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "ID de roadmap inválido"})
 			c.Abort()
 			return
 		}
 
-		// Verificar si el usuario es el propietario del roadmap
-		var authorID int64
-		err = db.QueryRow("SELECT author_id FROM roadmaps WHERE id = $1", roadmapID).Scan(&authorID)
+		roadmap, err := store.Roadmaps().GetByID(roadmapID)
 		if err != nil {
-			if err == sql.ErrNoRows {
+			if errors.Is(err, repository.ErrNotFound) {
 				c.JSON(http.StatusNotFound, gin.H{"error": "Roadmap no encontrado"})
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar propiedad del roadmap"})
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar permisos sobre el roadmap"})
 			}
 			c.Abort()
 			return
 		}
 
-		if authorID != userID.(int64) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "No tienes permiso para modificar este roadmap"})
+		// Verificar el rol del usuario en el roadmap
+		current, err := RoadmapRole(store, roadmap, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar permisos sobre el roadmap"})
+			c.Abort()
+			return
+		}
+		if !current.Includes(role) {
+			message := "No tienes permiso para realizar esta acción en el roadmap"
+			if role == models.RoleOwner {
+				message = "No tienes permiso para modificar este roadmap"
+			}
+			c.JSON(http.StatusForbidden, gin.H{"error": message})
 			c.Abort()
 			return
 		}
 
-		// Almacenar el ID del roadmap en el contexto para uso posterior
-		c.Set("roadmap_id", roadmapID)
+		// Almacenar el ID del roadmap y el rol en el contexto para uso posterior
+		c.Set(RoadmapIDKey, roadmapID)
+		c.Set(RoadmapRoleKey, current)
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// RequireRoadmapOwner verifica que el usuario autenticado es el propietario del roadmap
+func RequireRoadmapOwner(store repository.Store) gin.HandlerFunc {
+	return RequireRoadmapRole(store, models.RoleOwner)
+}
+
+// GetRoadmapID obtiene el ID del roadmap validado por el middleware
+func GetRoadmapID(c *gin.Context) models.ID {
+	roadmapID, _ := c.Get(RoadmapIDKey)
+	id, _ := roadmapID.(models.ID)
+	return id
+}
+
+// GetRoadmapRole obtiene el rol del usuario validado por el middleware
+func GetRoadmapRole(c *gin.Context) models.Role {
+	role, _ := c.Get(RoadmapRoleKey)
+	r, _ := role.(models.Role)
+	return r
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// Assignment asigna un roadmap, o parte de sus nodos, a un grupo de usuarios
+// con una fecha límite
+type Assignment struct {
+	ID          ID        `json:"id"`
+	RoadmapID   ID        `json:"roadmap_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	AllNodes    bool      `json:"all_nodes"` // el roadmap completo, incluidos los nodos que se añadan después
+	NodeIDs     []ID      `json:"node_ids"`  // nodos asignados si AllNodes es falso
+	DueAt       time.Time `json:"due_at"`
+	CreatedBy   ID        `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AssigneeStatus es el estado de un asignado respecto a su asignación
+type AssigneeStatus string
+
+const (
+	AssigneeStatusNotStarted AssigneeStatus = "not_started"
+	AssigneeStatusInProgress AssigneeStatus = "in_progress"
+	AssigneeStatusCompleted  AssigneeStatus = "completed"
+	// AssigneeStatusOverdue indica que pasó la fecha límite sin completarla
+	AssigneeStatusOverdue AssigneeStatus = "overdue"
+)
+
+// Assignee es un usuario asignado junto con su progreso en los nodos de la
+// asignación. Completion es el porcentaje de nodos completados y CompletedAt
+// la fecha en que completó el último.
+type Assignee struct {
+	AssignmentID   ID             `json:"assignment_id"`
+	UserID         ID             `json:"user_id"`
+	Username       string         `json:"username"`
+	AssignedAt     time.Time      `json:"assigned_at"`
+	TotalNodes     int            `json:"total_nodes"`
+	Completed      int            `json:"completed"`
+	InProgress     int            `json:"in_progress"`
+	Completion     int            `json:"completion"`
+	Status         AssigneeStatus `json:"status"`
+	Overdue        bool           `json:"overdue"`
+	CompletedAt    *time.Time     `json:"completed_at,omitempty"`
+	LastActivityAt *time.Time     `json:"last_activity_at,omitempty"`
+}
+
+// Evaluate calcula el porcentaje completado y el estado del asignado a partir
+// de su progreso, teniendo en cuenta la fecha límite
+func (a *Assignee) Evaluate(dueAt, at time.Time) {
+	a.Completion = 0
+	if a.TotalNodes > 0 {
+		a.Completion = a.Completed * 100 / a.TotalNodes
+	}
+
+	done := a.TotalNodes > 0 && a.Completed >= a.TotalNodes
+	if !done {
+		a.CompletedAt = nil
+	}
+	a.Overdue = !done && at.After(dueAt)
+
+	switch {
+	case done:
+		a.Status = AssigneeStatusCompleted
+	case a.Overdue:
+		a.Status = AssigneeStatusOverdue
+	case a.Completed > 0 || a.InProgress > 0:
+		a.Status = AssigneeStatusInProgress
+	default:
+		a.Status = AssigneeStatusNotStarted
+	}
+}
+
+// AssignmentSummary es una asignación con el recuento de sus asignados por
+// estado
+type AssignmentSummary struct {
+	Assignment
+	Assignees     int `json:"assignees"`
+	Completed     int `json:"completed"`
+	Overdue       int `json:"overdue"`
+	AvgCompletion int `json:"avg_completion"`
+}
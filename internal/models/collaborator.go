@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Role es el nivel de acceso de un usuario a un roadmap. Cada rol incluye
+// los permisos de los anteriores.
+type Role string
+
+const (
+	// RoleViewer puede ver el roadmap aunque sea privado, su historial y
+	// quién lo está editando
+	RoleViewer Role = "viewer"
+	// RoleCommenter además puede ver las propuestas recibidas
+	RoleCommenter Role = "commenter"
+	// RoleEditor además puede modificar el contenido del roadmap
+	RoleEditor Role = "editor"
+	// RoleAdmin además puede revisar propuestas y gestionar colaboradores
+	RoleAdmin Role = "admin"
+	// RoleOwner es el autor del roadmap; no se puede asignar a colaboradores
+	RoleOwner Role = "owner"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer:    1,
+	RoleCommenter: 2,
+	RoleEditor:    3,
+	RoleAdmin:     4,
+	RoleOwner:     5,
+}
+
+// Valid indica si el rol se puede asignar a un colaborador
+func (r Role) Valid() bool {
+	return r != RoleOwner && roleRanks[r] > 0
+}
+
+// Includes indica si el rol tiene al menos los permisos de other
+func (r Role) Includes(other Role) bool {
+	return roleRanks[r] > 0 && roleRanks[r] >= roleRanks[other]
+}
+
+// CollaboratorStatus es el estado de una invitación a colaborar
+type CollaboratorStatus string
+
+const (
+	CollaboratorStatusPending  CollaboratorStatus = "pending"
+	CollaboratorStatusAccepted CollaboratorStatus = "accepted"
+)
+
+// Collaborator es un usuario invitado a colaborar en un roadmap. Su rol solo
+// tiene efecto cuando acepta la invitación.
+type Collaborator struct {
+	ID         ID                 `json:"id"`
+	RoadmapID  ID                 `json:"roadmap_id"`
+	UserID     ID                 `json:"user_id"`
+	Username   string             `json:"username"`
+	Role       Role               `json:"role"`
+	Status     CollaboratorStatus `json:"status"`
+	InvitedBy  ID                 `json:"invited_by,omitempty"`
+	AcceptedAt *time.Time         `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidID = errors.New("identificador inválido")
+
+// ID identifica cualquier entidad del sistema. Es un UUID en su forma
+// canónica (8-4-4-4-12 dígitos hexadecimales en minúscula), igual que las
+// claves primarias del esquema.
+type ID string
+
+// NewID genera un nuevo UUID versión 4
+func NewID() ID {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("no se pudo generar un ID: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return ID(buf[:])
+}
+
+// ParseID valida un identificador recibido del exterior y lo normaliza
+func ParseID(s string) (ID, error) {
+	if len(s) != 36 {
+		return "", ErrInvalidID
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return "", ErrInvalidID
+			}
+		default:
+			if !isHex(c) {
+				return "", ErrInvalidID
+			}
+		}
+	}
+	return ID(strings.ToLower(s)), nil
+}
+
+func isHex(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+// String devuelve la representación textual del ID
+func (id ID) String() string {
+	return string(id)
+}
+
+// IsZero indica si el ID está vacío
+func (id ID) IsZero() bool {
+	return id == ""
+}
+
+// Value implementa driver.Valuer; un ID vacío se guarda como NULL
+func (id ID) Value() (driver.Value, error) {
+	if id.IsZero() {
+		return nil, nil
+	}
+	return string(id), nil
+}
+
+// Scan implementa sql.Scanner
+func (id *ID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ""
+		return nil
+	case string:
+		return id.set(v)
+	case []byte:
+		return id.set(string(v))
+	default:
+		return fmt.Errorf("no se puede convertir %T en ID", src)
+	}
+}
+
+func (id *ID) set(s string) error {
+	parsed, err := ParseID(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// UnmarshalJSON valida el ID al decodificar peticiones
+func (id *ID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ErrInvalidID
+	}
+	if s == "" {
+		*id = ""
+		return nil
+	}
+	return id.set(s)
+}
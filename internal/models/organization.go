@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// OrgRole es el rol de un miembro en una organización. Cada rol incluye los
+// permisos de los anteriores.
+type OrgRole string
+
+const (
+	// OrgRoleMember puede ver los roadmaps de la organización aunque sean privados
+	OrgRoleMember OrgRole = "member"
+	// OrgRoleAdmin además gestiona los miembros, administra los roadmaps de la
+	// organización y consulta el progreso de sus miembros
+	OrgRoleAdmin OrgRole = "admin"
+	// OrgRoleOwner además gestiona a los administradores y puede eliminar la
+	// organización
+	OrgRoleOwner OrgRole = "owner"
+)
+
+var orgRoleRanks = map[OrgRole]int{
+	OrgRoleMember: 1,
+	OrgRoleAdmin:  2,
+	OrgRoleOwner:  3,
+}
+
+// Valid indica si el rol es uno de los roles conocidos
+func (r OrgRole) Valid() bool {
+	return orgRoleRanks[r] > 0
+}
+
+// Includes indica si el rol tiene al menos los permisos de other
+func (r OrgRole) Includes(other OrgRole) bool {
+	return orgRoleRanks[r] > 0 && orgRoleRanks[r] >= orgRoleRanks[other]
+}
+
+// Organization agrupa a varios usuarios y los roadmaps que comparten
+type Organization struct {
+	ID          ID        `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	CreatedBy   ID        `json:"created_by,omitempty"`
+	Role        OrgRole   `json:"role,omitempty"` // rol del usuario; solo en los listados de sus organizaciones
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// OrganizationMember es un usuario que pertenece a una organización
+type OrganizationMember struct {
+	OrganizationID ID        `json:"organization_id"`
+	UserID         ID        `json:"user_id"`
+	Username       string    `json:"username"`
+	Role           OrgRole   `json:"role"`
+	JoinedAt       time.Time `json:"joined_at"`
+}
+
+// MemberProgress es el progreso de un miembro en un roadmap de la organización
+type MemberProgress struct {
+	UserID         ID         `json:"user_id"`
+	RoadmapID      ID         `json:"roadmap_id"`
+	TotalNodes     int        `json:"total_nodes"`
+	Completed      int        `json:"completed"`
+	InProgress     int        `json:"in_progress"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+}
+
+// MemberSummary es el progreso de un miembro en el conjunto de roadmaps de la
+// organización. Completion es el porcentaje de nodos completados.
+type MemberSummary struct {
+	UserID         ID               `json:"user_id"`
+	Username       string           `json:"username"`
+	Role           OrgRole          `json:"role"`
+	TotalNodes     int              `json:"total_nodes"`
+	Completed      int              `json:"completed"`
+	InProgress     int              `json:"in_progress"`
+	Completion     int              `json:"completion"`
+	LastActivityAt *time.Time       `json:"last_activity_at,omitempty"`
+	Roadmaps       []MemberProgress `json:"roadmaps"`
+}
+
+// RoadmapProgressSummary es el progreso de los miembros de la organización en
+// uno de sus roadmaps. AvgCompletion es el porcentaje medio de nodos
+// completados por miembro.
+type RoadmapProgressSummary struct {
+	RoadmapID        ID     `json:"roadmap_id"`
+	Title            string `json:"title"`
+	TotalNodes       int    `json:"total_nodes"`
+	MembersStarted   int    `json:"members_started"`
+	MembersCompleted int    `json:"members_completed"`
+	AvgCompletion    int    `json:"avg_completion"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// ProposalStatus es el estado de una propuesta de cambios
+type ProposalStatus string
+
+const (
+	ProposalStatusOpen     ProposalStatus = "open"
+	ProposalStatusAccepted ProposalStatus = "accepted"
+	ProposalStatusRejected ProposalStatus = "rejected"
+)
+
+// Proposal es una propuesta de cambios sobre un roadmap enviada por un usuario
+// que no es su autor. Base es el contenido del roadmap cuando se envió y
+// Proposed el resultado de aplicarle las operaciones.
+type Proposal struct {
+	ID            ID                  `json:"id"`
+	RoadmapID     ID                  `json:"roadmap_id"`
+	AuthorID      ID                  `json:"author_id"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	Status        ProposalStatus      `json:"status"`
+	Operations    []ProposalOperation `json:"operations"`
+	Base          Snapshot            `json:"-"`
+	Proposed      Snapshot            `json:"-"`
+	ReviewerID    *ID                 `json:"reviewer_id,omitempty"`
+	ReviewComment string              `json:"review_comment,omitempty"`
+	ReviewedAt    *time.Time          `json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+// ProposalOperation es una operación de una propuesta. ID es el elemento
+// afectado; en las creaciones, el id provisional del nuevo elemento, al que
+// pueden referirse las operaciones siguientes.
+type ProposalOperation struct {
+	Op         string      `json:"op"`   // create, update, delete
+	Kind       string      `json:"kind"` // node, connection, resource
+	ID         ID          `json:"id"`
+	Node       *Node       `json:"node,omitempty"`
+	Connection *Connection `json:"connection,omitempty"`
+	Resource   *Resource   `json:"resource,omitempty"`
+}
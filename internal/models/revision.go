@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+// RevisionAction es la operación que originó una revisión
+type RevisionAction string
+
+const (
+	RevisionActionCreate           RevisionAction = "create"
+	RevisionActionFork             RevisionAction = "fork"
+	RevisionActionImport           RevisionAction = "import"
+	RevisionActionRoadmapUpdate    RevisionAction = "roadmap_update"
+	RevisionActionNodeCreate       RevisionAction = "node_create"
+	RevisionActionNodeUpdate       RevisionAction = "node_update"
+	RevisionActionNodeDelete       RevisionAction = "node_delete"
+	RevisionActionNodePositions    RevisionAction = "node_positions"
+	RevisionActionConnectionCreate RevisionAction = "connection_create"
+	RevisionActionConnectionDelete RevisionAction = "connection_delete"
+	RevisionActionResourceCreate   RevisionAction = "resource_create"
+	RevisionActionGraphSave        RevisionAction = "graph_save"
+	RevisionActionTextEdit         RevisionAction = "text_edit"
+	RevisionActionSync             RevisionAction = "sync"
+	RevisionActionProposalAccept   RevisionAction = "proposal_accept"
+	RevisionActionRestore          RevisionAction = "restore"
+)
+
+// Revision es el contenido de un roadmap tras una de sus modificaciones. Las
+// revisiones de cada roadmap se numeran de forma consecutiva desde 1.
+type Revision struct {
+	ID           ID               `json:"id"`
+	RoadmapID    ID               `json:"roadmap_id"`
+	Number       int              `json:"number"`
+	AuthorID     *ID              `json:"author_id,omitempty"`
+	Action       RevisionAction   `json:"action"`
+	RestoredFrom *int             `json:"restored_from,omitempty"`
+	Details      *RevisionDetails `json:"details,omitempty"` // nil en las revisiones anteriores a guardarlos
+	Snapshot     Snapshot         `json:"-"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// RevisionDetails son los datos generales del roadmap en una revisión
+type RevisionDetails struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	IsPublic    bool   `json:"is_public"`
+}
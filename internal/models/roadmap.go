@@ -6,27 +6,34 @@ import (
 
 // Roadmap representa un roadmap creado por un usuario
 type Roadmap struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Category    string    `json:"category"`
-	AuthorID    int64     `json:"author_id"`
-	IsPublic    bool      `json:"is_public"`
-	ForkedFrom  *int64    `json:"forked_from,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             ID         `json:"id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Category       string     `json:"category"`
+	AuthorID       ID         `json:"author_id"`
+	IsPublic       bool       `json:"is_public"`
+	ForkedFrom     *ID        `json:"forked_from,omitempty"`
+	ForkedAt       *time.Time `json:"forked_at,omitempty"`
+	OrganizationID *ID        `json:"organization_id,omitempty"` // sus miembros pueden ver el roadmap aunque no sea público
+	Version        int        `json:"version"`                   // aumenta con cada cambio del roadmap o de su contenido
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 // Node representa un nodo en el roadmap
 type Node struct {
-	ID          int64     `json:"id"`
-	RoadmapID   int64     `json:"roadmap_id"`
+	ID          ID        `json:"id"`
+	RoadmapID   ID        `json:"roadmap_id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Type        NodeType  `json:"type"`
 	Position    Position  `json:"position"`
 	Status      string    `json:"status"`
 	Color       string    `json:"color"`
+	OrderIndex  *int      `json:"order_index,omitempty"`     // orden entre nodos del mismo nivel en el plan de estudio
+	Estimate    *float64  `json:"estimated_hours,omitempty"` // horas estimadas para completar el nodo
+	OriginID    *ID       `json:"origin_id,omitempty"`       // nodo original del que se copió al hacer fork
+	Version     int       `json:"version"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -41,20 +48,37 @@ const (
 	NodeTypeMilestone NodeType = "milestone"
 )
 
+// Valid indica si el tipo de nodo es uno de los tipos conocidos
+func (t NodeType) Valid() bool {
+	switch t {
+	case NodeTypeTopic, NodeTypeResource, NodeTypeChallenge, NodeTypeMilestone:
+		return true
+	}
+	return false
+}
+
 // Position representa la posición de un nodo en el canvas
 type Position struct {
 	X float64 `json:"x"`
 	Y float64 `json:"y"`
 }
 
+// Tamaño con el que se dibuja cada nodo en el canvas; Position es su esquina
+// superior izquierda
+const (
+	NodeWidth  = 200
+	NodeHeight = 100
+)
+
 // Connection representa una conexión entre dos nodos
 type Connection struct {
-	ID             int64          `json:"id"`
-	RoadmapID      int64          `json:"roadmap_id"`
-	FromNodeID     int64          `json:"from_node_id"`
-	ToNodeID       int64          `json:"to_node_id"`
+	ID             ID             `json:"id"`
+	RoadmapID      ID             `json:"roadmap_id"`
+	FromNodeID     ID             `json:"from_node_id"`
+	ToNodeID       ID             `json:"to_node_id"`
 	Label          string         `json:"label,omitempty"`
 	ConnectionType ConnectionType `json:"connection_type"`
+	OriginID       *ID            `json:"origin_id,omitempty"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 }
@@ -69,38 +93,129 @@ const (
 	ConnectionTypeDashed  ConnectionType = "dashed"
 )
 
+// Valid indica si el tipo de conexión es uno de los tipos conocidos
+func (t ConnectionType) Valid() bool {
+	switch t {
+	case ConnectionTypeDefault, ConnectionTypeStrong, ConnectionTypeWeak, ConnectionTypeDashed:
+		return true
+	}
+	return false
+}
+
+// Prerequisite indica si la conexión es un requisito: el nodo de destino no
+// puede empezarse hasta completar el de origen
+func (t ConnectionType) Prerequisite() bool {
+	return t == ConnectionTypeStrong
+}
+
 // Resource representa un recurso asociado a un nodo
 type Resource struct {
-	ID          int64     `json:"id"`
-	NodeID      int64     `json:"node_id"`
+	ID          ID        `json:"id"`
+	NodeID      ID        `json:"node_id"`
 	Title       string    `json:"title"`
 	Type        string    `json:"type"` // url, video, document, etc.
 	URL         string    `json:"url"`
 	Description string    `json:"description"`
+	OriginID    *ID       `json:"origin_id,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 // Review representa una reseña de un roadmap
 type Review struct {
-	ID        int64     `json:"id"`
-	RoadmapID int64     `json:"roadmap_id"`
-	UserID    int64     `json:"user_id"`
+	ID        ID        `json:"id"`
+	RoadmapID ID        `json:"roadmap_id"`
+	UserID    ID        `json:"user_id"`
 	Rating    int       `json:"rating"`
 	Comment   string    `json:"comment"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// Progress representa el progreso de un usuario en un roadmap
+// ProgressStatus es el estado de un usuario en un nodo
+type ProgressStatus string
+
+const (
+	ProgressNotStarted ProgressStatus = "not_started"
+	ProgressInProgress ProgressStatus = "in_progress"
+	ProgressCompleted  ProgressStatus = "completed"
+)
+
+// Valid indica si el estado es uno de los estados conocidos
+func (s ProgressStatus) Valid() bool {
+	switch s {
+	case ProgressNotStarted, ProgressInProgress, ProgressCompleted:
+		return true
+	}
+	return false
+}
+
+// Progress representa el progreso de un usuario en un nodo de un roadmap
 type Progress struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"user_id"`
-	NodeID    int64     `json:"node_id"`
-	Status    string    `json:"status"` // not_started, in_progress, completed
-	Notes     string    `json:"notes,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          ID             `json:"id"`
+	UserID      ID             `json:"user_id"`
+	NodeID      ID             `json:"node_id"`
+	Status      ProgressStatus `json:"status"`
+	Notes       string         `json:"notes,omitempty"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// NodeProgress es un nodo junto con el estado del usuario en él
+type NodeProgress struct {
+	Node
+	Progress ProgressStatus `json:"progress"`
+}
+
+// ProgressSummary resume el progreso de un usuario en un roadmap.
+// Completion es el porcentaje de nodos completados.
+type ProgressSummary struct {
+	RoadmapID      ID         `json:"roadmap_id"`
+	TotalNodes     int        `json:"total_nodes"`
+	NotStarted     int        `json:"not_started"`
+	InProgress     int        `json:"in_progress"`
+	Completed      int        `json:"completed"`
+	Completion     int        `json:"completion"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+	Nodes          []Progress `json:"nodes"`
+}
+
+// RoadmapStats agrupa las estadísticas públicas de un roadmap
+type RoadmapStats struct {
+	Views     int `json:"views"`
+	Forks     int `json:"forks"`
+	Favorites int `json:"favorites"`
+}
+
+// RoadmapSummary es un roadmap tal como aparece en los listados
+type RoadmapSummary struct {
+	Roadmap
+	Author      User    `json:"author"`
+	Views       int     `json:"views"`
+	AvgRating   float64 `json:"avg_rating"`
+	ReviewCount int     `json:"review_count"`
+}
+
+// Facet es una categoría o etiqueta junto al número de roadmaps que la usan
+type Facet struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Snapshot es el contenido de un roadmap en un momento dado
+type Snapshot struct {
+	Nodes       []Node       `json:"nodes"`
+	Connections []Connection `json:"connections"`
+	Resources   []Resource   `json:"resources"`
+}
+
+// RoadmapGraph representa un roadmap completo tal como lo consume el editor
+type RoadmapGraph struct {
+	Roadmap
+	Nodes       []Node       `json:"nodes"`
+	Connections []Connection `json:"connections"`
 }
 
 // Props para las vistas
@@ -121,6 +236,25 @@ type RoadmapDetailProps struct {
 	Nodes     []RoadmapNodeProps
 	Resources []ResourceProps
 	Reviews   []ReviewProps
+	Progress  *ProgressSummary // nil si el visitante no ha iniciado sesión
+}
+
+// StudyPlanProps es el plan de estudio imprimible de un roadmap. Levels
+// agrupa los pasos que pueden hacerse en paralelo.
+type StudyPlanProps struct {
+	ID          string
+	Title       string
+	Description string
+	Levels      [][]StudyPlanStep
+	Tracked     bool // el visitante ha iniciado sesión y se muestra su progreso
+}
+
+type StudyPlanStep struct {
+	Number      int
+	Title       string
+	Description string
+	Type        string
+	Status      string
 }
 
 type RoadmapNodeProps struct {
@@ -131,6 +265,7 @@ type RoadmapNodeProps struct {
 	PositionX   float64
 	PositionY   float64
 	Status      string
+	Locked      bool // quedan requisitos del nodo sin completar
 	Connections []struct {
 		TargetID string
 		Type     string
@@ -152,4 +287,4 @@ type ReviewProps struct {
 	Rating    int
 	Comment   string
 	CreatedAt string
-}
\ No newline at end of file
+}
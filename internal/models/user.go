@@ -16,10 +16,11 @@ var (
 )
 
 type User struct {
-	ID           int64     `json:"id" db:"id"`
+	ID           ID        `json:"id" db:"id"`
 	Username     string    `json:"username" db:"username"`
 	Email        string    `json:"email" db:"email"`
 	PasswordHash string    `json:"-" db:"password_hash"`
+	GoogleID     string    `json:"-" db:"google_id"`
 	AvatarURL    string    `json:"avatar_url,omitempty" db:"avatar_url"`
 	Bio          string    `json:"bio,omitempty" db:"bio"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
@@ -93,4 +94,4 @@ func (u *User) Validate() error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
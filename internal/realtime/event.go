@@ -0,0 +1,71 @@
+package realtime
+
+import (
+	"time"
+
+	"Gin/internal/models"
+)
+
+// EventType identifica el tipo de un evento del canal en tiempo real
+type EventType string
+
+const (
+	// EventHello es el primer evento que recibe un editor al conectarse
+	EventHello EventType = "hello"
+	// EventPresence indica quién está conectado y qué nodo tiene seleccionado
+	EventPresence EventType = "presence"
+
+	EventNodeCreated       EventType = "node_created"
+	EventNodeUpdated       EventType = "node_updated"
+	EventNodeDeleted       EventType = "node_deleted"
+	EventNodePositions     EventType = "node_positions"
+	EventConnectionCreated EventType = "connection_created"
+	EventConnectionDeleted EventType = "connection_deleted"
+	// EventGraphReplaced indica que el contenido cambió en bloque (guardado
+	// completo, sincronización, propuesta aceptada o restauración) y los
+	// editores deben recargarlo
+	EventGraphReplaced EventType = "graph_replaced"
+)
+
+// Origin identifica quién originó una operación. ClientID es el identificador
+// de la pestaña del editor; el evento lleva el ConnectionID de esa pestaña
+// para que reconozca sus propios cambios, solo si es del mismo usuario.
+type Origin struct {
+	ClientID string
+	UserID   models.ID
+}
+
+// Event es una operación sobre un roadmap. Seq es el número de secuencia que
+// le asigna el servidor: todos los editores reciben las operaciones en el
+// mismo orden, que es en el que se escribieron.
+type Event struct {
+	Seq          int64     `json:"seq"`
+	Type         EventType `json:"type"`
+	ConnectionID string    `json:"connection_id,omitempty"`
+	UserID       models.ID `json:"user_id,omitempty"`
+	Data         any       `json:"data,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Presence es un editor conectado a un roadmap. El ClientID solo lo conoce la
+// propia pestaña; al resto se publica el ConnectionID que el hub deriva de él.
+type Presence struct {
+	ClientID       string     `json:"-"`
+	ConnectionID   string     `json:"connection_id"`
+	UserID         models.ID  `json:"user_id"`
+	Username       string     `json:"username"`
+	SelectedNodeID *models.ID `json:"selected_node_id"`
+	JoinedAt       time.Time  `json:"joined_at"`
+}
+
+// Welcome es el estado que recibe un editor al conectarse
+type Welcome struct {
+	ClientID     string     `json:"client_id"`
+	ConnectionID string     `json:"connection_id"`
+	Seq          int64      `json:"seq"`
+	Presence     []Presence `json:"presence"`
+	// Resync indica que no se pueden reenviar las operaciones perdidas y el
+	// editor debe recargar el roadmap completo
+	Resync bool    `json:"resync"`
+	Missed []Event `json:"-"`
+}
@@ -0,0 +1,312 @@
+// Package realtime reparte entre los editores conectados a un roadmap las
+// operaciones que se hacen sobre él y quién lo está editando.
+package realtime
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"Gin/internal/models"
+)
+
+const (
+	// historySize es el número de operaciones que se guardan por roadmap para
+	// reenviarlas a los editores que se reconectan
+	historySize = 256
+	// bufferSize es el número de eventos pendientes por editor. Si se llena,
+	// el editor se desconecta y al reconectarse recupera lo que perdió.
+	bufferSize = 64
+)
+
+// ErrNotConnected indica que el editor no está conectado al roadmap
+var ErrNotConnected = errors.New("el editor no está conectado")
+
+// Subscription es la conexión de un editor a un roadmap
+type Subscription struct {
+	Presence
+	roadmapID models.ID
+	events    chan Event
+}
+
+// Events devuelve los eventos del roadmap. Se cierra cuando el editor se
+// desconecta o no consume los eventos a tiempo.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+type room struct {
+	seq     int64
+	history []Event
+	clients map[string]*Subscription // por ConnectionID
+}
+
+// writer serializa las escrituras de un roadmap
+type writer struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// Hub mantiene los editores conectados a cada roadmap
+type Hub struct {
+	mu      sync.Mutex
+	rooms   map[models.ID]*room
+	writers map[models.ID]*writer
+	key     []byte // clave con la que se derivan los ConnectionID
+}
+
+func NewHub() *Hub {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return &Hub{
+		rooms:   make(map[models.ID]*room),
+		writers: make(map[models.ID]*writer),
+		key:     key,
+	}
+}
+
+// connectionID es el identificador público de la pestaña clientID del
+// usuario. Se deriva con la clave del hub, así que conocerlo no permite
+// suplantar al editor, y el mismo ClientID de otro usuario da otro distinto.
+func (h *Hub) connectionID(userID models.ID, clientID string) string {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(userID.String()))
+	mac.Write([]byte{0})
+	mac.Write([]byte(clientID))
+	return hex.EncodeToString(mac.Sum(nil)[:12])
+}
+
+// room devuelve la sala del roadmap, creándola si no existe. Las salas no se
+// eliminan para que la secuencia de cada roadmap no vuelva a empezar.
+func (h *Hub) room(roadmapID models.ID) *room {
+	r, ok := h.rooms[roadmapID]
+	if !ok {
+		r = &room{clients: make(map[string]*Subscription)}
+		h.rooms[roadmapID] = r
+	}
+	return r
+}
+
+// Join conecta un editor al roadmap. after es el último número de secuencia
+// que recibió el editor, o -1 si no recibió ninguno; si las operaciones
+// posteriores siguen en el historial se incluyen en Missed y, si no, se pide
+// al editor que recargue el roadmap. Si presence no trae ClientID se genera
+// uno; si el mismo usuario ya tenía una conexión con ese ClientID, se
+// sustituye. Las conexiones de otros usuarios nunca se sustituyen.
+func (h *Hub) Join(roadmapID models.ID, presence Presence, after int64) (*Subscription, Welcome) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r := h.room(roadmapID)
+	if presence.ClientID == "" {
+		presence.ClientID = models.NewID().String()
+	}
+	presence.ConnectionID = h.connectionID(presence.UserID, presence.ClientID)
+	if previous, ok := r.clients[presence.ConnectionID]; ok {
+		close(previous.events)
+	}
+
+	presence.JoinedAt = time.Now()
+	sub := &Subscription{Presence: presence, roadmapID: roadmapID, events: make(chan Event, bufferSize)}
+	r.clients[presence.ConnectionID] = sub
+
+	welcome := Welcome{ClientID: presence.ClientID, ConnectionID: presence.ConnectionID, Seq: r.seq}
+	welcome.Missed, welcome.Resync = r.since(after)
+	h.broadcastPresence(r)
+	welcome.Presence = r.presence()
+	return sub, welcome
+}
+
+// since devuelve las operaciones posteriores a after, o false si ya no están
+// todas en el historial
+func (r *room) since(after int64) ([]Event, bool) {
+	if after < 0 || after > r.seq {
+		return nil, true
+	}
+	if after == r.seq {
+		return nil, false
+	}
+	if len(r.history) == 0 || r.history[0].Seq > after+1 {
+		return nil, true
+	}
+	missed := r.history[after+1-r.history[0].Seq:]
+	return append([]Event(nil), missed...), false
+}
+
+// Leave desconecta al editor
+func (h *Hub) Leave(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r := h.room(sub.roadmapID)
+	if r.clients[sub.ConnectionID] != sub {
+		return // ya sustituido o expulsado
+	}
+	delete(r.clients, sub.ConnectionID)
+	close(sub.events)
+	h.broadcastPresence(r)
+}
+
+// Disconnect desconecta todas las conexiones del usuario al roadmap, por
+// ejemplo cuando pierde el acceso
+func (h *Hub) Disconnect(roadmapID, userID models.ID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r := h.room(roadmapID)
+	var removed bool
+	for connectionID, sub := range r.clients {
+		if sub.UserID == userID {
+			delete(r.clients, connectionID)
+			close(sub.events)
+			removed = true
+		}
+	}
+	if removed {
+		h.broadcastPresence(r)
+	}
+}
+
+// Select actualiza el nodo que tiene seleccionado el editor, o ninguno si
+// nodeID es nil
+func (h *Hub) Select(roadmapID models.ID, clientID string, userID models.ID, nodeID *models.ID) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r := h.room(roadmapID)
+	sub, ok := r.clients[h.connectionID(userID, clientID)]
+	if !ok {
+		return ErrNotConnected
+	}
+	sub.SelectedNodeID = nodeID
+	h.broadcastPresence(r)
+	return nil
+}
+
+// Presence devuelve los editores conectados al roadmap por orden de llegada
+func (h *Hub) Presence(roadmapID models.ID) []Presence {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.room(roadmapID).presence()
+}
+
+func (r *room) presence() []Presence {
+	presence := make([]Presence, 0, len(r.clients))
+	for _, sub := range r.clients {
+		presence = append(presence, sub.Presence)
+	}
+	sort.Slice(presence, func(i, j int) bool {
+		if !presence[i].JoinedAt.Equal(presence[j].JoinedAt) {
+			return presence[i].JoinedAt.Before(presence[j].JoinedAt)
+		}
+		return presence[i].ConnectionID < presence[j].ConnectionID
+	})
+	return presence
+}
+
+// Commit ejecuta write en exclusiva frente a las demás escrituras del roadmap
+// y, si termina sin error, publica las operaciones que haya emitido con
+// números de secuencia consecutivos. Así el orden de la secuencia es el mismo
+// en que se confirmaron las escrituras y todos los editores convergen.
+func (h *Hub) Commit(roadmapID models.ID, origin Origin, write func(emit func(EventType, any)) error) error {
+	w := h.acquire(roadmapID)
+	defer h.release(roadmapID, w)
+
+	var pending []Event
+	err := write(func(eventType EventType, data any) {
+		pending = append(pending, Event{Type: eventType, Data: data})
+	})
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r := h.room(roadmapID)
+	now := time.Now()
+
+	// El origen solo se identifica si es una conexión del mismo usuario
+	var connectionID string
+	if origin.ClientID != "" {
+		if sub, ok := r.clients[h.connectionID(origin.UserID, origin.ClientID)]; ok {
+			connectionID = sub.ConnectionID
+		}
+	}
+	for _, event := range pending {
+		r.seq++
+		event.Seq = r.seq
+		event.ConnectionID = connectionID
+		event.UserID = origin.UserID
+		event.CreatedAt = now
+
+		// Sin editores conectados no hace falta guardar nada: quien se
+		// conecte después recargará el roadmap
+		if len(r.clients) == 0 {
+			continue
+		}
+		r.history = append(r.history, event)
+		if len(r.history) > historySize {
+			r.history = r.history[len(r.history)-historySize:]
+		}
+		h.broadcast(r, event)
+	}
+	return nil
+}
+
+func (h *Hub) acquire(roadmapID models.ID) *writer {
+	h.mu.Lock()
+	w, ok := h.writers[roadmapID]
+	if !ok {
+		w = &writer{}
+		h.writers[roadmapID] = w
+	}
+	w.refs++
+	h.mu.Unlock()
+
+	w.mu.Lock()
+	return w
+}
+
+func (h *Hub) release(roadmapID models.ID, w *writer) {
+	w.mu.Unlock()
+
+	h.mu.Lock()
+	w.refs--
+	if w.refs == 0 {
+		delete(h.writers, roadmapID)
+	}
+	h.mu.Unlock()
+}
+
+// broadcast envía el evento a todos los editores de la sala. Los que tienen
+// el buffer lleno se desconectan y se avisa al resto de su salida.
+func (h *Hub) broadcast(r *room, event Event) {
+	var dropped bool
+	for connectionID, sub := range r.clients {
+		select {
+		case sub.events <- event:
+		default:
+			delete(r.clients, connectionID)
+			close(sub.events)
+			dropped = true
+		}
+	}
+	if dropped {
+		h.broadcastPresence(r)
+	}
+}
+
+func (h *Hub) broadcastPresence(r *room) {
+	if len(r.clients) == 0 {
+		// Nadie puede continuar la secuencia desde el historial
+		r.history = nil
+		return
+	}
+	h.broadcast(r, Event{Seq: r.seq, Type: EventPresence, Data: r.presence(), CreatedAt: time.Now()})
+}
@@ -0,0 +1,243 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type assignmentRepository struct {
+	s *Store
+}
+
+// assigneeKey identifica a un asignado, como la clave primaria de postgres
+type assigneeKey struct {
+	assignmentID models.ID
+	userID       models.ID
+}
+
+// withNodes copia los nodos de la asignación para que no se compartan entre
+// transacciones; si asigna el roadmap completo no tiene ninguno
+func withNodes(assignment models.Assignment) models.Assignment {
+	if assignment.AllNodes {
+		assignment.NodeIDs = []models.ID{}
+	} else {
+		assignment.NodeIDs = append([]models.ID{}, assignment.NodeIDs...)
+		slices.Sort(assignment.NodeIDs)
+		assignment.NodeIDs = slices.Compact(assignment.NodeIDs)
+	}
+	return assignment
+}
+
+func (r *assignmentRepository) Create(assignment *models.Assignment) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.roadmaps[assignment.RoadmapID]; !ok {
+			return errInvalidReference
+		}
+		if err := d.checkAssignmentNodes(assignment); err != nil {
+			return err
+		}
+
+		assignment.ID = models.NewID()
+		assignment.CreatedAt = time.Now()
+		assignment.UpdatedAt = assignment.CreatedAt
+		*assignment = withNodes(*assignment)
+		d.assignments[assignment.ID] = withNodes(*assignment)
+		return nil
+	})
+}
+
+// checkAssignmentNodes comprueba que los nodos de la asignación existen
+func (d *data) checkAssignmentNodes(assignment *models.Assignment) error {
+	if assignment.AllNodes {
+		return nil
+	}
+	for _, nodeID := range assignment.NodeIDs {
+		if _, ok := d.nodes[nodeID]; !ok {
+			return errInvalidReference
+		}
+	}
+	return nil
+}
+
+func (r *assignmentRepository) GetByID(id models.ID) (*models.Assignment, error) {
+	var assignment models.Assignment
+	err := r.s.read(func(d *data) error {
+		found, ok := d.assignments[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		assignment = withNodes(found)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *assignmentRepository) ListByRoadmap(roadmapID models.ID) ([]models.Assignment, error) {
+	return r.list(func(d *data, a models.Assignment) bool { return a.RoadmapID == roadmapID })
+}
+
+func (r *assignmentRepository) ListByUser(userID models.ID) ([]models.Assignment, error) {
+	return r.list(func(d *data, a models.Assignment) bool {
+		_, ok := d.assignees[assigneeKey{a.ID, userID}]
+		return ok
+	})
+}
+
+// list devuelve las asignaciones que cumplen match por fecha límite
+func (r *assignmentRepository) list(match func(*data, models.Assignment) bool) ([]models.Assignment, error) {
+	assignments := []models.Assignment{}
+	err := r.s.read(func(d *data) error {
+		for _, assignment := range d.assignments {
+			if match(d, assignment) {
+				assignments = append(assignments, withNodes(assignment))
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(assignments, func(i, j int) bool {
+		a, b := assignments[i], assignments[j]
+		if !a.DueAt.Equal(b.DueAt) {
+			return a.DueAt.Before(b.DueAt)
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	return assignments, err
+}
+
+func (r *assignmentRepository) Update(assignment *models.Assignment) error {
+	return r.s.write(func(d *data) error {
+		current, ok := d.assignments[assignment.ID]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		if err := d.checkAssignmentNodes(assignment); err != nil {
+			return err
+		}
+
+		current.Title = assignment.Title
+		current.Description = assignment.Description
+		current.AllNodes = assignment.AllNodes
+		current.NodeIDs = assignment.NodeIDs
+		current.DueAt = assignment.DueAt
+		current.UpdatedAt = now(assignment.UpdatedAt)
+		d.assignments[assignment.ID] = withNodes(current)
+		return nil
+	})
+}
+
+func (r *assignmentRepository) Delete(id models.ID) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.assignments[id]; !ok {
+			return repository.ErrNotFound
+		}
+		d.deleteAssignment(id)
+		return nil
+	})
+}
+
+// deleteAssignment elimina una asignación con sus asignados
+func (d *data) deleteAssignment(id models.ID) {
+	delete(d.assignments, id)
+	for key := range d.assignees {
+		if key.assignmentID == id {
+			delete(d.assignees, key)
+		}
+	}
+}
+
+func (r *assignmentRepository) AddAssignee(assignmentID, userID models.ID) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.assignments[assignmentID]; !ok {
+			return errInvalidReference
+		}
+		if _, ok := d.users[userID]; !ok {
+			return errInvalidReference
+		}
+		key := assigneeKey{assignmentID, userID}
+		if _, ok := d.assignees[key]; !ok {
+			d.assignees[key] = time.Now()
+		}
+		return nil
+	})
+}
+
+func (r *assignmentRepository) RemoveAssignee(assignmentID, userID models.ID) error {
+	return r.s.write(func(d *data) error {
+		key := assigneeKey{assignmentID, userID}
+		if _, ok := d.assignees[key]; !ok {
+			return repository.ErrNotFound
+		}
+		delete(d.assignees, key)
+		return nil
+	})
+}
+
+func (r *assignmentRepository) ListAssignees(assignmentID models.ID) ([]models.Assignee, error) {
+	assignees := []models.Assignee{}
+	err := r.s.read(func(d *data) error {
+		assignment, ok := d.assignments[assignmentID]
+		if !ok {
+			return nil
+		}
+
+		// Nodos de la asignación
+		nodes := make(map[models.ID]bool)
+		for nodeID, node := range d.nodes {
+			if node.RoadmapID == assignment.RoadmapID && (assignment.AllNodes || slices.Contains(assignment.NodeIDs, nodeID)) {
+				nodes[nodeID] = true
+			}
+		}
+
+		index := make(map[models.ID]int)
+		for key, assignedAt := range d.assignees {
+			if key.assignmentID != assignmentID {
+				continue
+			}
+			index[key.userID] = len(assignees)
+			assignees = append(assignees, models.Assignee{
+				AssignmentID: assignmentID,
+				UserID:       key.userID,
+				Username:     d.users[key.userID].Username,
+				AssignedAt:   assignedAt,
+				TotalNodes:   len(nodes),
+			})
+		}
+
+		for _, p := range d.progress {
+			i, ok := index[p.UserID]
+			if !ok || !nodes[p.NodeID] {
+				continue
+			}
+			a := &assignees[i]
+			switch p.Status {
+			case models.ProgressCompleted:
+				a.Completed++
+				if p.CompletedAt != nil && (a.CompletedAt == nil || p.CompletedAt.After(*a.CompletedAt)) {
+					completedAt := *p.CompletedAt
+					a.CompletedAt = &completedAt
+				}
+			case models.ProgressInProgress:
+				a.InProgress++
+			}
+			if a.LastActivityAt == nil || p.UpdatedAt.After(*a.LastActivityAt) {
+				updatedAt := p.UpdatedAt
+				a.LastActivityAt = &updatedAt
+			}
+		}
+		return nil
+	})
+
+	sortByCreated(assignees, func(a models.Assignee) (time.Time, models.ID) { return a.AssignedAt, a.UserID })
+	return assignees, err
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type collaboratorRepository struct {
+	s *Store
+}
+
+// withUsername completa el colaborador con el username, como el JOIN de postgres
+func (d *data) withUsername(collaborator models.Collaborator) models.Collaborator {
+	collaborator.Username = d.users[collaborator.UserID].Username
+	return collaborator
+}
+
+func (r *collaboratorRepository) Create(collaborator *models.Collaborator) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.roadmaps[collaborator.RoadmapID]; !ok {
+			return errInvalidReference
+		}
+		if _, ok := d.users[collaborator.UserID]; !ok {
+			return errInvalidReference
+		}
+		for _, existing := range d.collaborators {
+			if existing.RoadmapID == collaborator.RoadmapID && existing.UserID == collaborator.UserID {
+				return repository.ErrConflict
+			}
+		}
+
+		collaborator.ID = models.NewID()
+		collaborator.CreatedAt = time.Now()
+		collaborator.UpdatedAt = collaborator.CreatedAt
+		*collaborator = d.withUsername(*collaborator)
+		d.collaborators[collaborator.ID] = *collaborator
+		return nil
+	})
+}
+
+func (r *collaboratorRepository) GetByID(id models.ID) (*models.Collaborator, error) {
+	return r.find(func(c models.Collaborator) bool { return c.ID == id })
+}
+
+func (r *collaboratorRepository) Get(roadmapID, userID models.ID) (*models.Collaborator, error) {
+	return r.find(func(c models.Collaborator) bool { return c.RoadmapID == roadmapID && c.UserID == userID })
+}
+
+func (r *collaboratorRepository) find(match func(models.Collaborator) bool) (*models.Collaborator, error) {
+	var found models.Collaborator
+	err := r.s.read(func(d *data) error {
+		for _, collaborator := range d.collaborators {
+			if match(collaborator) {
+				found = d.withUsername(collaborator)
+				return nil
+			}
+		}
+		return repository.ErrNotFound
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+func (r *collaboratorRepository) ListByRoadmap(roadmapID models.ID) ([]models.Collaborator, error) {
+	return r.list(func(c models.Collaborator) bool { return c.RoadmapID == roadmapID })
+}
+
+func (r *collaboratorRepository) ListByUser(userID models.ID, status models.CollaboratorStatus) ([]models.Collaborator, error) {
+	collaborators, err := r.list(func(c models.Collaborator) bool { return c.UserID == userID && c.Status == status })
+	for i, j := 0, len(collaborators)-1; i < j; i, j = i+1, j-1 {
+		collaborators[i], collaborators[j] = collaborators[j], collaborators[i]
+	}
+	return collaborators, err
+}
+
+// list devuelve los colaboradores que cumplen match por orden de invitación
+func (r *collaboratorRepository) list(match func(models.Collaborator) bool) ([]models.Collaborator, error) {
+	collaborators := []models.Collaborator{}
+	err := r.s.read(func(d *data) error {
+		for _, collaborator := range d.collaborators {
+			if match(collaborator) {
+				collaborators = append(collaborators, d.withUsername(collaborator))
+			}
+		}
+		return nil
+	})
+
+	sortByCreated(collaborators, func(c models.Collaborator) (time.Time, models.ID) { return c.CreatedAt, c.ID })
+	return collaborators, err
+}
+
+func (r *collaboratorRepository) Update(collaborator *models.Collaborator) error {
+	return r.s.write(func(d *data) error {
+		current, ok := d.collaborators[collaborator.ID]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		current.Role = collaborator.Role
+		current.Status = collaborator.Status
+		current.AcceptedAt = collaborator.AcceptedAt
+		current.UpdatedAt = now(collaborator.UpdatedAt)
+		d.collaborators[collaborator.ID] = current
+		return nil
+	})
+}
+
+func (r *collaboratorRepository) Delete(id models.ID) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.collaborators[id]; !ok {
+			return repository.ErrNotFound
+		}
+		delete(d.collaborators, id)
+		return nil
+	})
+}
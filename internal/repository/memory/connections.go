@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type connectionRepository struct {
+	s *Store
+}
+
+// checkConnection valida las referencias y la unicidad de una conexión
+func (d *data) checkConnection(conn *models.Connection) error {
+	from, okFrom := d.nodes[conn.FromNodeID]
+	to, okTo := d.nodes[conn.ToNodeID]
+	if !okFrom || !okTo || from.RoadmapID != conn.RoadmapID || to.RoadmapID != conn.RoadmapID {
+		return errInvalidReference
+	}
+
+	for id, other := range d.connections {
+		if id != conn.ID && other.RoadmapID == conn.RoadmapID &&
+			other.FromNodeID == conn.FromNodeID && other.ToNodeID == conn.ToNodeID {
+			return repository.ErrConflict
+		}
+	}
+	return nil
+}
+
+func (r *connectionRepository) Create(conn *models.Connection) error {
+	return r.s.write(func(d *data) error {
+		if err := d.checkConnection(conn); err != nil {
+			return err
+		}
+
+		conn.ID = models.NewID()
+		conn.CreatedAt = now(conn.CreatedAt)
+		conn.UpdatedAt = now(conn.UpdatedAt)
+		d.connections[conn.ID] = *conn
+		return nil
+	})
+}
+
+func (r *connectionRepository) GetByID(roadmapID, connID models.ID) (*models.Connection, error) {
+	var conn models.Connection
+	err := r.s.read(func(d *data) error {
+		var ok bool
+		if conn, ok = d.connections[connID]; !ok || conn.RoadmapID != roadmapID {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &conn, nil
+}
+
+func (r *connectionRepository) ListByRoadmap(roadmapID models.ID) ([]models.Connection, error) {
+	connections := []models.Connection{}
+	err := r.s.read(func(d *data) error {
+		for _, conn := range d.connections {
+			if conn.RoadmapID == roadmapID {
+				connections = append(connections, conn)
+			}
+		}
+		return nil
+	})
+
+	sortByCreated(connections, func(c models.Connection) (time.Time, models.ID) { return c.CreatedAt, c.ID })
+	return connections, err
+}
+
+func (r *connectionRepository) Exists(roadmapID, fromNodeID, toNodeID models.ID) (bool, error) {
+	exists := false
+	err := r.s.read(func(d *data) error {
+		for _, conn := range d.connections {
+			if conn.RoadmapID == roadmapID && conn.FromNodeID == fromNodeID && conn.ToNodeID == toNodeID {
+				exists = true
+				break
+			}
+		}
+		return nil
+	})
+	return exists, err
+}
+
+func (r *connectionRepository) Update(conn *models.Connection) error {
+	return r.s.write(func(d *data) error {
+		current, ok := d.connections[conn.ID]
+		if !ok || current.RoadmapID != conn.RoadmapID {
+			return repository.ErrNotFound
+		}
+		if err := d.checkConnection(conn); err != nil {
+			return err
+		}
+
+		updated := *conn
+		updated.OriginID = current.OriginID
+		updated.CreatedAt = current.CreatedAt
+		d.connections[conn.ID] = updated
+		return nil
+	})
+}
+
+func (r *connectionRepository) Delete(roadmapID, connID models.ID) error {
+	return r.s.write(func(d *data) error {
+		conn, ok := d.connections[connID]
+		if !ok || conn.RoadmapID != roadmapID {
+			return repository.ErrNotFound
+		}
+
+		delete(d.connections, connID)
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type nodeRepository struct {
+	s *Store
+}
+
+func (r *nodeRepository) Create(node *models.Node) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.roadmaps[node.RoadmapID]; !ok {
+			return errInvalidReference
+		}
+
+		node.ID = models.NewID()
+		node.Version = 1
+		node.CreatedAt = now(node.CreatedAt)
+		node.UpdatedAt = now(node.UpdatedAt)
+		d.nodes[node.ID] = *node
+		return nil
+	})
+}
+
+func (r *nodeRepository) GetByID(roadmapID, nodeID models.ID) (*models.Node, error) {
+	var node models.Node
+	err := r.s.read(func(d *data) error {
+		var ok bool
+		if node, ok = d.nodes[nodeID]; !ok || node.RoadmapID != roadmapID {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+func (r *nodeRepository) ListByRoadmap(roadmapID models.ID) ([]models.Node, error) {
+	nodes := []models.Node{}
+	err := r.s.read(func(d *data) error {
+		for _, node := range d.nodes {
+			if node.RoadmapID == roadmapID {
+				nodes = append(nodes, node)
+			}
+		}
+		return nil
+	})
+
+	sortByCreated(nodes, func(n models.Node) (time.Time, models.ID) { return n.CreatedAt, n.ID })
+	return nodes, err
+}
+
+func (r *nodeRepository) Update(node *models.Node) error {
+	return r.s.write(func(d *data) error {
+		current, ok := d.nodes[node.ID]
+		if !ok || current.RoadmapID != node.RoadmapID {
+			return repository.ErrNotFound
+		}
+		if current.Version != node.Version {
+			return repository.ErrVersionConflict
+		}
+
+		node.Version++
+		updated := *node
+		updated.OriginID = current.OriginID
+		updated.CreatedAt = current.CreatedAt
+		d.nodes[node.ID] = updated
+		return nil
+	})
+}
+
+func (r *nodeRepository) UpdatePosition(roadmapID, nodeID models.ID, position models.Position, version int, updatedAt time.Time) (int, error) {
+	err := r.s.write(func(d *data) error {
+		node, ok := d.nodes[nodeID]
+		if !ok || node.RoadmapID != roadmapID {
+			return repository.ErrNotFound
+		}
+		if version != 0 && node.Version != version {
+			return repository.ErrVersionConflict
+		}
+
+		node.Position = position
+		node.UpdatedAt = updatedAt
+		node.Version++
+		d.nodes[nodeID] = node
+		version = node.Version
+		return nil
+	})
+	return version, err
+}
+
+func (r *nodeRepository) Delete(roadmapID, nodeID models.ID) error {
+	return r.s.write(func(d *data) error {
+		node, ok := d.nodes[nodeID]
+		if !ok || node.RoadmapID != roadmapID {
+			return repository.ErrNotFound
+		}
+
+		d.deleteNode(nodeID)
+		return nil
+	})
+}
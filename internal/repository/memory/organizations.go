@@ -0,0 +1,255 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type organizationRepository struct {
+	s *Store
+}
+
+// memberKey identifica a un miembro, como la clave primaria de postgres
+type memberKey struct {
+	organizationID models.ID
+	userID         models.ID
+}
+
+func (r *organizationRepository) Create(organization *models.Organization) error {
+	return r.s.write(func(d *data) error {
+		if d.slugTaken(organization.Slug, "") {
+			return repository.ErrConflict
+		}
+
+		organization.ID = models.NewID()
+		organization.Role = ""
+		organization.CreatedAt = time.Now()
+		organization.UpdatedAt = organization.CreatedAt
+		d.organizations[organization.ID] = *organization
+		return nil
+	})
+}
+
+// slugTaken indica si otra organización distinta de except usa el slug
+func (d *data) slugTaken(slug string, except models.ID) bool {
+	for id, organization := range d.organizations {
+		if id != except && organization.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *organizationRepository) GetByID(id models.ID) (*models.Organization, error) {
+	var organization models.Organization
+	err := r.s.read(func(d *data) error {
+		var ok bool
+		if organization, ok = d.organizations[id]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) ListByUser(userID models.ID) ([]models.Organization, error) {
+	organizations := []models.Organization{}
+	err := r.s.read(func(d *data) error {
+		for key, member := range d.members {
+			if key.userID != userID {
+				continue
+			}
+			organization := d.organizations[key.organizationID]
+			organization.Role = member.Role
+			organizations = append(organizations, organization)
+		}
+		return nil
+	})
+
+	sort.Slice(organizations, func(i, j int) bool {
+		if organizations[i].Name != organizations[j].Name {
+			return organizations[i].Name < organizations[j].Name
+		}
+		return organizations[i].ID < organizations[j].ID
+	})
+	return organizations, err
+}
+
+func (r *organizationRepository) Update(organization *models.Organization) error {
+	return r.s.write(func(d *data) error {
+		current, ok := d.organizations[organization.ID]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		if d.slugTaken(organization.Slug, organization.ID) {
+			return repository.ErrConflict
+		}
+
+		current.Name = organization.Name
+		current.Slug = organization.Slug
+		current.Description = organization.Description
+		current.UpdatedAt = now(organization.UpdatedAt)
+		d.organizations[organization.ID] = current
+		return nil
+	})
+}
+
+func (r *organizationRepository) Delete(id models.ID) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.organizations[id]; !ok {
+			return repository.ErrNotFound
+		}
+
+		delete(d.organizations, id)
+		for key := range d.members {
+			if key.organizationID == id {
+				delete(d.members, key)
+			}
+		}
+		for roadmapID, roadmap := range d.roadmaps {
+			if roadmap.OrganizationID != nil && *roadmap.OrganizationID == id {
+				roadmap.OrganizationID = nil
+				d.roadmaps[roadmapID] = roadmap
+			}
+		}
+		return nil
+	})
+}
+
+func (r *organizationRepository) AddMember(member *models.OrganizationMember) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.organizations[member.OrganizationID]; !ok {
+			return errInvalidReference
+		}
+		user, ok := d.users[member.UserID]
+		if !ok {
+			return errInvalidReference
+		}
+		key := memberKey{member.OrganizationID, member.UserID}
+		if _, ok := d.members[key]; ok {
+			return repository.ErrConflict
+		}
+
+		member.Username = user.Username
+		member.JoinedAt = time.Now()
+		d.members[key] = *member
+		return nil
+	})
+}
+
+func (r *organizationRepository) GetMember(organizationID, userID models.ID) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := r.s.read(func(d *data) error {
+		var ok bool
+		if member, ok = d.members[memberKey{organizationID, userID}]; !ok {
+			return repository.ErrNotFound
+		}
+		member.Username = d.users[userID].Username
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *organizationRepository) ListMembers(organizationID models.ID) ([]models.OrganizationMember, error) {
+	members := []models.OrganizationMember{}
+	err := r.s.read(func(d *data) error {
+		for key, member := range d.members {
+			if key.organizationID == organizationID {
+				member.Username = d.users[key.userID].Username
+				members = append(members, member)
+			}
+		}
+		return nil
+	})
+
+	sortByCreated(members, func(m models.OrganizationMember) (time.Time, models.ID) { return m.JoinedAt, m.UserID })
+	return members, err
+}
+
+func (r *organizationRepository) UpdateMember(member *models.OrganizationMember) error {
+	return r.s.write(func(d *data) error {
+		key := memberKey{member.OrganizationID, member.UserID}
+		current, ok := d.members[key]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		current.Role = member.Role
+		d.members[key] = current
+		return nil
+	})
+}
+
+func (r *organizationRepository) RemoveMember(organizationID, userID models.ID) error {
+	return r.s.write(func(d *data) error {
+		key := memberKey{organizationID, userID}
+		if _, ok := d.members[key]; !ok {
+			return repository.ErrNotFound
+		}
+		delete(d.members, key)
+		return nil
+	})
+}
+
+func (r *organizationRepository) ListProgress(organizationID models.ID) ([]models.MemberProgress, error) {
+	progress := []models.MemberProgress{}
+	err := r.s.read(func(d *data) error {
+		// Posición de cada miembro y roadmap en progress
+		type cell struct{ roadmapID, userID models.ID }
+		index := make(map[cell]int)
+		for key := range d.members {
+			if key.organizationID != organizationID {
+				continue
+			}
+			for roadmapID, roadmap := range d.roadmaps {
+				if roadmap.OrganizationID != nil && *roadmap.OrganizationID == organizationID {
+					index[cell{roadmapID, key.userID}] = len(progress)
+					progress = append(progress, models.MemberProgress{UserID: key.userID, RoadmapID: roadmapID})
+				}
+			}
+		}
+
+		totals := make(map[models.ID]int)
+		for _, node := range d.nodes {
+			totals[node.RoadmapID]++
+		}
+		for i := range progress {
+			progress[i].TotalNodes = totals[progress[i].RoadmapID]
+		}
+
+		for _, p := range d.progress {
+			i, ok := index[cell{d.nodes[p.NodeID].RoadmapID, p.UserID}]
+			if !ok {
+				continue
+			}
+			switch p.Status {
+			case models.ProgressCompleted:
+				progress[i].Completed++
+			case models.ProgressInProgress:
+				progress[i].InProgress++
+			}
+			if last := progress[i].LastActivityAt; last == nil || p.UpdatedAt.After(*last) {
+				updatedAt := p.UpdatedAt
+				progress[i].LastActivityAt = &updatedAt
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(progress, func(i, j int) bool {
+		if progress[i].UserID != progress[j].UserID {
+			return progress[i].UserID < progress[j].UserID
+		}
+		return progress[i].RoadmapID < progress[j].RoadmapID
+	})
+	return progress, err
+}
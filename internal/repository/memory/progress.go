@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"sort"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type progressRepository struct {
+	s *Store
+}
+
+func (r *progressRepository) Upsert(progress *models.Progress) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.users[progress.UserID]; !ok {
+			return errInvalidReference
+		}
+		if _, ok := d.nodes[progress.NodeID]; !ok {
+			return errInvalidReference
+		}
+
+		progress.UpdatedAt = now(progress.UpdatedAt)
+		progress.ID = ""
+		for id, existing := range d.progress {
+			if existing.UserID == progress.UserID && existing.NodeID == progress.NodeID {
+				progress.ID = id
+				progress.CreatedAt = existing.CreatedAt
+				break
+			}
+		}
+		if progress.ID.IsZero() {
+			progress.ID = models.NewID()
+			progress.CreatedAt = progress.UpdatedAt
+		}
+
+		d.progress[progress.ID] = *progress
+		return nil
+	})
+}
+
+func (r *progressRepository) Get(userID, nodeID models.ID) (*models.Progress, error) {
+	var found *models.Progress
+	err := r.s.read(func(d *data) error {
+		for _, progress := range d.progress {
+			if progress.UserID == userID && progress.NodeID == nodeID {
+				found = &progress
+				return nil
+			}
+		}
+		return repository.ErrNotFound
+	})
+	return found, err
+}
+
+func (r *progressRepository) ListByRoadmap(userID, roadmapID models.ID) ([]models.Progress, error) {
+	progress := []models.Progress{}
+	err := r.s.read(func(d *data) error {
+		for _, p := range d.progress {
+			if p.UserID == userID && d.nodes[p.NodeID].RoadmapID == roadmapID {
+				progress = append(progress, p)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(progress, func(i, j int) bool {
+		return progress[i].UpdatedAt.Before(progress[j].UpdatedAt)
+	})
+	return progress, err
+}
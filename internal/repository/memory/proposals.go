@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type proposalRepository struct {
+	s *Store
+}
+
+func (r *proposalRepository) Create(proposal *models.Proposal) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.roadmaps[proposal.RoadmapID]; !ok {
+			return errInvalidReference
+		}
+		if _, ok := d.users[proposal.AuthorID]; !ok {
+			return errInvalidReference
+		}
+
+		proposal.ID = models.NewID()
+		proposal.CreatedAt = time.Now()
+		proposal.UpdatedAt = proposal.CreatedAt
+		d.proposals[proposal.ID] = *proposal
+		return nil
+	})
+}
+
+func (r *proposalRepository) GetByID(id models.ID) (*models.Proposal, error) {
+	var proposal models.Proposal
+	err := r.s.read(func(d *data) error {
+		var ok bool
+		if proposal, ok = d.proposals[id]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+func (r *proposalRepository) ListByRoadmap(roadmapID models.ID) ([]models.Proposal, error) {
+	return r.list(func(p models.Proposal) bool { return p.RoadmapID == roadmapID })
+}
+
+func (r *proposalRepository) ListByAuthor(authorID models.ID) ([]models.Proposal, error) {
+	return r.list(func(p models.Proposal) bool { return p.AuthorID == authorID })
+}
+
+func (r *proposalRepository) list(match func(models.Proposal) bool) ([]models.Proposal, error) {
+	proposals := []models.Proposal{}
+	err := r.s.read(func(d *data) error {
+		for _, proposal := range d.proposals {
+			if match(proposal) {
+				proposals = append(proposals, proposal)
+			}
+		}
+		return nil
+	})
+
+	sortByCreated(proposals, func(p models.Proposal) (time.Time, models.ID) { return p.CreatedAt, p.ID })
+	for i, j := 0, len(proposals)-1; i < j; i, j = i+1, j-1 {
+		proposals[i], proposals[j] = proposals[j], proposals[i]
+	}
+	return proposals, err
+}
+
+func (r *proposalRepository) Review(proposal *models.Proposal) error {
+	return r.s.write(func(d *data) error {
+		current, ok := d.proposals[proposal.ID]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		current.Status = proposal.Status
+		current.ReviewerID = proposal.ReviewerID
+		current.ReviewComment = proposal.ReviewComment
+		current.ReviewedAt = proposal.ReviewedAt
+		current.UpdatedAt = now(proposal.UpdatedAt)
+		d.proposals[proposal.ID] = current
+		return nil
+	})
+}
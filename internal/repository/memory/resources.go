@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type resourceRepository struct {
+	s *Store
+}
+
+func (r *resourceRepository) Create(resource *models.Resource) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.nodes[resource.NodeID]; !ok {
+			return errInvalidReference
+		}
+
+		resource.ID = models.NewID()
+		resource.CreatedAt = now(resource.CreatedAt)
+		resource.UpdatedAt = now(resource.UpdatedAt)
+		d.resources[resource.ID] = *resource
+		return nil
+	})
+}
+
+func (r *resourceRepository) GetByID(resourceID models.ID) (*models.Resource, error) {
+	var resource models.Resource
+	err := r.s.read(func(d *data) error {
+		var ok bool
+		if resource, ok = d.resources[resourceID]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (r *resourceRepository) ListByNode(nodeID models.ID) ([]models.Resource, error) {
+	return r.list(func(d *data, resource models.Resource) bool {
+		return resource.NodeID == nodeID
+	})
+}
+
+func (r *resourceRepository) ListByRoadmap(roadmapID models.ID) ([]models.Resource, error) {
+	return r.list(func(d *data, resource models.Resource) bool {
+		return d.nodes[resource.NodeID].RoadmapID == roadmapID
+	})
+}
+
+func (r *resourceRepository) list(match func(*data, models.Resource) bool) ([]models.Resource, error) {
+	resources := []models.Resource{}
+	err := r.s.read(func(d *data) error {
+		for _, resource := range d.resources {
+			if match(d, resource) {
+				resources = append(resources, resource)
+			}
+		}
+		return nil
+	})
+
+	sortByCreated(resources, func(r models.Resource) (time.Time, models.ID) { return r.CreatedAt, r.ID })
+	return resources, err
+}
+
+func (r *resourceRepository) Update(resource *models.Resource) error {
+	return r.s.write(func(d *data) error {
+		current, ok := d.resources[resource.ID]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		if _, ok := d.nodes[resource.NodeID]; !ok {
+			return errInvalidReference
+		}
+
+		updated := *resource
+		updated.OriginID = current.OriginID
+		updated.CreatedAt = current.CreatedAt
+		d.resources[resource.ID] = updated
+		return nil
+	})
+}
+
+func (r *resourceRepository) Delete(resourceID models.ID) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.resources[resourceID]; !ok {
+			return repository.ErrNotFound
+		}
+
+		delete(d.resources, resourceID)
+		return nil
+	})
+}
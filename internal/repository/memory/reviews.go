@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type reviewRepository struct {
+	s *Store
+}
+
+func (r *reviewRepository) Create(review *models.Review) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.roadmaps[review.RoadmapID]; !ok {
+			return errInvalidReference
+		}
+		if _, ok := d.users[review.UserID]; !ok {
+			return errInvalidReference
+		}
+		for _, existing := range d.reviews {
+			if existing.RoadmapID == review.RoadmapID && existing.UserID == review.UserID {
+				return repository.ErrConflict
+			}
+		}
+
+		review.ID = models.NewID()
+		review.CreatedAt = time.Now()
+		review.UpdatedAt = review.CreatedAt
+		d.reviews[review.ID] = *review
+		return nil
+	})
+}
+
+func (r *reviewRepository) ListByRoadmap(roadmapID models.ID) ([]models.Review, error) {
+	reviews := []models.Review{}
+	err := r.s.read(func(d *data) error {
+		for _, review := range d.reviews {
+			if review.RoadmapID == roadmapID {
+				reviews = append(reviews, review)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].CreatedAt.After(reviews[j].CreatedAt)
+	})
+	return reviews, err
+}
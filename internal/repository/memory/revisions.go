@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type revisionRepository struct {
+	s *Store
+}
+
+func (r *revisionRepository) Create(revision *models.Revision) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.roadmaps[revision.RoadmapID]; !ok {
+			return errInvalidReference
+		}
+
+		number := 0
+		for _, existing := range d.revisions {
+			if existing.RoadmapID == revision.RoadmapID && existing.Number > number {
+				number = existing.Number
+			}
+		}
+
+		revision.ID = models.NewID()
+		revision.Number = number + 1
+		revision.CreatedAt = time.Now()
+		d.revisions[revision.ID] = *revision
+		return nil
+	})
+}
+
+func (r *revisionRepository) GetByNumber(roadmapID models.ID, number int) (*models.Revision, error) {
+	var found *models.Revision
+	err := r.s.read(func(d *data) error {
+		for _, revision := range d.revisions {
+			if revision.RoadmapID == roadmapID && revision.Number == number {
+				found = &revision
+				return nil
+			}
+		}
+		return repository.ErrNotFound
+	})
+	return found, err
+}
+
+func (r *revisionRepository) GetLatest(roadmapID models.ID) (*models.Revision, error) {
+	var found *models.Revision
+	err := r.s.read(func(d *data) error {
+		for _, revision := range d.revisions {
+			if revision.RoadmapID == roadmapID && (found == nil || revision.Number > found.Number) {
+				found = &revision
+			}
+		}
+		if found == nil {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	return found, err
+}
+
+func (r *revisionRepository) ListByRoadmap(roadmapID models.ID) ([]models.Revision, error) {
+	revisions := []models.Revision{}
+	err := r.s.read(func(d *data) error {
+		for _, revision := range d.revisions {
+			if revision.RoadmapID == roadmapID {
+				revision.Snapshot = models.Snapshot{}
+				revisions = append(revisions, revision)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number > revisions[j].Number
+	})
+	return revisions, err
+}
@@ -0,0 +1,285 @@
+package memory
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type roadmapRepository struct {
+	s *Store
+}
+
+func (r *roadmapRepository) Create(roadmap *models.Roadmap) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.users[roadmap.AuthorID]; !ok {
+			return errInvalidReference
+		}
+
+		roadmap.ID = models.NewID()
+		roadmap.Version = 1
+		roadmap.CreatedAt = time.Now()
+		roadmap.UpdatedAt = roadmap.CreatedAt
+		d.roadmaps[roadmap.ID] = *roadmap
+		return nil
+	})
+}
+
+func (r *roadmapRepository) GetByID(id models.ID) (*models.Roadmap, error) {
+	var roadmap models.Roadmap
+	err := r.s.read(func(d *data) error {
+		var ok bool
+		if roadmap, ok = d.roadmaps[id]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &roadmap, nil
+}
+
+func (r *roadmapRepository) Update(roadmap *models.Roadmap) error {
+	return r.s.write(func(d *data) error {
+		current, ok := d.roadmaps[roadmap.ID]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		// El autor y la fecha de creación no se modifican
+		updated := *roadmap
+		updated.AuthorID = current.AuthorID
+		updated.CreatedAt = current.CreatedAt
+		updated.Version = current.Version + 1
+		d.roadmaps[roadmap.ID] = updated
+		roadmap.Version = updated.Version
+		return nil
+	})
+}
+
+// Lock solo lee la versión: las transacciones en memoria ya se ejecutan de
+// una en una
+func (r *roadmapRepository) Lock(id models.ID) (int, error) {
+	roadmap, err := r.GetByID(id)
+	if err != nil {
+		return 0, err
+	}
+	return roadmap.Version, nil
+}
+
+func (r *roadmapRepository) Touch(id models.ID, updatedAt time.Time) (int, error) {
+	var version int
+	err := r.s.write(func(d *data) error {
+		roadmap, ok := d.roadmaps[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		roadmap.Version++
+		roadmap.UpdatedAt = updatedAt
+		d.roadmaps[id] = roadmap
+		version = roadmap.Version
+		return nil
+	})
+	return version, err
+}
+
+func (r *roadmapRepository) Delete(id models.ID) error {
+	return r.s.write(func(d *data) error {
+		if _, ok := d.roadmaps[id]; !ok {
+			return repository.ErrNotFound
+		}
+
+		delete(d.roadmaps, id)
+		for nodeID, node := range d.nodes {
+			if node.RoadmapID == id {
+				d.deleteNode(nodeID)
+			}
+		}
+		for reviewID, review := range d.reviews {
+			if review.RoadmapID == id {
+				delete(d.reviews, reviewID)
+			}
+		}
+		for proposalID, proposal := range d.proposals {
+			if proposal.RoadmapID == id {
+				delete(d.proposals, proposalID)
+			}
+		}
+		for revisionID, revision := range d.revisions {
+			if revision.RoadmapID == id {
+				delete(d.revisions, revisionID)
+			}
+		}
+		for collaboratorID, collaborator := range d.collaborators {
+			if collaborator.RoadmapID == id {
+				delete(d.collaborators, collaboratorID)
+			}
+		}
+		for assignmentID, assignment := range d.assignments {
+			if assignment.RoadmapID == id {
+				d.deleteAssignment(assignmentID)
+			}
+		}
+		// Los forks conservan su contenido pero pierden la referencia
+		for forkID, fork := range d.roadmaps {
+			if fork.ForkedFrom != nil && *fork.ForkedFrom == id {
+				fork.ForkedFrom = nil
+				d.roadmaps[forkID] = fork
+			}
+		}
+		delete(d.views, id)
+		delete(d.likes, id)
+		delete(d.forkBases, id)
+		return nil
+	})
+}
+
+func (r *roadmapRepository) ListPublic(limit int) ([]models.RoadmapSummary, error) {
+	summaries, err := r.listSummaries(func(roadmap models.Roadmap) bool {
+		return roadmap.IsPublic
+	})
+	if limit > 0 && len(summaries) > limit {
+		summaries = summaries[:limit]
+	}
+	return summaries, err
+}
+
+func (r *roadmapRepository) ListByOrganization(organizationID models.ID) ([]models.RoadmapSummary, error) {
+	return r.listSummaries(func(roadmap models.Roadmap) bool {
+		return roadmap.OrganizationID != nil && *roadmap.OrganizationID == organizationID
+	})
+}
+
+func (r *roadmapRepository) ListForks(id models.ID) ([]models.RoadmapSummary, error) {
+	return r.listSummaries(func(roadmap models.Roadmap) bool {
+		return roadmap.ForkedFrom != nil && *roadmap.ForkedFrom == id
+	})
+}
+
+// listSummaries devuelve los roadmaps que cumplen match, del más reciente al
+// más antiguo, con su autor, visitas y valoraciones
+func (r *roadmapRepository) listSummaries(match func(models.Roadmap) bool) ([]models.RoadmapSummary, error) {
+	summaries := []models.RoadmapSummary{}
+	err := r.s.read(func(d *data) error {
+		ratings := make(map[models.ID][]int)
+		for _, review := range d.reviews {
+			ratings[review.RoadmapID] = append(ratings[review.RoadmapID], review.Rating)
+		}
+
+		for _, roadmap := range d.roadmaps {
+			if !match(roadmap) {
+				continue
+			}
+			author, ok := d.users[roadmap.AuthorID]
+			if !ok {
+				continue
+			}
+
+			summary := models.RoadmapSummary{
+				Roadmap:     roadmap,
+				Author:      author,
+				Views:       d.views[roadmap.ID],
+				ReviewCount: len(ratings[roadmap.ID]),
+			}
+			if summary.ReviewCount > 0 {
+				total := 0
+				for _, rating := range ratings[roadmap.ID] {
+					total += rating
+				}
+				summary.AvgRating = float64(total) / float64(summary.ReviewCount)
+			}
+			summaries = append(summaries, summary)
+		}
+		return nil
+	})
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].CreatedAt.After(summaries[j].CreatedAt)
+	})
+	return summaries, err
+}
+
+func (r *roadmapRepository) GetStats(id models.ID) (models.RoadmapStats, error) {
+	var stats models.RoadmapStats
+	err := r.s.read(func(d *data) error {
+		stats.Views = d.views[id]
+		stats.Favorites = d.likes[id]
+		for _, fork := range d.roadmaps {
+			if fork.IsPublic && fork.ForkedFrom != nil && *fork.ForkedFrom == id {
+				stats.Forks++
+			}
+		}
+		return nil
+	})
+	return stats, err
+}
+
+func (r *roadmapRepository) GetForkBase(id models.ID) (*models.Snapshot, error) {
+	var raw []byte
+	err := r.s.read(func(d *data) error {
+		var ok bool
+		if raw, ok = d.forkBases[id]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var base models.Snapshot
+	if err := json.Unmarshal(raw, &base); err != nil {
+		return nil, err
+	}
+	return &base, nil
+}
+
+func (r *roadmapRepository) SaveForkBase(id models.ID, base *models.Snapshot) error {
+	raw, err := json.Marshal(base)
+	if err != nil {
+		return err
+	}
+
+	return r.s.write(func(d *data) error {
+		if _, ok := d.roadmaps[id]; !ok {
+			return errInvalidReference
+		}
+		d.forkBases[id] = raw
+		return nil
+	})
+}
+
+// ListCategories agrupa los roadmaps públicos por su categoría
+func (r *roadmapRepository) ListCategories() ([]models.Facet, error) {
+	var facets []models.Facet
+	err := r.s.read(func(d *data) error {
+		counts := make(map[string]int)
+		for _, roadmap := range d.roadmaps {
+			if roadmap.IsPublic && roadmap.Category != "" {
+				counts[roadmap.Category]++
+			}
+		}
+		for name, count := range counts {
+			facets = append(facets, models.Facet{ID: name, Name: name, Count: count})
+		}
+		return nil
+	})
+
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+	return facets, err
+}
+
+// ListTags no devuelve nada: las etiquetas solo existen en postgres
+func (r *roadmapRepository) ListTags() ([]models.Facet, error) {
+	return nil, nil
+}
@@ -0,0 +1,202 @@
+// Package memory implementa los repositorios en memoria. Permite arrancar el
+// servidor y ejecutar pruebas sin PostgreSQL; los datos se pierden al salir.
+package memory
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+// errInvalidReference equivale a una violación de clave foránea
+var errInvalidReference = errors.New("referencia a un registro inexistente")
+
+// data contiene todas las tablas en memoria
+type data struct {
+	users         map[models.ID]models.User
+	roadmaps      map[models.ID]models.Roadmap
+	nodes         map[models.ID]models.Node
+	connections   map[models.ID]models.Connection
+	resources     map[models.ID]models.Resource
+	progress      map[models.ID]models.Progress
+	reviews       map[models.ID]models.Review
+	proposals     map[models.ID]models.Proposal
+	revisions     map[models.ID]models.Revision
+	collaborators map[models.ID]models.Collaborator
+	organizations map[models.ID]models.Organization
+	members       map[memberKey]models.OrganizationMember
+	assignments   map[models.ID]models.Assignment
+	assignees     map[assigneeKey]time.Time // fecha de asignación de cada asignado
+	views         map[models.ID]int
+	likes         map[models.ID]int
+	// forkBases guarda las bases de los forks serializadas, como en postgres
+	forkBases map[models.ID][]byte
+}
+
+func newData() *data {
+	return &data{
+		users:         make(map[models.ID]models.User),
+		roadmaps:      make(map[models.ID]models.Roadmap),
+		nodes:         make(map[models.ID]models.Node),
+		connections:   make(map[models.ID]models.Connection),
+		resources:     make(map[models.ID]models.Resource),
+		progress:      make(map[models.ID]models.Progress),
+		reviews:       make(map[models.ID]models.Review),
+		proposals:     make(map[models.ID]models.Proposal),
+		revisions:     make(map[models.ID]models.Revision),
+		collaborators: make(map[models.ID]models.Collaborator),
+		organizations: make(map[models.ID]models.Organization),
+		members:       make(map[memberKey]models.OrganizationMember),
+		assignments:   make(map[models.ID]models.Assignment),
+		assignees:     make(map[assigneeKey]time.Time),
+		views:         make(map[models.ID]int),
+		likes:         make(map[models.ID]int),
+		forkBases:     make(map[models.ID][]byte),
+	}
+}
+
+// clone copia todas las tablas para trabajar sobre ellas en una transacción
+func (d *data) clone() *data {
+	return &data{
+		users:         maps.Clone(d.users),
+		roadmaps:      maps.Clone(d.roadmaps),
+		nodes:         maps.Clone(d.nodes),
+		connections:   maps.Clone(d.connections),
+		resources:     maps.Clone(d.resources),
+		progress:      maps.Clone(d.progress),
+		reviews:       maps.Clone(d.reviews),
+		proposals:     maps.Clone(d.proposals),
+		revisions:     maps.Clone(d.revisions),
+		collaborators: maps.Clone(d.collaborators),
+		organizations: maps.Clone(d.organizations),
+		members:       maps.Clone(d.members),
+		assignments:   maps.Clone(d.assignments),
+		assignees:     maps.Clone(d.assignees),
+		views:         maps.Clone(d.views),
+		likes:         maps.Clone(d.likes),
+		forkBases:     maps.Clone(d.forkBases),
+	}
+}
+
+// Store implementa repository.Store en memoria
+type Store struct {
+	// mu protege data; es nil dentro de una transacción, que ya tiene el
+	// acceso exclusivo a su copia de los datos
+	mu   *sync.RWMutex
+	data *data
+}
+
+// NewStore crea un Store vacío
+func NewStore() *Store {
+	return &Store{mu: &sync.RWMutex{}, data: newData()}
+}
+
+func (s *Store) Users() repository.UserRepository                 { return &userRepository{s} }
+func (s *Store) Roadmaps() repository.RoadmapRepository           { return &roadmapRepository{s} }
+func (s *Store) Nodes() repository.NodeRepository                 { return &nodeRepository{s} }
+func (s *Store) Connections() repository.ConnectionRepository     { return &connectionRepository{s} }
+func (s *Store) Resources() repository.ResourceRepository         { return &resourceRepository{s} }
+func (s *Store) Progress() repository.ProgressRepository          { return &progressRepository{s} }
+func (s *Store) Reviews() repository.ReviewRepository             { return &reviewRepository{s} }
+func (s *Store) Proposals() repository.ProposalRepository         { return &proposalRepository{s} }
+func (s *Store) Revisions() repository.RevisionRepository         { return &revisionRepository{s} }
+func (s *Store) Collaborators() repository.CollaboratorRepository { return &collaboratorRepository{s} }
+func (s *Store) Organizations() repository.OrganizationRepository { return &organizationRepository{s} }
+func (s *Store) Assignments() repository.AssignmentRepository     { return &assignmentRepository{s} }
+
+// Transaction ejecuta fn sobre una copia de los datos y solo la publica si
+// fn termina sin error. Las transacciones se serializan entre sí y con el
+// resto de escrituras.
+func (s *Store) Transaction(fn func(tx repository.Store) error) error {
+	if s.mu == nil {
+		return fn(s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &Store{data: s.data.clone()}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.data = tx.data
+	return nil
+}
+
+// Close no hace nada; existe para cumplir repository.Store
+func (s *Store) Close() error {
+	return nil
+}
+
+// read ejecuta fn con acceso de lectura a los datos
+func (s *Store) read(fn func(d *data) error) error {
+	if s.mu != nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+	return fn(s.data)
+}
+
+// write ejecuta fn con acceso exclusivo a los datos
+func (s *Store) write(fn func(d *data) error) error {
+	if s.mu != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	return fn(s.data)
+}
+
+// now devuelve t o, si es cero, la hora actual, como hacen los DEFAULT del esquema
+func now(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
+
+// sortByCreated ordena por fecha de creación y después por ID, igual que las
+// consultas de postgres
+func sortByCreated[T any](items []T, key func(T) (time.Time, models.ID)) {
+	sort.Slice(items, func(i, j int) bool {
+		ti, idi := key(items[i])
+		tj, idj := key(items[j])
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return idi < idj
+	})
+}
+
+// deleteNode elimina un nodo con sus conexiones, recursos y progreso, y lo
+// quita de las asignaciones
+func (d *data) deleteNode(nodeID models.ID) {
+	delete(d.nodes, nodeID)
+	for id, assignment := range d.assignments {
+		if i := slices.Index(assignment.NodeIDs, nodeID); i >= 0 {
+			// Los nodos se copian para no modificar los de otra transacción
+			assignment.NodeIDs = slices.Delete(slices.Clone(assignment.NodeIDs), i, i+1)
+			d.assignments[id] = assignment
+		}
+	}
+	for id, conn := range d.connections {
+		if conn.FromNodeID == nodeID || conn.ToNodeID == nodeID {
+			delete(d.connections, id)
+		}
+	}
+	for id, resource := range d.resources {
+		if resource.NodeID == nodeID {
+			delete(d.resources, id)
+		}
+	}
+	for id, progress := range d.progress {
+		if progress.NodeID == nodeID {
+			delete(d.progress, id)
+		}
+	}
+}
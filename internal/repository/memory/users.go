@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type userRepository struct {
+	s *Store
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	return r.s.write(func(d *data) error {
+		for _, u := range d.users {
+			if u.Username == user.Username || u.Email == user.Email ||
+				(user.GoogleID != "" && u.GoogleID == user.GoogleID) {
+				return repository.ErrConflict
+			}
+		}
+
+		user.ID = models.NewID()
+		user.CreatedAt = time.Now()
+		user.UpdatedAt = user.CreatedAt
+		d.users[user.ID] = *user
+		return nil
+	})
+}
+
+func (r *userRepository) GetByID(id models.ID) (*models.User, error) {
+	return r.find(func(u models.User) bool { return u.ID == id })
+}
+
+func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+	return r.find(func(u models.User) bool { return u.Email == email })
+}
+
+func (r *userRepository) GetByUsername(username string) (*models.User, error) {
+	return r.find(func(u models.User) bool { return u.Username == username })
+}
+
+func (r *userRepository) FindForGoogle(googleID, email string) (*models.User, error) {
+	user, err := r.find(func(u models.User) bool { return u.GoogleID != "" && u.GoogleID == googleID })
+	if err == repository.ErrNotFound {
+		return r.find(func(u models.User) bool { return u.Email == email })
+	}
+	return user, err
+}
+
+func (r *userRepository) find(match func(models.User) bool) (*models.User, error) {
+	var found *models.User
+	err := r.s.read(func(d *data) error {
+		for _, u := range d.users {
+			if match(u) {
+				found = &u
+				return nil
+			}
+		}
+		return repository.ErrNotFound
+	})
+	return found, err
+}
@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"Gin/internal/models"
+	"github.com/lib/pq"
+)
+
+type assignmentRepository struct {
+	q queryer
+}
+
+const assignmentColumns = `a.id, a.roadmap_id, a.title, COALESCE(a.description, ''), a.all_nodes,
+	ARRAY(SELECT an.node_id FROM assignment_nodes an WHERE an.assignment_id = a.id ORDER BY an.node_id),
+	a.due_at, a.created_by, a.created_at, a.updated_at`
+
+func scanAssignment(row interface{ Scan(...any) error }, assignment *models.Assignment) error {
+	err := row.Scan(
+		&assignment.ID, &assignment.RoadmapID, &assignment.Title, &assignment.Description, &assignment.AllNodes,
+		pq.Array(&assignment.NodeIDs), &assignment.DueAt, &assignment.CreatedBy,
+		&assignment.CreatedAt, &assignment.UpdatedAt,
+	)
+	if assignment.NodeIDs == nil {
+		assignment.NodeIDs = []models.ID{}
+	}
+	return err
+}
+
+func (r *assignmentRepository) Create(assignment *models.Assignment) error {
+	err := r.q.QueryRow(`
+		INSERT INTO assignments (roadmap_id, title, description, all_nodes, due_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`,
+		assignment.RoadmapID, assignment.Title, assignment.Description, assignment.AllNodes,
+		assignment.DueAt, assignment.CreatedBy,
+	).Scan(&assignment.ID, &assignment.CreatedAt, &assignment.UpdatedAt)
+	if err != nil {
+		return mapError(err)
+	}
+	return r.saveNodes(assignment)
+}
+
+// saveNodes sustituye los nodos guardados de la asignación por los suyos
+func (r *assignmentRepository) saveNodes(assignment *models.Assignment) error {
+	// Un array nulo no coincidiría con ANY y no se borraría ningún nodo
+	nodeIDs := []models.ID{}
+	if !assignment.AllNodes {
+		nodeIDs = append(nodeIDs, assignment.NodeIDs...)
+	}
+
+	_, err := r.q.Exec(`
+		DELETE FROM assignment_nodes
+		WHERE assignment_id = $1 AND NOT (node_id = ANY($2::uuid[]))`,
+		assignment.ID, pq.Array(nodeIDs),
+	)
+	if err != nil {
+		return mapError(err)
+	}
+	_, err = r.q.Exec(`
+		INSERT INTO assignment_nodes (assignment_id, node_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING`,
+		assignment.ID, pq.Array(nodeIDs),
+	)
+	return mapError(err)
+}
+
+func (r *assignmentRepository) GetByID(id models.ID) (*models.Assignment, error) {
+	var assignment models.Assignment
+	err := scanAssignment(r.q.QueryRow(`SELECT `+assignmentColumns+` FROM assignments a WHERE a.id = $1`, id), &assignment)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &assignment, nil
+}
+
+func (r *assignmentRepository) ListByRoadmap(roadmapID models.ID) ([]models.Assignment, error) {
+	return r.list(`
+		FROM assignments a
+		WHERE a.roadmap_id = $1
+		ORDER BY a.due_at, a.created_at, a.id`,
+		roadmapID,
+	)
+}
+
+func (r *assignmentRepository) ListByUser(userID models.ID) ([]models.Assignment, error) {
+	return r.list(`
+		FROM assignments a
+		JOIN assignment_assignees aa ON aa.assignment_id = a.id
+		WHERE aa.user_id = $1
+		ORDER BY a.due_at, a.created_at, a.id`,
+		userID,
+	)
+}
+
+// list obtiene las asignaciones; filter contiene el FROM, WHERE y ORDER BY
+func (r *assignmentRepository) list(filter string, args ...any) ([]models.Assignment, error) {
+	rows, err := r.q.Query(`SELECT `+assignmentColumns+filter, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []models.Assignment{}
+	for rows.Next() {
+		var assignment models.Assignment
+		if err := scanAssignment(rows, &assignment); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, rows.Err()
+}
+
+func (r *assignmentRepository) Update(assignment *models.Assignment) error {
+	err := expectRows(r.q.Exec(`
+		UPDATE assignments
+		SET title = $1, description = $2, all_nodes = $3, due_at = $4, updated_at = $5
+		WHERE id = $6`,
+		assignment.Title, assignment.Description, assignment.AllNodes, assignment.DueAt,
+		now(assignment.UpdatedAt), assignment.ID,
+	))
+	if err != nil {
+		return err
+	}
+	return r.saveNodes(assignment)
+}
+
+func (r *assignmentRepository) Delete(id models.ID) error {
+	// Los nodos y los asignados se eliminan en cascada
+	return expectRows(r.q.Exec(`DELETE FROM assignments WHERE id = $1`, id))
+}
+
+func (r *assignmentRepository) AddAssignee(assignmentID, userID models.ID) error {
+	_, err := r.q.Exec(`
+		INSERT INTO assignment_assignees (assignment_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`,
+		assignmentID, userID,
+	)
+	return mapError(err)
+}
+
+func (r *assignmentRepository) RemoveAssignee(assignmentID, userID models.ID) error {
+	return expectRows(r.q.Exec(`
+		DELETE FROM assignment_assignees WHERE assignment_id = $1 AND user_id = $2`,
+		assignmentID, userID,
+	))
+}
+
+func (r *assignmentRepository) ListAssignees(assignmentID models.ID) ([]models.Assignee, error) {
+	rows, err := r.q.Query(`
+		SELECT aa.assignment_id, aa.user_id, u.username, aa.assigned_at,
+			   COUNT(DISTINCT n.id),
+			   COUNT(p.id) FILTER (WHERE p.status = 'completed'),
+			   COUNT(p.id) FILTER (WHERE p.status = 'in_progress'),
+			   MAX(p.completed_at) FILTER (WHERE p.status = 'completed'),
+			   MAX(p.updated_at)
+		FROM assignment_assignees aa
+		JOIN assignments a ON a.id = aa.assignment_id
+		JOIN users u ON u.id = aa.user_id
+		LEFT JOIN roadmap_nodes n ON n.roadmap_id = a.roadmap_id AND (a.all_nodes OR EXISTS (
+			SELECT 1 FROM assignment_nodes an WHERE an.assignment_id = a.id AND an.node_id = n.id
+		))
+		LEFT JOIN user_progress p ON p.node_id = n.id AND p.user_id = aa.user_id
+		WHERE aa.assignment_id = $1
+		GROUP BY aa.assignment_id, aa.user_id, u.username, aa.assigned_at
+		ORDER BY aa.assigned_at, aa.user_id`,
+		assignmentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignees := []models.Assignee{}
+	for rows.Next() {
+		var a models.Assignee
+		err := rows.Scan(
+			&a.AssignmentID, &a.UserID, &a.Username, &a.AssignedAt,
+			&a.TotalNodes, &a.Completed, &a.InProgress, &a.CompletedAt, &a.LastActivityAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		assignees = append(assignees, a)
+	}
+	return assignees, rows.Err()
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"Gin/internal/models"
+)
+
+type collaboratorRepository struct {
+	q queryer
+}
+
+const collaboratorColumns = `c.id, c.roadmap_id, c.user_id, u.username, c.role, c.status, c.invited_by,
+	c.accepted_at, c.created_at, c.updated_at`
+
+func scanCollaborator(row interface{ Scan(...any) error }, collaborator *models.Collaborator) error {
+	return row.Scan(
+		&collaborator.ID, &collaborator.RoadmapID, &collaborator.UserID, &collaborator.Username,
+		&collaborator.Role, &collaborator.Status, &collaborator.InvitedBy, &collaborator.AcceptedAt,
+		&collaborator.CreatedAt, &collaborator.UpdatedAt,
+	)
+}
+
+func (r *collaboratorRepository) Create(collaborator *models.Collaborator) error {
+	err := r.q.QueryRow(`
+		WITH inserted AS (
+			INSERT INTO roadmap_collaborators (roadmap_id, user_id, role, status, invited_by, accepted_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, user_id, created_at, updated_at
+		)
+		SELECT i.id, u.username, i.created_at, i.updated_at
+		FROM inserted i
+		JOIN users u ON u.id = i.user_id`,
+		collaborator.RoadmapID, collaborator.UserID, collaborator.Role, collaborator.Status,
+		collaborator.InvitedBy, collaborator.AcceptedAt,
+	).Scan(&collaborator.ID, &collaborator.Username, &collaborator.CreatedAt, &collaborator.UpdatedAt)
+	return mapError(err)
+}
+
+func (r *collaboratorRepository) GetByID(id models.ID) (*models.Collaborator, error) {
+	return r.get(`c.id = $1`, id)
+}
+
+func (r *collaboratorRepository) Get(roadmapID, userID models.ID) (*models.Collaborator, error) {
+	return r.get(`c.roadmap_id = $1 AND c.user_id = $2`, roadmapID, userID)
+}
+
+func (r *collaboratorRepository) get(filter string, args ...any) (*models.Collaborator, error) {
+	var collaborator models.Collaborator
+	row := r.q.QueryRow(`
+		SELECT `+collaboratorColumns+`
+		FROM roadmap_collaborators c
+		JOIN users u ON u.id = c.user_id
+		WHERE `+filter,
+		args...,
+	)
+	if err := scanCollaborator(row, &collaborator); err != nil {
+		return nil, mapError(err)
+	}
+	return &collaborator, nil
+}
+
+func (r *collaboratorRepository) ListByRoadmap(roadmapID models.ID) ([]models.Collaborator, error) {
+	return r.list(`c.roadmap_id = $1 ORDER BY c.created_at, c.id`, roadmapID)
+}
+
+func (r *collaboratorRepository) ListByUser(userID models.ID, status models.CollaboratorStatus) ([]models.Collaborator, error) {
+	return r.list(`c.user_id = $1 AND c.status = $2 ORDER BY c.created_at DESC, c.id DESC`, userID, status)
+}
+
+// list obtiene los colaboradores; filter contiene el WHERE y el ORDER BY
+func (r *collaboratorRepository) list(filter string, args ...any) ([]models.Collaborator, error) {
+	rows, err := r.q.Query(`
+		SELECT `+collaboratorColumns+`
+		FROM roadmap_collaborators c
+		JOIN users u ON u.id = c.user_id
+		WHERE `+filter,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collaborators := []models.Collaborator{}
+	for rows.Next() {
+		var collaborator models.Collaborator
+		if err := scanCollaborator(rows, &collaborator); err != nil {
+			return nil, err
+		}
+		collaborators = append(collaborators, collaborator)
+	}
+	return collaborators, rows.Err()
+}
+
+func (r *collaboratorRepository) Update(collaborator *models.Collaborator) error {
+	return expectRows(r.q.Exec(`
+		UPDATE roadmap_collaborators
+		SET role = $1, status = $2, accepted_at = $3, updated_at = $4
+		WHERE id = $5`,
+		collaborator.Role, collaborator.Status, collaborator.AcceptedAt, now(collaborator.UpdatedAt), collaborator.ID,
+	))
+}
+
+func (r *collaboratorRepository) Delete(id models.ID) error {
+	return expectRows(r.q.Exec(`DELETE FROM roadmap_collaborators WHERE id = $1`, id))
+}
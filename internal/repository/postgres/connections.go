@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"Gin/internal/models"
+)
+
+type connectionRepository struct {
+	q queryer
+}
+
+const connectionColumns = `id, roadmap_id, source_node_id, target_node_id, COALESCE(label, ''), type,
+	origin_id, created_at, updated_at`
+
+func scanConnection(row interface{ Scan(...any) error }, conn *models.Connection) error {
+	return row.Scan(
+		&conn.ID, &conn.RoadmapID, &conn.FromNodeID, &conn.ToNodeID, &conn.Label,
+		&conn.ConnectionType, &conn.OriginID, &conn.CreatedAt, &conn.UpdatedAt,
+	)
+}
+
+func (r *connectionRepository) Create(conn *models.Connection) error {
+	conn.CreatedAt = now(conn.CreatedAt)
+	conn.UpdatedAt = now(conn.UpdatedAt)
+	err := r.q.QueryRow(`
+		INSERT INTO node_connections (roadmap_id, source_node_id, target_node_id, label, type, origin_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		conn.RoadmapID, conn.FromNodeID, conn.ToNodeID, conn.Label, conn.ConnectionType, conn.OriginID,
+		conn.CreatedAt, conn.UpdatedAt,
+	).Scan(&conn.ID)
+	return mapError(err)
+}
+
+func (r *connectionRepository) GetByID(roadmapID, connID models.ID) (*models.Connection, error) {
+	var conn models.Connection
+	err := scanConnection(r.q.QueryRow(`
+		SELECT `+connectionColumns+`
+		FROM node_connections WHERE id = $1 AND roadmap_id = $2`,
+		connID, roadmapID,
+	), &conn)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &conn, nil
+}
+
+func (r *connectionRepository) ListByRoadmap(roadmapID models.ID) ([]models.Connection, error) {
+	rows, err := r.q.Query(`
+		SELECT `+connectionColumns+`
+		FROM node_connections
+		WHERE roadmap_id = $1
+		ORDER BY created_at, id`,
+		roadmapID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	connections := []models.Connection{}
+	for rows.Next() {
+		var conn models.Connection
+		if err := scanConnection(rows, &conn); err != nil {
+			return nil, err
+		}
+		connections = append(connections, conn)
+	}
+	return connections, rows.Err()
+}
+
+func (r *connectionRepository) Exists(roadmapID, fromNodeID, toNodeID models.ID) (bool, error) {
+	var exists bool
+	err := r.q.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1
+			FROM node_connections
+			WHERE roadmap_id = $1 AND source_node_id = $2 AND target_node_id = $3
+		)`,
+		roadmapID, fromNodeID, toNodeID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *connectionRepository) Update(conn *models.Connection) error {
+	return expectRows(r.q.Exec(`
+		UPDATE node_connections
+		SET source_node_id = $1, target_node_id = $2, label = $3, type = $4, updated_at = $5
+		WHERE id = $6 AND roadmap_id = $7`,
+		conn.FromNodeID, conn.ToNodeID, conn.Label, conn.ConnectionType, conn.UpdatedAt, conn.ID, conn.RoadmapID,
+	))
+}
+
+func (r *connectionRepository) Delete(roadmapID, connID models.ID) error {
+	return expectRows(r.q.Exec(`DELETE FROM node_connections WHERE id = $1 AND roadmap_id = $2`, connID, roadmapID))
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"Gin/internal/models"
+	"Gin/internal/repository"
+)
+
+type nodeRepository struct {
+	q queryer
+}
+
+const nodeColumns = `id, roadmap_id, title, COALESCE(description, ''), type, position_x, position_y,
+	status, COALESCE(color, ''), order_index, estimated_hours, origin_id, version, created_at, updated_at`
+
+func scanNode(row interface{ Scan(...any) error }, node *models.Node) error {
+	return row.Scan(
+		&node.ID, &node.RoadmapID, &node.Title, &node.Description, &node.Type,
+		&node.Position.X, &node.Position.Y, &node.Status, &node.Color, &node.OrderIndex, &node.Estimate,
+		&node.OriginID, &node.Version, &node.CreatedAt, &node.UpdatedAt,
+	)
+}
+
+func (r *nodeRepository) Create(node *models.Node) error {
+	node.CreatedAt = now(node.CreatedAt)
+	node.UpdatedAt = now(node.UpdatedAt)
+	err := r.q.QueryRow(`
+		INSERT INTO roadmap_nodes (roadmap_id, title, description, type, position_x, position_y, color, status, order_index, estimated_hours,
+			origin_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id, version`,
+		node.RoadmapID, node.Title, node.Description, node.Type, node.Position.X, node.Position.Y,
+		node.Color, node.Status, node.OrderIndex, node.Estimate, node.OriginID, node.CreatedAt, node.UpdatedAt,
+	).Scan(&node.ID, &node.Version)
+	return mapError(err)
+}
+
+func (r *nodeRepository) GetByID(roadmapID, nodeID models.ID) (*models.Node, error) {
+	var node models.Node
+	err := scanNode(r.q.QueryRow(`
+		SELECT `+nodeColumns+`
+		FROM roadmap_nodes WHERE id = $1 AND roadmap_id = $2`,
+		nodeID, roadmapID,
+	), &node)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &node, nil
+}
+
+func (r *nodeRepository) ListByRoadmap(roadmapID models.ID) ([]models.Node, error) {
+	rows, err := r.q.Query(`
+		SELECT `+nodeColumns+`
+		FROM roadmap_nodes
+		WHERE roadmap_id = $1
+		ORDER BY created_at, id`,
+		roadmapID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := []models.Node{}
+	for rows.Next() {
+		var node models.Node
+		if err := scanNode(rows, &node); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, rows.Err()
+}
+
+func (r *nodeRepository) Update(node *models.Node) error {
+	err := r.q.QueryRow(`
+		UPDATE roadmap_nodes
+		SET title = $1, description = $2, type = $3, position_x = $4, position_y = $5, status = $6, color = $7,
+			order_index = $8, estimated_hours = $9, updated_at = $10, version = version + 1
+		WHERE id = $11 AND roadmap_id = $12 AND version = $13
+		RETURNING version`,
+		node.Title, node.Description, node.Type, node.Position.X, node.Position.Y, node.Status, node.Color,
+		node.OrderIndex, node.Estimate, node.UpdatedAt, node.ID, node.RoadmapID, node.Version,
+	).Scan(&node.Version)
+	return r.versionError(node.RoadmapID, node.ID, err)
+}
+
+func (r *nodeRepository) UpdatePosition(roadmapID, nodeID models.ID, position models.Position, version int, updatedAt time.Time) (int, error) {
+	err := r.q.QueryRow(`
+		UPDATE roadmap_nodes
+		SET position_x = $1, position_y = $2, updated_at = $3, version = version + 1
+		WHERE id = $4 AND roadmap_id = $5 AND ($6 = 0 OR version = $6)
+		RETURNING version`,
+		position.X, position.Y, updatedAt, nodeID, roadmapID, version,
+	).Scan(&version)
+	return version, r.versionError(roadmapID, nodeID, err)
+}
+
+// versionError distingue, cuando una actualización no afectó a ninguna fila,
+// si el nodo no existe o si cambió de versión
+func (r *nodeRepository) versionError(roadmapID, nodeID models.ID, err error) error {
+	if !errors.Is(err, sql.ErrNoRows) {
+		return mapError(err)
+	}
+	if _, err := r.GetByID(roadmapID, nodeID); err != nil {
+		return err
+	}
+	return repository.ErrVersionConflict
+}
+
+func (r *nodeRepository) Delete(roadmapID, nodeID models.ID) error {
+	// Conexiones, recursos y progreso se eliminan en cascada
+	return expectRows(r.q.Exec(`DELETE FROM roadmap_nodes WHERE id = $1 AND roadmap_id = $2`, nodeID, roadmapID))
+}
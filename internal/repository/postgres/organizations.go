@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"Gin/internal/models"
+)
+
+type organizationRepository struct {
+	q queryer
+}
+
+const organizationColumns = `o.id, o.name, o.slug, COALESCE(o.description, ''), o.created_by, o.created_at, o.updated_at`
+
+func scanOrganization(row interface{ Scan(...any) error }, organization *models.Organization, extra ...any) error {
+	return row.Scan(append([]any{
+		&organization.ID, &organization.Name, &organization.Slug, &organization.Description,
+		&organization.CreatedBy, &organization.CreatedAt, &organization.UpdatedAt,
+	}, extra...)...)
+}
+
+const memberColumns = `m.organization_id, m.user_id, u.username, m.role, m.joined_at`
+
+func scanMember(row interface{ Scan(...any) error }, member *models.OrganizationMember) error {
+	return row.Scan(&member.OrganizationID, &member.UserID, &member.Username, &member.Role, &member.JoinedAt)
+}
+
+func (r *organizationRepository) Create(organization *models.Organization) error {
+	err := r.q.QueryRow(`
+		INSERT INTO organizations (name, slug, description, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		organization.Name, organization.Slug, organization.Description, organization.CreatedBy,
+	).Scan(&organization.ID, &organization.CreatedAt, &organization.UpdatedAt)
+	return mapError(err)
+}
+
+func (r *organizationRepository) GetByID(id models.ID) (*models.Organization, error) {
+	var organization models.Organization
+	err := scanOrganization(r.q.QueryRow(`SELECT `+organizationColumns+` FROM organizations o WHERE o.id = $1`, id), &organization)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) ListByUser(userID models.ID) ([]models.Organization, error) {
+	rows, err := r.q.Query(`
+		SELECT `+organizationColumns+`, m.role
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.name, o.id`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := []models.Organization{}
+	for rows.Next() {
+		var organization models.Organization
+		if err := scanOrganization(rows, &organization, &organization.Role); err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, organization)
+	}
+	return organizations, rows.Err()
+}
+
+func (r *organizationRepository) Update(organization *models.Organization) error {
+	return expectRows(r.q.Exec(`
+		UPDATE organizations
+		SET name = $1, slug = $2, description = $3, updated_at = $4
+		WHERE id = $5`,
+		organization.Name, organization.Slug, organization.Description, now(organization.UpdatedAt), organization.ID,
+	))
+}
+
+func (r *organizationRepository) Delete(id models.ID) error {
+	// Los miembros se eliminan en cascada y los roadmaps quedan sin organización
+	return expectRows(r.q.Exec(`DELETE FROM organizations WHERE id = $1`, id))
+}
+
+func (r *organizationRepository) AddMember(member *models.OrganizationMember) error {
+	err := r.q.QueryRow(`
+		WITH inserted AS (
+			INSERT INTO organization_members (organization_id, user_id, role)
+			VALUES ($1, $2, $3)
+			RETURNING user_id, joined_at
+		)
+		SELECT u.username, i.joined_at
+		FROM inserted i
+		JOIN users u ON u.id = i.user_id`,
+		member.OrganizationID, member.UserID, member.Role,
+	).Scan(&member.Username, &member.JoinedAt)
+	return mapError(err)
+}
+
+func (r *organizationRepository) GetMember(organizationID, userID models.ID) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := scanMember(r.q.QueryRow(`
+		SELECT `+memberColumns+`
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1 AND m.user_id = $2`,
+		organizationID, userID,
+	), &member)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &member, nil
+}
+
+func (r *organizationRepository) ListMembers(organizationID models.ID) ([]models.OrganizationMember, error) {
+	rows, err := r.q.Query(`
+		SELECT `+memberColumns+`
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.joined_at, m.user_id`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.OrganizationMember{}
+	for rows.Next() {
+		var member models.OrganizationMember
+		if err := scanMember(rows, &member); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func (r *organizationRepository) UpdateMember(member *models.OrganizationMember) error {
+	return expectRows(r.q.Exec(`
+		UPDATE organization_members SET role = $1
+		WHERE organization_id = $2 AND user_id = $3`,
+		member.Role, member.OrganizationID, member.UserID,
+	))
+}
+
+func (r *organizationRepository) RemoveMember(organizationID, userID models.ID) error {
+	return expectRows(r.q.Exec(`
+		DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`,
+		organizationID, userID,
+	))
+}
+
+func (r *organizationRepository) ListProgress(organizationID models.ID) ([]models.MemberProgress, error) {
+	rows, err := r.q.Query(`
+		SELECT m.user_id, r.id,
+			   COUNT(DISTINCT n.id),
+			   COUNT(p.id) FILTER (WHERE p.status = 'completed'),
+			   COUNT(p.id) FILTER (WHERE p.status = 'in_progress'),
+			   MAX(p.updated_at)
+		FROM organization_members m
+		JOIN roadmaps r ON r.organization_id = m.organization_id
+		LEFT JOIN roadmap_nodes n ON n.roadmap_id = r.id
+		LEFT JOIN user_progress p ON p.node_id = n.id AND p.user_id = m.user_id
+		WHERE m.organization_id = $1
+		GROUP BY m.user_id, r.id
+		ORDER BY m.user_id, r.id`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := []models.MemberProgress{}
+	for rows.Next() {
+		var p models.MemberProgress
+		err := rows.Scan(&p.UserID, &p.RoadmapID, &p.TotalNodes, &p.Completed, &p.InProgress, &p.LastActivityAt)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, p)
+	}
+	return progress, rows.Err()
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"Gin/internal/models"
+)
+
+type progressRepository struct {
+	q queryer
+}
+
+const progressColumns = `p.id, p.user_id, p.node_id, p.status, COALESCE(p.notes, ''), p.completed_at, p.created_at, p.updated_at`
+
+func scanProgress(row interface{ Scan(...any) error }, progress *models.Progress) error {
+	return row.Scan(
+		&progress.ID, &progress.UserID, &progress.NodeID, &progress.Status, &progress.Notes,
+		&progress.CompletedAt, &progress.CreatedAt, &progress.UpdatedAt,
+	)
+}
+
+func (r *progressRepository) Upsert(progress *models.Progress) error {
+	err := r.q.QueryRow(`
+		INSERT INTO user_progress (user_id, node_id, status, notes, completed_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (user_id, node_id) DO UPDATE
+		SET status = EXCLUDED.status, notes = EXCLUDED.notes,
+			completed_at = EXCLUDED.completed_at, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at`,
+		progress.UserID, progress.NodeID, progress.Status, progress.Notes, progress.CompletedAt, progress.UpdatedAt,
+	).Scan(&progress.ID, &progress.CreatedAt)
+	return mapError(err)
+}
+
+func (r *progressRepository) Get(userID, nodeID models.ID) (*models.Progress, error) {
+	var progress models.Progress
+	err := scanProgress(r.q.QueryRow(`
+		SELECT `+progressColumns+`
+		FROM user_progress p
+		WHERE p.user_id = $1 AND p.node_id = $2`,
+		userID, nodeID,
+	), &progress)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &progress, nil
+}
+
+func (r *progressRepository) ListByRoadmap(userID, roadmapID models.ID) ([]models.Progress, error) {
+	rows, err := r.q.Query(`
+		SELECT `+progressColumns+`
+		FROM user_progress p
+		JOIN roadmap_nodes n ON p.node_id = n.id
+		WHERE p.user_id = $1 AND n.roadmap_id = $2
+		ORDER BY p.updated_at`,
+		userID, roadmapID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := []models.Progress{}
+	for rows.Next() {
+		var p models.Progress
+		if err := scanProgress(rows, &p); err != nil {
+			return nil, err
+		}
+		progress = append(progress, p)
+	}
+	return progress, rows.Err()
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"encoding/json"
+
+	"Gin/internal/models"
+)
+
+type proposalRepository struct {
+	q queryer
+}
+
+const proposalColumns = `id, roadmap_id, author_id, title, COALESCE(description, ''), status,
+	operations, base, proposed, reviewer_id, COALESCE(review_comment, ''), reviewed_at,
+	created_at, updated_at`
+
+func scanProposal(row interface{ Scan(...any) error }, proposal *models.Proposal) error {
+	var operations, base, proposed []byte
+	if err := row.Scan(
+		&proposal.ID, &proposal.RoadmapID, &proposal.AuthorID, &proposal.Title, &proposal.Description,
+		&proposal.Status, &operations, &base, &proposed, &proposal.ReviewerID, &proposal.ReviewComment,
+		&proposal.ReviewedAt, &proposal.CreatedAt, &proposal.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(operations, &proposal.Operations); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(base, &proposal.Base); err != nil {
+		return err
+	}
+	return json.Unmarshal(proposed, &proposal.Proposed)
+}
+
+func (r *proposalRepository) Create(proposal *models.Proposal) error {
+	operations, err := json.Marshal(proposal.Operations)
+	if err != nil {
+		return err
+	}
+	base, err := json.Marshal(proposal.Base)
+	if err != nil {
+		return err
+	}
+	proposed, err := json.Marshal(proposal.Proposed)
+	if err != nil {
+		return err
+	}
+
+	err = r.q.QueryRow(`
+		INSERT INTO roadmap_proposals (roadmap_id, author_id, title, description, status, operations, base, proposed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`,
+		proposal.RoadmapID, proposal.AuthorID, proposal.Title, proposal.Description, proposal.Status,
+		operations, base, proposed,
+	).Scan(&proposal.ID, &proposal.CreatedAt, &proposal.UpdatedAt)
+	return mapError(err)
+}
+
+func (r *proposalRepository) GetByID(id models.ID) (*models.Proposal, error) {
+	var proposal models.Proposal
+	row := r.q.QueryRow(`SELECT `+proposalColumns+` FROM roadmap_proposals WHERE id = $1`, id)
+	if err := scanProposal(row, &proposal); err != nil {
+		return nil, mapError(err)
+	}
+	return &proposal, nil
+}
+
+func (r *proposalRepository) ListByRoadmap(roadmapID models.ID) ([]models.Proposal, error) {
+	return r.list(`roadmap_id = $1`, roadmapID)
+}
+
+func (r *proposalRepository) ListByAuthor(authorID models.ID) ([]models.Proposal, error) {
+	return r.list(`author_id = $1`, authorID)
+}
+
+func (r *proposalRepository) list(filter string, args ...any) ([]models.Proposal, error) {
+	rows, err := r.q.Query(`
+		SELECT `+proposalColumns+`
+		FROM roadmap_proposals
+		WHERE `+filter+`
+		ORDER BY created_at DESC, id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	proposals := []models.Proposal{}
+	for rows.Next() {
+		var proposal models.Proposal
+		if err := scanProposal(rows, &proposal); err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals, rows.Err()
+}
+
+func (r *proposalRepository) Review(proposal *models.Proposal) error {
+	return expectRows(r.q.Exec(`
+		UPDATE roadmap_proposals
+		SET status = $1, reviewer_id = $2, review_comment = $3, reviewed_at = $4, updated_at = $5
+		WHERE id = $6`,
+		proposal.Status, proposal.ReviewerID, proposal.ReviewComment, proposal.ReviewedAt,
+		now(proposal.UpdatedAt), proposal.ID,
+	))
+}
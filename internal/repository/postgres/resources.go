@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"Gin/internal/models"
+)
+
+type resourceRepository struct {
+	q queryer
+}
+
+const resourceColumns = `r.id, r.node_id, r.title, r.type, r.url, COALESCE(r.description, ''),
+	r.origin_id, r.created_at, r.updated_at`
+
+func scanResource(row interface{ Scan(...any) error }, resource *models.Resource) error {
+	return row.Scan(
+		&resource.ID, &resource.NodeID, &resource.Title, &resource.Type, &resource.URL,
+		&resource.Description, &resource.OriginID, &resource.CreatedAt, &resource.UpdatedAt,
+	)
+}
+
+func (r *resourceRepository) Create(resource *models.Resource) error {
+	resource.CreatedAt = now(resource.CreatedAt)
+	resource.UpdatedAt = now(resource.UpdatedAt)
+	err := r.q.QueryRow(`
+		INSERT INTO node_resources (node_id, title, type, url, description, origin_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		resource.NodeID, resource.Title, resource.Type, resource.URL, resource.Description,
+		resource.OriginID, resource.CreatedAt, resource.UpdatedAt,
+	).Scan(&resource.ID)
+	return mapError(err)
+}
+
+func (r *resourceRepository) GetByID(resourceID models.ID) (*models.Resource, error) {
+	var resource models.Resource
+	err := scanResource(r.q.QueryRow(`SELECT `+resourceColumns+` FROM node_resources r WHERE r.id = $1`, resourceID), &resource)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &resource, nil
+}
+
+func (r *resourceRepository) ListByNode(nodeID models.ID) ([]models.Resource, error) {
+	return r.list(`
+		SELECT `+resourceColumns+`
+		FROM node_resources r
+		WHERE r.node_id = $1
+		ORDER BY r.created_at, r.id`,
+		nodeID,
+	)
+}
+
+func (r *resourceRepository) ListByRoadmap(roadmapID models.ID) ([]models.Resource, error) {
+	return r.list(`
+		SELECT `+resourceColumns+`
+		FROM node_resources r
+		JOIN roadmap_nodes n ON r.node_id = n.id
+		WHERE n.roadmap_id = $1
+		ORDER BY r.created_at, r.id`,
+		roadmapID,
+	)
+}
+
+func (r *resourceRepository) list(query string, args ...any) ([]models.Resource, error) {
+	rows, err := r.q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resources := []models.Resource{}
+	for rows.Next() {
+		var resource models.Resource
+		if err := scanResource(rows, &resource); err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return resources, rows.Err()
+}
+
+func (r *resourceRepository) Update(resource *models.Resource) error {
+	return expectRows(r.q.Exec(`
+		UPDATE node_resources
+		SET node_id = $1, title = $2, type = $3, url = $4, description = $5, updated_at = $6
+		WHERE id = $7`,
+		resource.NodeID, resource.Title, resource.Type, resource.URL, resource.Description, resource.UpdatedAt,
+		resource.ID,
+	))
+}
+
+func (r *resourceRepository) Delete(resourceID models.ID) error {
+	return expectRows(r.q.Exec(`DELETE FROM node_resources WHERE id = $1`, resourceID))
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"Gin/internal/models"
+)
+
+type reviewRepository struct {
+	q queryer
+}
+
+func (r *reviewRepository) Create(review *models.Review) error {
+	err := r.q.QueryRow(`
+		INSERT INTO reviews (roadmap_id, user_id, rating, comment)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		review.RoadmapID, review.UserID, review.Rating, review.Comment,
+	).Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+	return mapError(err)
+}
+
+func (r *reviewRepository) ListByRoadmap(roadmapID models.ID) ([]models.Review, error) {
+	rows, err := r.q.Query(`
+		SELECT id, roadmap_id, user_id, rating, COALESCE(comment, ''), created_at, updated_at
+		FROM reviews
+		WHERE roadmap_id = $1
+		ORDER BY created_at DESC`,
+		roadmapID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		var review models.Review
+		if err := rows.Scan(
+			&review.ID, &review.RoadmapID, &review.UserID, &review.Rating, &review.Comment,
+			&review.CreatedAt, &review.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"encoding/json"
+
+	"Gin/internal/models"
+)
+
+type revisionRepository struct {
+	q queryer
+}
+
+const revisionColumns = `id, roadmap_id, number, author_id, action, restored_from, details, created_at`
+
+func scanRevision(row interface{ Scan(...any) error }, revision *models.Revision, dest ...any) error {
+	var details []byte
+	err := row.Scan(append([]any{
+		&revision.ID, &revision.RoadmapID, &revision.Number, &revision.AuthorID, &revision.Action,
+		&revision.RestoredFrom, &details, &revision.CreatedAt,
+	}, dest...)...)
+	if err != nil || details == nil {
+		return err
+	}
+	revision.Details = &models.RevisionDetails{}
+	return json.Unmarshal(details, revision.Details)
+}
+
+func (r *revisionRepository) Create(revision *models.Revision) error {
+	snapshot, err := json.Marshal(revision.Snapshot)
+	if err != nil {
+		return err
+	}
+	var details []byte
+	if revision.Details != nil {
+		if details, err = json.Marshal(revision.Details); err != nil {
+			return err
+		}
+	}
+
+	// Dos revisiones simultáneas del mismo roadmap chocan en la restricción
+	// UNIQUE y la segunda devuelve ErrConflict
+	err = r.q.QueryRow(`
+		INSERT INTO roadmap_revisions (roadmap_id, number, author_id, action, restored_from, details, snapshot)
+		SELECT $1, COALESCE(MAX(number), 0) + 1, $2, $3, $4, $5, $6
+		FROM roadmap_revisions
+		WHERE roadmap_id = $1
+		RETURNING id, number, created_at`,
+		revision.RoadmapID, revision.AuthorID, revision.Action, revision.RestoredFrom, details, snapshot,
+	).Scan(&revision.ID, &revision.Number, &revision.CreatedAt)
+	return mapError(err)
+}
+
+func (r *revisionRepository) GetByNumber(roadmapID models.ID, number int) (*models.Revision, error) {
+	return r.get(`
+		SELECT `+revisionColumns+`, snapshot
+		FROM roadmap_revisions
+		WHERE roadmap_id = $1 AND number = $2`,
+		roadmapID, number,
+	)
+}
+
+func (r *revisionRepository) GetLatest(roadmapID models.ID) (*models.Revision, error) {
+	return r.get(`
+		SELECT `+revisionColumns+`, snapshot
+		FROM roadmap_revisions
+		WHERE roadmap_id = $1
+		ORDER BY number DESC
+		LIMIT 1`,
+		roadmapID,
+	)
+}
+
+func (r *revisionRepository) get(query string, args ...any) (*models.Revision, error) {
+	var revision models.Revision
+	var snapshot []byte
+	if err := scanRevision(r.q.QueryRow(query, args...), &revision, &snapshot); err != nil {
+		return nil, mapError(err)
+	}
+	if err := json.Unmarshal(snapshot, &revision.Snapshot); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *revisionRepository) ListByRoadmap(roadmapID models.ID) ([]models.Revision, error) {
+	rows, err := r.q.Query(`
+		SELECT `+revisionColumns+`
+		FROM roadmap_revisions
+		WHERE roadmap_id = $1
+		ORDER BY number DESC`,
+		roadmapID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.Revision{}
+	for rows.Next() {
+		var revision models.Revision
+		if err := scanRevision(rows, &revision); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, rows.Err()
+}
@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"Gin/internal/models"
+)
+
+type roadmapRepository struct {
+	q queryer
+}
+
+const roadmapColumns = `id, title, COALESCE(description, ''), COALESCE(category, ''), author_id,
+	is_public, forked_from, forked_at, organization_id, version, created_at, updated_at`
+
+func scanRoadmap(row interface{ Scan(...any) error }, roadmap *models.Roadmap) error {
+	return row.Scan(
+		&roadmap.ID, &roadmap.Title, &roadmap.Description, &roadmap.Category, &roadmap.AuthorID,
+		&roadmap.IsPublic, &roadmap.ForkedFrom, &roadmap.ForkedAt, &roadmap.OrganizationID, &roadmap.Version,
+		&roadmap.CreatedAt, &roadmap.UpdatedAt,
+	)
+}
+
+func (r *roadmapRepository) Create(roadmap *models.Roadmap) error {
+	err := r.q.QueryRow(`
+		INSERT INTO roadmaps (title, description, category, author_id, is_public, forked_from, forked_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, version, created_at, updated_at`,
+		roadmap.Title, roadmap.Description, roadmap.Category, roadmap.AuthorID, roadmap.IsPublic,
+		roadmap.ForkedFrom, roadmap.ForkedAt, roadmap.OrganizationID,
+	).Scan(&roadmap.ID, &roadmap.Version, &roadmap.CreatedAt, &roadmap.UpdatedAt)
+	return mapError(err)
+}
+
+func (r *roadmapRepository) GetByID(id models.ID) (*models.Roadmap, error) {
+	var roadmap models.Roadmap
+	err := scanRoadmap(r.q.QueryRow(`SELECT `+roadmapColumns+` FROM roadmaps WHERE id = $1`, id), &roadmap)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &roadmap, nil
+}
+
+func (r *roadmapRepository) Update(roadmap *models.Roadmap) error {
+	err := r.q.QueryRow(`
+		UPDATE roadmaps
+		SET title = $1, description = $2, category = $3, is_public = $4, forked_from = $5, forked_at = $6,
+			organization_id = $7, updated_at = $8, version = version + 1
+		WHERE id = $9
+		RETURNING version`,
+		roadmap.Title, roadmap.Description, roadmap.Category, roadmap.IsPublic, roadmap.ForkedFrom, roadmap.ForkedAt,
+		roadmap.OrganizationID, roadmap.UpdatedAt, roadmap.ID,
+	).Scan(&roadmap.Version)
+	return mapError(err)
+}
+
+func (r *roadmapRepository) Lock(id models.ID) (int, error) {
+	var version int
+	err := r.q.QueryRow(`SELECT version FROM roadmaps WHERE id = $1 FOR UPDATE`, id).Scan(&version)
+	return version, mapError(err)
+}
+
+func (r *roadmapRepository) Touch(id models.ID, updatedAt time.Time) (int, error) {
+	var version int
+	err := r.q.QueryRow(`
+		UPDATE roadmaps SET version = version + 1, updated_at = $1
+		WHERE id = $2
+		RETURNING version`,
+		updatedAt, id,
+	).Scan(&version)
+	return version, mapError(err)
+}
+
+func (r *roadmapRepository) Delete(id models.ID) error {
+	// Nodos, conexiones, recursos, progreso, reseñas y visitas se eliminan en cascada
+	return expectRows(r.q.Exec(`DELETE FROM roadmaps WHERE id = $1`, id))
+}
+
+func (r *roadmapRepository) ListPublic(limit int) ([]models.RoadmapSummary, error) {
+	return r.listSummaries(`
+		WHERE r.is_public = true
+		ORDER BY r.created_at DESC
+		LIMIT $1`,
+		limit,
+	)
+}
+
+func (r *roadmapRepository) ListForks(id models.ID) ([]models.RoadmapSummary, error) {
+	return r.listSummaries(`
+		WHERE r.forked_from = $1
+		ORDER BY r.created_at DESC`,
+		id,
+	)
+}
+
+func (r *roadmapRepository) ListByOrganization(organizationID models.ID) ([]models.RoadmapSummary, error) {
+	return r.listSummaries(`
+		WHERE r.organization_id = $1
+		ORDER BY r.created_at DESC`,
+		organizationID,
+	)
+}
+
+// listSummaries obtiene los roadmaps con su autor, visitas y valoraciones.
+// filter contiene el WHERE, ORDER BY y LIMIT de la consulta.
+func (r *roadmapRepository) listSummaries(filter string, args ...any) ([]models.RoadmapSummary, error) {
+	rows, err := r.q.Query(`
+		SELECT r.id, r.title, COALESCE(r.description, ''), COALESCE(r.category, ''), r.author_id,
+			   r.is_public, r.forked_from, r.forked_at, r.organization_id, r.version, r.created_at, r.updated_at,
+			   u.id, u.username, COALESCE(u.avatar_url, ''),
+			   COALESCE(v.views_count, 0) as views_count,
+			   COALESCE(rv.avg_rating, 0) as avg_rating,
+			   COALESCE(rv.reviews_count, 0) as reviews_count
+		FROM roadmaps r
+		JOIN users u ON r.author_id = u.id
+		LEFT JOIN (
+			SELECT roadmap_id, COUNT(*) as views_count
+			FROM roadmap_views
+			GROUP BY roadmap_id
+		) v ON r.id = v.roadmap_id
+		LEFT JOIN (
+			SELECT roadmap_id,
+				   AVG(rating) as avg_rating,
+				   COUNT(*) as reviews_count
+			FROM reviews
+			GROUP BY roadmap_id
+		) rv ON r.id = rv.roadmap_id
+		`+filter,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []models.RoadmapSummary{}
+	for rows.Next() {
+		var s models.RoadmapSummary
+		err := rows.Scan(
+			&s.ID, &s.Title, &s.Description, &s.Category, &s.AuthorID,
+			&s.IsPublic, &s.ForkedFrom, &s.ForkedAt, &s.OrganizationID, &s.Version, &s.CreatedAt, &s.UpdatedAt,
+			&s.Author.ID, &s.Author.Username, &s.Author.AvatarURL,
+			&s.Views, &s.AvgRating, &s.ReviewCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+
+	return summaries, rows.Err()
+}
+
+func (r *roadmapRepository) GetStats(id models.ID) (models.RoadmapStats, error) {
+	var stats models.RoadmapStats
+	err := r.q.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM roadmap_views WHERE roadmap_id = $1),
+			(SELECT COUNT(*) FROM roadmaps WHERE forked_from = $1 AND is_public = true),
+			(SELECT COUNT(*) FROM roadmap_likes WHERE roadmap_id = $1)`,
+		id,
+	).Scan(&stats.Views, &stats.Forks, &stats.Favorites)
+	return stats, mapError(err)
+}
+
+func (r *roadmapRepository) GetForkBase(id models.ID) (*models.Snapshot, error) {
+	var raw []byte
+	err := r.q.QueryRow(`SELECT snapshot FROM roadmap_fork_bases WHERE roadmap_id = $1`, id).Scan(&raw)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var base models.Snapshot
+	if err := json.Unmarshal(raw, &base); err != nil {
+		return nil, err
+	}
+	return &base, nil
+}
+
+func (r *roadmapRepository) SaveForkBase(id models.ID, base *models.Snapshot) error {
+	raw, err := json.Marshal(base)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.q.Exec(`
+		INSERT INTO roadmap_fork_bases (roadmap_id, snapshot)
+		VALUES ($1, $2)
+		ON CONFLICT (roadmap_id) DO UPDATE
+		SET snapshot = EXCLUDED.snapshot, updated_at = CURRENT_TIMESTAMP`,
+		id, raw,
+	)
+	return mapError(err)
+}
+
+func (r *roadmapRepository) ListCategories() ([]models.Facet, error) {
+	return r.listFacets(`
+		SELECT c.id, c.name, COUNT(r.id) as count
+		FROM categories c
+		LEFT JOIN roadmap_categories rc ON c.id = rc.category_id
+		LEFT JOIN roadmaps r ON rc.roadmap_id = r.id AND r.is_public = true
+		GROUP BY c.id, c.name
+		ORDER BY count DESC
+	`)
+}
+
+func (r *roadmapRepository) ListTags() ([]models.Facet, error) {
+	return r.listFacets(`
+		SELECT t.id, t.name, COUNT(r.id) as count
+		FROM tags t
+		LEFT JOIN roadmap_tags rt ON t.id = rt.tag_id
+		LEFT JOIN roadmaps r ON rt.roadmap_id = r.id AND r.is_public = true
+		GROUP BY t.id, t.name
+		ORDER BY count DESC
+	`)
+}
+
+func (r *roadmapRepository) listFacets(query string) ([]models.Facet, error) {
+	rows, err := r.q.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var facets []models.Facet
+	for rows.Next() {
+		var f models.Facet
+		if err := rows.Scan(&f.ID, &f.Name, &f.Count); err != nil {
+			return nil, err
+		}
+		facets = append(facets, f)
+	}
+	return facets, rows.Err()
+}
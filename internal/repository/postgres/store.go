@@ -0,0 +1,103 @@
+// Package postgres implementa los repositorios sobre PostgreSQL
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"Gin/internal/database"
+	"Gin/internal/repository"
+	"github.com/lib/pq"
+)
+
+// queryer agrupa los métodos comunes a *sql.DB y *sql.Tx
+type queryer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// Store implementa repository.Store sobre una conexión o una transacción
+type Store struct {
+	db   *database.DB
+	q    queryer
+	inTx bool
+}
+
+// NewStore crea un Store sobre la base de datos indicada
+func NewStore(db *database.DB) *Store {
+	return &Store{db: db, q: db.GetDB()}
+}
+
+func (s *Store) Users() repository.UserRepository             { return &userRepository{q: s.q} }
+func (s *Store) Roadmaps() repository.RoadmapRepository       { return &roadmapRepository{q: s.q} }
+func (s *Store) Nodes() repository.NodeRepository             { return &nodeRepository{q: s.q} }
+func (s *Store) Connections() repository.ConnectionRepository { return &connectionRepository{q: s.q} }
+func (s *Store) Resources() repository.ResourceRepository     { return &resourceRepository{q: s.q} }
+func (s *Store) Progress() repository.ProgressRepository      { return &progressRepository{q: s.q} }
+func (s *Store) Reviews() repository.ReviewRepository         { return &reviewRepository{q: s.q} }
+func (s *Store) Proposals() repository.ProposalRepository     { return &proposalRepository{q: s.q} }
+func (s *Store) Revisions() repository.RevisionRepository     { return &revisionRepository{q: s.q} }
+func (s *Store) Collaborators() repository.CollaboratorRepository {
+	return &collaboratorRepository{q: s.q}
+}
+func (s *Store) Organizations() repository.OrganizationRepository {
+	return &organizationRepository{q: s.q}
+}
+func (s *Store) Assignments() repository.AssignmentRepository {
+	return &assignmentRepository{q: s.q}
+}
+
+// Transaction ejecuta fn dentro de una transacción de la base de datos
+func (s *Store) Transaction(fn func(tx repository.Store) error) error {
+	if s.inTx {
+		return fn(s)
+	}
+	return s.db.Transaction(func(tx *sql.Tx) error {
+		return fn(&Store{db: s.db, q: tx, inTx: true})
+	})
+}
+
+// Close cierra la conexión con la base de datos
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// mapError traduce los errores del driver a los errores del repositorio
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return repository.ErrConflict
+	}
+	return err
+}
+
+// expectRows devuelve ErrNotFound si la sentencia no afectó a ninguna fila
+func expectRows(result sql.Result, err error) error {
+	if err != nil {
+		return mapError(err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// now devuelve t o, si es cero, la hora actual
+func now(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"Gin/internal/models"
+)
+
+type userRepository struct {
+	q queryer
+}
+
+const userColumns = `id, username, email, COALESCE(password_hash, ''), COALESCE(google_id, ''),
+	COALESCE(avatar_url, ''), COALESCE(bio, ''), created_at, updated_at`
+
+func scanUser(row interface{ Scan(...any) error }) (*models.User, error) {
+	var user models.User
+	err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.GoogleID,
+		&user.AvatarURL, &user.Bio, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	err := r.q.QueryRow(`
+		INSERT INTO users (username, email, password_hash, google_id, avatar_url, bio)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''))
+		RETURNING id, created_at, updated_at`,
+		user.Username, user.Email, user.PasswordHash, user.GoogleID, user.AvatarURL, user.Bio,
+	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	return mapError(err)
+}
+
+func (r *userRepository) GetByID(id models.ID) (*models.User, error) {
+	return scanUser(r.q.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+}
+
+func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+	return scanUser(r.q.QueryRow(`SELECT `+userColumns+` FROM users WHERE email = $1`, email))
+}
+
+func (r *userRepository) GetByUsername(username string) (*models.User, error) {
+	return scanUser(r.q.QueryRow(`SELECT `+userColumns+` FROM users WHERE username = $1`, username))
+}
+
+func (r *userRepository) FindForGoogle(googleID, email string) (*models.User, error) {
+	return scanUser(r.q.QueryRow(`
+		SELECT `+userColumns+`
+		FROM users
+		WHERE google_id = $1 OR email = $2
+		ORDER BY google_id = $1 DESC
+		LIMIT 1`,
+		googleID, email,
+	))
+}
@@ -0,0 +1,228 @@
+// Package repository define el acceso a datos de la aplicación. Los handlers
+// dependen únicamente de estas interfaces; postgres y memory las implementan.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"Gin/internal/models"
+)
+
+var (
+	ErrNotFound = errors.New("registro no encontrado")
+	ErrConflict = errors.New("el registro ya existe")
+	// ErrVersionConflict indica que el registro cambió desde que se leyó
+	ErrVersionConflict = errors.New("el registro fue modificado por otra operación")
+)
+
+// Store da acceso a todos los repositorios
+type Store interface {
+	Users() UserRepository
+	Roadmaps() RoadmapRepository
+	Nodes() NodeRepository
+	Connections() ConnectionRepository
+	Resources() ResourceRepository
+	Progress() ProgressRepository
+	Reviews() ReviewRepository
+	Proposals() ProposalRepository
+	Revisions() RevisionRepository
+	Collaborators() CollaboratorRepository
+	Organizations() OrganizationRepository
+	Assignments() AssignmentRepository
+
+	// Transaction ejecuta fn con un Store transaccional. Si fn devuelve un
+	// error (o entra en pánico) no se aplica ninguno de sus cambios. Dentro
+	// de una transacción, Transaction ejecuta fn en la misma transacción.
+	Transaction(fn func(tx Store) error) error
+
+	// Close libera los recursos del Store
+	Close() error
+}
+
+// UserRepository gestiona los usuarios
+type UserRepository interface {
+	// Create asigna ID y fechas al usuario. Devuelve ErrConflict si el
+	// username, el email o la cuenta de Google ya existen.
+	Create(user *models.User) error
+	GetByID(id models.ID) (*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	GetByUsername(username string) (*models.User, error)
+	// FindForGoogle busca el usuario vinculado a la cuenta de Google o, si no
+	// existe, el que tenga el mismo email
+	FindForGoogle(googleID, email string) (*models.User, error)
+}
+
+// RoadmapRepository gestiona los roadmaps
+type RoadmapRepository interface {
+	Create(roadmap *models.Roadmap) error
+	GetByID(id models.ID) (*models.Roadmap, error)
+	// Update guarda los datos del roadmap y avanza su versión
+	Update(roadmap *models.Roadmap) error
+	// Lock bloquea el roadmap hasta el final de la transacción y devuelve su
+	// versión, para comprobarla sin que otra transacción la cambie entretanto
+	Lock(id models.ID) (int, error)
+	// Touch avanza la versión del roadmap tras un cambio en su contenido y
+	// devuelve la nueva
+	Touch(id models.ID, updatedAt time.Time) (int, error)
+	// Delete elimina el roadmap junto con sus nodos, conexiones, recursos,
+	// progreso, reseñas, visitas, propuestas, revisiones, colaboradores y
+	// asignaciones
+	Delete(id models.ID) error
+	ListPublic(limit int) ([]models.RoadmapSummary, error)
+	// ListForks devuelve los forks directos del roadmap, públicos o no
+	ListForks(id models.ID) ([]models.RoadmapSummary, error)
+	// ListByOrganization devuelve los roadmaps de la organización, públicos o no
+	ListByOrganization(organizationID models.ID) ([]models.RoadmapSummary, error)
+	GetStats(id models.ID) (models.RoadmapStats, error)
+	// GetForkBase devuelve el contenido del original con el que el fork se
+	// sincronizó por última vez, o ErrNotFound si no hay ninguno
+	GetForkBase(id models.ID) (*models.Snapshot, error)
+	SaveForkBase(id models.ID, base *models.Snapshot) error
+	ListCategories() ([]models.Facet, error)
+	ListTags() ([]models.Facet, error)
+}
+
+// NodeRepository gestiona los nodos de los roadmaps. Todas las operaciones
+// están acotadas al roadmap indicado. Al igual que en conexiones y recursos,
+// Create asigna el ID y usa la hora actual si las fechas son cero.
+type NodeRepository interface {
+	Create(node *models.Node) error
+	GetByID(roadmapID, nodeID models.ID) (*models.Node, error)
+	ListByRoadmap(roadmapID models.ID) ([]models.Node, error)
+	// Update guarda el nodo solo si su versión sigue siendo node.Version y la
+	// avanza; si no, devuelve ErrVersionConflict
+	Update(node *models.Node) error
+	// UpdatePosition mueve el nodo y devuelve su nueva versión. Con version
+	// distinto de cero se comporta como Update ante versiones distintas.
+	UpdatePosition(roadmapID, nodeID models.ID, position models.Position, version int, updatedAt time.Time) (int, error)
+	// Delete elimina el nodo junto con sus conexiones, recursos y progreso
+	Delete(roadmapID, nodeID models.ID) error
+}
+
+// ConnectionRepository gestiona las conexiones entre nodos
+type ConnectionRepository interface {
+	// Create devuelve ErrConflict si ya existe una conexión entre los nodos
+	Create(conn *models.Connection) error
+	GetByID(roadmapID, connID models.ID) (*models.Connection, error)
+	ListByRoadmap(roadmapID models.ID) ([]models.Connection, error)
+	Exists(roadmapID, fromNodeID, toNodeID models.ID) (bool, error)
+	Update(conn *models.Connection) error
+	Delete(roadmapID, connID models.ID) error
+}
+
+// ResourceRepository gestiona los recursos de los nodos
+type ResourceRepository interface {
+	Create(resource *models.Resource) error
+	GetByID(resourceID models.ID) (*models.Resource, error)
+	ListByNode(nodeID models.ID) ([]models.Resource, error)
+	ListByRoadmap(roadmapID models.ID) ([]models.Resource, error)
+	Update(resource *models.Resource) error
+	Delete(resourceID models.ID) error
+}
+
+// ProgressRepository gestiona el progreso de los usuarios en los nodos
+type ProgressRepository interface {
+	// Upsert crea o actualiza el progreso del usuario en el nodo
+	Upsert(progress *models.Progress) error
+	Get(userID, nodeID models.ID) (*models.Progress, error)
+	ListByRoadmap(userID, roadmapID models.ID) ([]models.Progress, error)
+}
+
+// ReviewRepository gestiona las reseñas de los roadmaps
+type ReviewRepository interface {
+	// Create devuelve ErrConflict si el usuario ya reseñó el roadmap
+	Create(review *models.Review) error
+	ListByRoadmap(roadmapID models.ID) ([]models.Review, error)
+}
+
+// ProposalRepository gestiona las propuestas de cambios de los roadmaps
+type ProposalRepository interface {
+	Create(proposal *models.Proposal) error
+	GetByID(id models.ID) (*models.Proposal, error)
+	// ListByRoadmap y ListByAuthor devuelven las propuestas más recientes primero
+	ListByRoadmap(roadmapID models.ID) ([]models.Proposal, error)
+	ListByAuthor(authorID models.ID) ([]models.Proposal, error)
+	// Review guarda el estado, el revisor, el comentario y la fecha de revisión
+	Review(proposal *models.Proposal) error
+}
+
+// RevisionRepository gestiona el historial de revisiones de los roadmaps
+type RevisionRepository interface {
+	// Create asigna a la revisión el siguiente número del roadmap
+	Create(revision *models.Revision) error
+	GetByNumber(roadmapID models.ID, number int) (*models.Revision, error)
+	// GetLatest devuelve la última revisión, o ErrNotFound si no hay ninguna
+	GetLatest(roadmapID models.ID) (*models.Revision, error)
+	// ListByRoadmap devuelve las revisiones sin su contenido, la más reciente primero
+	ListByRoadmap(roadmapID models.ID) ([]models.Revision, error)
+}
+
+// CollaboratorRepository gestiona los colaboradores de los roadmaps. Los
+// colaboradores se devuelven con el username del usuario.
+type CollaboratorRepository interface {
+	// Create devuelve ErrConflict si el usuario ya está invitado al roadmap
+	Create(collaborator *models.Collaborator) error
+	GetByID(id models.ID) (*models.Collaborator, error)
+	// Get devuelve el colaborador del roadmap para el usuario, o ErrNotFound
+	Get(roadmapID, userID models.ID) (*models.Collaborator, error)
+	// ListByRoadmap devuelve los colaboradores por orden de invitación
+	ListByRoadmap(roadmapID models.ID) ([]models.Collaborator, error)
+	// ListByUser devuelve las invitaciones del usuario con el estado indicado,
+	// la más reciente primero
+	ListByUser(userID models.ID, status models.CollaboratorStatus) ([]models.Collaborator, error)
+	// Update guarda el rol, el estado y la fecha de aceptación
+	Update(collaborator *models.Collaborator) error
+	Delete(id models.ID) error
+}
+
+// OrganizationRepository gestiona las organizaciones y sus miembros. Los
+// miembros se devuelven con el username del usuario.
+type OrganizationRepository interface {
+	// Create devuelve ErrConflict si el slug ya existe
+	Create(organization *models.Organization) error
+	GetByID(id models.ID) (*models.Organization, error)
+	// ListByUser devuelve las organizaciones del usuario por nombre, con su rol
+	ListByUser(userID models.ID) ([]models.Organization, error)
+	// Update guarda el nombre, el slug y la descripción. Devuelve ErrConflict
+	// si el slug ya existe.
+	Update(organization *models.Organization) error
+	// Delete elimina la organización y sus miembros; sus roadmaps se
+	// conservan pero dejan de pertenecer a ella
+	Delete(id models.ID) error
+
+	// AddMember devuelve ErrConflict si el usuario ya es miembro
+	AddMember(member *models.OrganizationMember) error
+	// GetMember devuelve el miembro de la organización, o ErrNotFound
+	GetMember(organizationID, userID models.ID) (*models.OrganizationMember, error)
+	// ListMembers devuelve los miembros por orden de incorporación
+	ListMembers(organizationID models.ID) ([]models.OrganizationMember, error)
+	UpdateMember(member *models.OrganizationMember) error
+	RemoveMember(organizationID, userID models.ID) error
+
+	// ListProgress devuelve el progreso de cada miembro en cada roadmap de la
+	// organización, incluidos los que aún no han empezado
+	ListProgress(organizationID models.ID) ([]models.MemberProgress, error)
+}
+
+// AssignmentRepository gestiona las asignaciones de roadmaps y sus asignados.
+// Create y Update guardan también los nodos de la asignación, por lo que
+// deben ejecutarse dentro de una transacción.
+type AssignmentRepository interface {
+	Create(assignment *models.Assignment) error
+	GetByID(id models.ID) (*models.Assignment, error)
+	// ListByRoadmap y ListByUser devuelven las asignaciones por fecha límite
+	ListByRoadmap(roadmapID models.ID) ([]models.Assignment, error)
+	// ListByUser devuelve las asignaciones en las que el usuario es asignado
+	ListByUser(userID models.ID) ([]models.Assignment, error)
+	// Update guarda el título, la descripción, los nodos y la fecha límite
+	Update(assignment *models.Assignment) error
+	Delete(id models.ID) error
+
+	// AddAssignee no hace nada si el usuario ya está asignado
+	AddAssignee(assignmentID, userID models.ID) error
+	RemoveAssignee(assignmentID, userID models.ID) error
+	// ListAssignees devuelve los asignados por orden de asignación con su
+	// progreso en los nodos de la asignación, sin evaluar su estado
+	ListAssignees(assignmentID models.ID) ([]models.Assignee, error)
+}
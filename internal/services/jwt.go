@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"Gin/internal/models"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -14,7 +15,7 @@ var (
 )
 
 type JWTClaims struct {
-	UserID int64 `json:"user_id"`
+	UserID models.ID `json:"user_id"`
 	jwt.RegisteredClaims
 }
 
@@ -29,7 +30,7 @@ func NewJWTService() *JWTService {
 }
 
 // GenerateToken genera un nuevo token JWT para el usuario
-func (s *JWTService) GenerateToken(userID int64) (string, error) {
+func (s *JWTService) GenerateToken(userID models.ID) (string, error) {
 	claims := JWTClaims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -44,7 +45,7 @@ func (s *JWTService) GenerateToken(userID int64) (string, error) {
 }
 
 // ValidateToken valida un token JWT y retorna el user_id
-func (s *JWTService) ValidateToken(tokenString string) (int64, error) {
+func (s *JWTService) ValidateToken(tokenString string) (models.ID, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, ErrInvalidToken
@@ -54,14 +55,14 @@ func (s *JWTService) ValidateToken(tokenString string) (int64, error) {
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return 0, ErrExpiredToken
+			return "", ErrExpiredToken
 		}
-		return 0, ErrInvalidToken
+		return "", ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(*JWTClaims)
-	if !ok || !token.Valid {
-		return 0, ErrInvalidToken
+	if !ok || !token.Valid || claims.UserID.IsZero() {
+		return "", ErrInvalidToken
 	}
 
 	return claims.UserID, nil
@@ -1,165 +0,0 @@
--- Extensión para generar UUIDs
-CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-
--- Tabla de usuarios
-CREATE TABLE users (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    username VARCHAR(30) NOT NULL UNIQUE,
-    email VARCHAR(255) NOT NULL UNIQUE,
-    password_hash VARCHAR(255),
-    google_id VARCHAR(255) UNIQUE,
-    avatar_url TEXT,
-    bio TEXT,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    last_login_at TIMESTAMP WITH TIME ZONE,
-    is_active BOOLEAN DEFAULT true,
-    CONSTRAINT check_auth_method CHECK (
-        (password_hash IS NOT NULL AND google_id IS NULL) OR
-        (password_hash IS NULL AND google_id IS NOT NULL)
-    )
-);
-
--- Tabla de roadmaps
-CREATE TABLE roadmaps (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    title VARCHAR(255) NOT NULL,
-    description TEXT,
-    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    category VARCHAR(50),
-    is_public BOOLEAN DEFAULT false,
-    likes_count INTEGER DEFAULT 0,
-    views_count INTEGER DEFAULT 0,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    published_at TIMESTAMP WITH TIME ZONE,
-    deleted_at TIMESTAMP WITH TIME ZONE
-);
-
--- Tabla de nodos del roadmap
-CREATE TABLE roadmap_nodes (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
-    title VARCHAR(255) NOT NULL,
-    description TEXT,
-    type VARCHAR(50) NOT NULL,
-    position_x FLOAT NOT NULL,
-    position_y FLOAT NOT NULL,
-    color VARCHAR(7),
-    status VARCHAR(20) DEFAULT 'pending',
-    order_index INTEGER,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
--- Tabla de conexiones entre nodos
-CREATE TABLE node_connections (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
-    source_node_id UUID NOT NULL REFERENCES roadmap_nodes(id) ON DELETE CASCADE,
-    target_node_id UUID NOT NULL REFERENCES roadmap_nodes(id) ON DELETE CASCADE,
-    label VARCHAR(255),
-    type VARCHAR(50) DEFAULT 'default',
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(roadmap_id, source_node_id, target_node_id)
-);
-
--- Tabla de recursos asociados a nodos
-CREATE TABLE node_resources (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    node_id UUID NOT NULL REFERENCES roadmap_nodes(id) ON DELETE CASCADE,
-    title VARCHAR(255) NOT NULL,
-    url TEXT NOT NULL,
-    description TEXT,
-    type VARCHAR(50) DEFAULT 'link',
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
--- Tabla de progreso de usuarios en roadmaps
-CREATE TABLE user_progress (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    node_id UUID NOT NULL REFERENCES roadmap_nodes(id) ON DELETE CASCADE,
-    status VARCHAR(20) NOT NULL DEFAULT 'not_started',
-    completed_at TIMESTAMP WITH TIME ZONE,
-    notes TEXT,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(user_id, node_id)
-);
-
--- Tabla de likes de roadmaps
-CREATE TABLE roadmap_likes (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(user_id, roadmap_id)
-);
-
--- Tabla de comentarios en roadmaps
-CREATE TABLE roadmap_comments (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
-    content TEXT NOT NULL,
-    parent_id UUID REFERENCES roadmap_comments(id) ON DELETE CASCADE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    deleted_at TIMESTAMP WITH TIME ZONE
-);
-
--- Índices
-CREATE INDEX idx_users_email ON users(email);
-CREATE INDEX idx_users_username ON users(username);
-CREATE INDEX idx_users_google_id ON users(google_id);
-CREATE INDEX idx_roadmaps_user_id ON roadmaps(user_id);
-CREATE INDEX idx_roadmaps_category ON roadmaps(category);
-CREATE INDEX idx_roadmap_nodes_roadmap_id ON roadmap_nodes(roadmap_id);
-CREATE INDEX idx_node_connections_roadmap_id ON node_connections(roadmap_id);
-CREATE INDEX idx_node_resources_node_id ON node_resources(node_id);
-CREATE INDEX idx_user_progress_user_id ON user_progress(user_id);
-CREATE INDEX idx_user_progress_node_id ON user_progress(node_id);
-CREATE INDEX idx_roadmap_likes_roadmap_id ON roadmap_likes(roadmap_id);
-CREATE INDEX idx_roadmap_comments_roadmap_id ON roadmap_comments(roadmap_id);
-CREATE INDEX idx_roadmap_comments_parent_id ON roadmap_comments(parent_id);
-
--- Triggers para actualizar updated_at
-CREATE OR REPLACE FUNCTION update_updated_at_column()
-RETURNS TRIGGER AS $$
-BEGIN
-    NEW.updated_at = CURRENT_TIMESTAMP;
-    RETURN NEW;
-END;
-$$ language 'plpgsql';
-
-CREATE TRIGGER update_users_updated_at
-    BEFORE UPDATE ON users
-    FOR EACH ROW
-    EXECUTE FUNCTION update_updated_at_column();
-
-CREATE TRIGGER update_roadmaps_updated_at
-    BEFORE UPDATE ON roadmaps
-    FOR EACH ROW
-    EXECUTE FUNCTION update_updated_at_column();
-
-CREATE TRIGGER update_roadmap_nodes_updated_at
-    BEFORE UPDATE ON roadmap_nodes
-    FOR EACH ROW
-    EXECUTE FUNCTION update_updated_at_column();
-
-CREATE TRIGGER update_node_resources_updated_at
-    BEFORE UPDATE ON node_resources
-    FOR EACH ROW
-    EXECUTE FUNCTION update_updated_at_column();
-
-CREATE TRIGGER update_user_progress_updated_at
-    BEFORE UPDATE ON user_progress
-    FOR EACH ROW
-    EXECUTE FUNCTION update_updated_at_column();
-
-CREATE TRIGGER update_roadmap_comments_updated_at
-    BEFORE UPDATE ON roadmap_comments
-    FOR EACH ROW
-    EXECUTE FUNCTION update_updated_at_column();
\ No newline at end of file